@@ -47,9 +47,17 @@ func main() {
 	input := flag.String("input", "", "Decrypt path file")
 	cryptType := flag.String("crypt", "", "Crypt file: backup | config")
 	pass := flag.String("password", "", "Password to crypt file (optional)")
-	mode := flag.String("mode", "", "Mode: encrypt | decrypt | recovery")
+	identity := flag.String("identity", "", "Age identity file to decrypt backups (optional)")
+	mode := flag.String("mode", "", "Mode: encrypt | decrypt | recovery | restore | wal")
 	recoveryKey := flag.String("token", "", "Recovery token for recovery")
 	scope := flag.String("scope", "both", "Scope to crypt file: app | device (optional)")
+	from := flag.String("from", "", "Backup to restore: <storage>:<file>")
+	list := flag.Bool("list", false, "List backups recorded in the catalog (filter with -db)")
+	show := flag.String("show", "", "Show a catalog entry by id")
+	reportFormat := flag.String("report", "", "Write a run report: json | junit")
+	reportFile := flag.String("report-file", "", "Path to the run report (default stdout)")
+	daemon := flag.Bool("daemon", false, "Run as a daemon, backing up databases on their schedule")
+	verify := flag.String("verify", "", "Re-hash stored backups: <storage>:<file> | <catalog id>")
 
 	flag.Usage = func() {
 		_, _ = fmt.Fprintf(os.Stderr, "Usage: %s [options]\n\n", os.Args[0])
@@ -67,10 +75,18 @@ func main() {
 		Input:      *input,
 		Crypt:      *cryptType,
 		Password:   *pass,
+		Identity:   *identity,
 		Mode:       *mode,
 		Recovery:   *recoveryKey,
 		AppSecret:  appKey,
 		Scope:      *scope,
+		From:       *from,
+		List:       *list,
+		Show:       *show,
+		Verify:     *verify,
+		Daemon:     *daemon,
+		Report:     *reportFormat,
+		ReportFile: *reportFile,
 	}
 
 	if flags.Crypt != "" {
@@ -3,7 +3,12 @@ package app
 import (
 	"context"
 	"dumper/internal/app/automation"
+	"dumper/internal/app/catalog"
+	"dumper/internal/app/daemon"
 	"dumper/internal/app/manual"
+	"dumper/internal/app/restore"
+	"dumper/internal/app/verify"
+	"dumper/internal/app/wal"
 	_ "dumper/internal/command/database/dynamodb"
 	_ "dumper/internal/command/database/firebird"
 	_ "dumper/internal/command/database/mariadb"
@@ -16,8 +21,12 @@ import (
 	_ "dumper/internal/command/database/sqlite"
 	"dumper/internal/domain/app"
 	cfg "dumper/internal/domain/config"
+	"dumper/internal/metrics"
+	"dumper/internal/report"
 	"dumper/pkg/logging"
+	"fmt"
 	"strings"
+	"time"
 )
 
 type App struct {
@@ -43,10 +52,46 @@ func (a *App) MustRun() error {
 }
 
 func (a *App) Run() error {
+	if a.flags.List || a.flags.Show != "" {
+		logging.L(a.ctx).Info("Running the app in catalog mode")
+		catalogApp := catalog.NewApp(a.ctx, a.cfg, a.flags)
+		return catalogApp.Run()
+	}
+
+	if a.flags.Verify != "" {
+		logging.L(a.ctx).Info("Running the app in verify mode")
+		verifyApp := verify.NewApp(a.ctx, a.cfg, a.flags)
+		return verifyApp.Run()
+	}
+
+	if a.flags.Daemon {
+		logging.L(a.ctx).Info("Running the app in daemon mode")
+		daemonApp := daemon.NewApp(a.ctx, a.cfg, a.flags)
+		return daemonApp.Run()
+	}
+
+	if a.flags.Mode == "restore" {
+		logging.L(a.ctx).Info("Running the app in restore mode")
+		restoreApp := restore.NewApp(a.ctx, a.cfg, a.flags)
+		return restoreApp.Run()
+	}
+
+	if a.flags.Mode == "wal" {
+		logging.L(a.ctx).Info("Running the app in WAL shipping mode")
+		walApp := wal.NewApp(a.ctx, a.cfg, a.flags)
+		return walApp.Run()
+	}
+
+	if a.flags.Report != "" && !report.IsFormat(a.flags.Report) {
+		return fmt.Errorf("unsupported report format '%s', expected json | junit", a.flags.Report)
+	}
+
+	a.readMetrics()
+	defer a.writeMetrics()
+
 	if a.flags.All == false && a.flags.DbNameList != "" {
 		logging.L(a.ctx).Info("Running the app with the parameters specified (db list)")
-		automationDumpApp := automation.NewApp(a.ctx, a.cfg, a.flags)
-		return automationDumpApp.Run()
+		return a.runAutomation()
 	}
 
 	if a.flags.All == true && a.flags.DbNameList == "" {
@@ -57,11 +102,58 @@ func (a *App) Run() error {
 		}
 		a.flags.DbNameList = strings.Join(keys, ",")
 
-		automationDumpApp := automation.NewApp(a.ctx, a.cfg, a.flags)
-		return automationDumpApp.Run()
+		return a.runAutomation()
 	}
 
 	logging.L(a.ctx).Info("Running the app in manual mode with db selection")
 	manualDumpApp := manual.NewApp(a.ctx, a.cfg, a.flags)
 	return manualDumpApp.Run()
 }
+
+func (a *App) runAutomation() error {
+	startedAt := time.Now()
+
+	automationDumpApp := automation.NewApp(a.ctx, a.cfg, a.flags)
+	err := automationDumpApp.Run()
+
+	if a.flags.Report != "" {
+		runReport := report.New(startedAt, automationDumpApp.Results())
+		if reportErr := report.Write(runReport, a.flags.Report, a.flags.ReportFile); reportErr != nil {
+			logging.L(a.ctx).Error("Failed to write run report", logging.ErrAttr(reportErr))
+			if err == nil {
+				err = fmt.Errorf("failed to write run report: %w", reportErr)
+			}
+		}
+	}
+
+	return err
+}
+
+// readMetrics seeds the metrics with the textfile of the previous run, a
+// one-shot run only observes its own databases.
+func (a *App) readMetrics() {
+	m := a.cfg.Settings.Metrics
+	if m == nil || m.Textfile == "" {
+		return
+	}
+
+	if err := metrics.ReadTextfile(m.Textfile); err != nil {
+		logging.L(a.ctx).Warn("Failed to read metrics textfile", logging.ErrAttr(err))
+	}
+}
+
+// writeMetrics exports the metrics of a one-shot run for the node_exporter
+// textfile collector.
+func (a *App) writeMetrics() {
+	m := a.cfg.Settings.Metrics
+	if m == nil || m.Textfile == "" {
+		return
+	}
+
+	if err := metrics.WriteTextfile(m.Textfile); err != nil {
+		logging.L(a.ctx).Warn("Failed to write metrics textfile", logging.ErrAttr(err))
+		return
+	}
+
+	logging.L(a.ctx).Info("Metrics written", logging.StringAttr("path", m.Textfile))
+}
@@ -3,25 +3,32 @@ package automation
 import (
 	"context"
 	"dumper/internal/backup"
+	"dumper/internal/catalog"
 	"dumper/internal/connect"
 	connecterror "dumper/internal/connect/connect-error"
+	"dumper/internal/discover"
 	"dumper/internal/domain/app"
+	backupDomain "dumper/internal/domain/backup"
+	catalogDomain "dumper/internal/domain/catalog"
 	cfg "dumper/internal/domain/config"
 	dbConnect "dumper/internal/domain/config/db-connect"
 	"dumper/internal/domain/config/storage"
-	connectDomain "dumper/internal/domain/connect"
+	"dumper/internal/notify"
 	"dumper/pkg/logging"
 	"dumper/pkg/utils/retry"
 	"errors"
 	"fmt"
 	"strings"
 	"sync"
+	"time"
 )
 
 type Automation struct {
-	ctx context.Context
-	cfg *cfg.Config
-	env *app.Flags
+	ctx     context.Context
+	cfg     *cfg.Config
+	env     *app.Flags
+	mu      sync.Mutex
+	results []catalogDomain.Entry
 }
 
 func NewApp(
@@ -51,6 +58,7 @@ func (m *Automation) Run() error {
 		if !ok {
 			fmt.Printf("Database %s not found\n", dbName)
 			logging.L(m.ctx).Warn("Database not found", logging.StringAttr("name", dbName))
+			m.addResult(failedResult(dbName, "", fmt.Errorf("database %s not found", dbName)))
 			countDBs--
 			continue
 		}
@@ -64,29 +72,39 @@ func (m *Automation) Run() error {
 	}
 
 	wg := &sync.WaitGroup{}
-	errCh := make(chan error, len(dbList))
+	errMu := sync.Mutex{}
+	var errs []error
+	report := func(err error) {
+		errMu.Lock()
+		defer errMu.Unlock()
+		errs = append(errs, err)
+	}
 
 	for _, dbInfoList := range serversDatabases {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			for _, dbConn := range dbInfoList {
+
+			// every database of a server shares one SSH connection
+			srv := dbInfoList[0].Server
+			connectDto := connect.FromServer(srv, m.cfg.Settings)
+			connectApp := connect.NewApp(m.ctx, connectDto)
+			defer connectApp.Close()
+
+			dbInfoList = m.discover(discover.NewApp(m.ctx, m.cfg, connectApp), dbInfoList, report)
+
+			for i, dbConn := range dbInfoList {
 				select {
 				case <-m.ctx.Done():
 					logging.L(m.ctx).Info("Backup cancelled by context")
-					errCh <- fmt.Errorf("backup cancelled for database %s", dbConn.Database.Name)
+					// the databases still queued on this server never run
+					for _, skipped := range dbInfoList[i:] {
+						err := fmt.Errorf("backup cancelled for database %s", skipped.Database.Name)
+						m.addResult(failedResult(skipped.Database.Key, skipped.Server.GetName(), err))
+						report(err)
+					}
 					return
 				default:
-					connectDto := &connectDomain.Connect{
-						Server:       dbConn.Server.Host,
-						Port:         dbConn.Server.GetPort(&m.cfg.Settings.SrvPost),
-						Username:     dbConn.Server.User,
-						Password:     dbConn.Server.GetPassword(&m.cfg.Settings.SSH.Password),
-						PrivateKey:   dbConn.Server.GetPrivateKey(&m.cfg.Settings.SSH.PrivateKey),
-						Passphrase:   dbConn.Server.GetPassphrase(&m.cfg.Settings.SSH.Passphrase),
-						IsPassphrase: dbConn.Server.GetIsPassphrase(*m.cfg.Settings.SSH.IsPassphrase),
-					}
-					connectApp := connect.NewApp(m.ctx, connectDto)
 					backupApp := backup.NewApp(m.ctx, m.cfg, dbConn, connectApp)
 
 					err := retry.WithRetry(
@@ -111,9 +129,18 @@ func (m *Automation) Run() error {
 						},
 					)
 
+					result := backupApp.Result()
+					if result.Status == "" {
+						result = failedResult(dbConn.Database.Key, dbConn.Server.GetName(), err)
+					}
+					m.addResult(result)
+
+					_ = notify.NewApp(m.ctx, m.cfg.Notifications).Send(result)
+
+					// a failed backup does not hold back the next database
+					// on the same server
 					if err != nil {
-						errCh <- fmt.Errorf("backup failed for %s: %w", dbConn.Database.Name, err)
-						return
+						report(fmt.Errorf("backup failed for %s: %w", dbConn.Database.Name, err))
 					}
 				}
 			}
@@ -121,12 +148,9 @@ func (m *Automation) Run() error {
 	}
 
 	wg.Wait()
-	close(errCh)
 
-	for err := range errCh {
-		if err != nil {
-			return err
-		}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
 	}
 
 	logging.L(m.ctx).Info("All requested database backups are done")
@@ -134,6 +158,80 @@ func (m *Automation) Run() error {
 	return nil
 }
 
+// discover replaces the entries with discover enabled by the databases found
+// on the server. A failed listing is reported like a failed backup and the
+// other databases of the server still run.
+func (m *Automation) discover(
+	discoverApp *discover.Discover,
+	list []dbConnect.DBConnect,
+	report func(error),
+) []dbConnect.DBConnect {
+	expanded := make([]dbConnect.DBConnect, 0, len(list))
+
+	for _, dbConn := range list {
+		if !dbConn.Database.IsDiscover() {
+			expanded = append(expanded, dbConn)
+			continue
+		}
+
+		found, err := discoverApp.Databases(dbConn)
+		if err != nil {
+			logging.L(m.ctx).Error(
+				"Failed to discover databases",
+				logging.StringAttr("db", dbConn.Database.Key),
+				logging.ErrAttr(err),
+			)
+			result := failedResult(dbConn.Database.Key, dbConn.Server.GetName(), err)
+			m.addResult(result)
+			_ = notify.NewApp(m.ctx, m.cfg.Notifications).Send(result)
+			report(err)
+			continue
+		}
+
+		if len(found) == 0 {
+			logging.L(m.ctx).Warn("No databases discovered", logging.StringAttr("db", dbConn.Database.Key))
+		}
+
+		expanded = append(expanded, found...)
+	}
+
+	return expanded
+}
+
+// Results returns one entry per requested database once Run has returned.
+func (m *Automation) Results() []catalogDomain.Entry {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]catalogDomain.Entry(nil), m.results...)
+}
+
+func (m *Automation) addResult(entry catalogDomain.Entry) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.results = append(m.results, entry)
+}
+
+// failedResult describes a database that never got to run a backup.
+func failedResult(key, server string, err error) catalogDomain.Entry {
+	now := time.Now()
+	entry := catalogDomain.Entry{
+		Database:   key,
+		Server:     server,
+		StartedAt:  now,
+		FinishedAt: now,
+		Status:     catalog.StatusFailed,
+		Phase:      backupDomain.PhasePrepare,
+	}
+
+	if err != nil {
+		entry.Error = err.Error()
+	}
+
+	return entry
+}
+
 func (m *Automation) prepareDBConnect() map[string]dbConnect.DBConnect {
 	connectDBs := make(map[string]dbConnect.DBConnect, len(m.cfg.Databases))
 	for idx, database := range m.cfg.Databases {
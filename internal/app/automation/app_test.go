@@ -0,0 +1,123 @@
+package automation_test
+
+import (
+	"context"
+	"dumper/internal/app/automation"
+	"dumper/internal/catalog"
+	local_config "dumper/internal/config/local"
+	"dumper/internal/domain/app"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun_ReportsEveryDatabaseOfAServer(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+
+	require.NoError(t, os.WriteFile(path, []byte(`
+settings:
+  ssh:
+    config_file: none
+  location: local-direct
+  dir_dump: `+filepath.Join(dir, "dumps")+`
+  dir_archived: `+filepath.Join(dir, "archived")+`
+  catalog: `+filepath.Join(dir, "catalog.jsonl")+`
+  archive:
+    algo: none
+  storages: [local]
+storages:
+  local:
+    type: local
+    dir: `+filepath.Join(dir, "storage")+`
+servers:
+  prod:
+    host: 10.0.0.12
+    user: root
+    password: secret
+databases:
+  broken:
+    driver: sqlite
+    format: sql
+    server: prod
+    options:
+      source: "false"
+      path: broken.db
+  app:
+    driver: sqlite
+    format: sql
+    server: prod
+    options:
+      source: echo
+      path: app.db
+`), 0600))
+
+	cfg, err := local_config.Load(path, "")
+	require.NoError(t, err)
+
+	automationApp := automation.NewApp(context.Background(), cfg, &app.Flags{DbNameList: "broken,app"})
+	err = automationApp.Run()
+	require.Error(t, err)
+
+	statuses := make(map[string]string)
+	for _, result := range automationApp.Results() {
+		statuses[result.Database] = result.Status
+	}
+	assert.Equal(t, map[string]string{
+		"broken": catalog.StatusFailed,
+		"app":    catalog.StatusSuccess,
+	}, statuses)
+}
+
+func TestRun_CancelledReportsQueuedDatabases(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+
+	require.NoError(t, os.WriteFile(path, []byte(`
+settings:
+  ssh:
+    config_file: none
+  location: local-direct
+  dir_dump: `+filepath.Join(dir, "dumps")+`
+  dir_archived: `+filepath.Join(dir, "archived")+`
+  catalog: `+filepath.Join(dir, "catalog.jsonl")+`
+  storages: [local]
+storages:
+  local:
+    type: local
+    dir: `+filepath.Join(dir, "storage")+`
+servers:
+  prod:
+    host: 10.0.0.12
+    user: root
+    password: secret
+databases:
+  first:
+    driver: sqlite
+    format: sql
+    server: prod
+  second:
+    driver: sqlite
+    format: sql
+    server: prod
+`), 0600))
+
+	cfg, err := local_config.Load(path, "")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	automationApp := automation.NewApp(ctx, cfg, &app.Flags{DbNameList: "first,second"})
+	require.Error(t, automationApp.Run())
+
+	results := automationApp.Results()
+	require.Len(t, results, 2)
+	for _, result := range results {
+		assert.Equal(t, catalog.StatusFailed, result.Status)
+		assert.Contains(t, result.Error, "backup cancelled")
+	}
+}
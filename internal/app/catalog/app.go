@@ -0,0 +1,104 @@
+package catalog
+
+import (
+	"context"
+	"dumper/internal/catalog"
+	"dumper/internal/domain/app"
+	cfg "dumper/internal/domain/config"
+	"dumper/pkg/logging"
+	"dumper/pkg/utils/format"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+)
+
+type Catalog struct {
+	ctx context.Context
+	cfg *cfg.Config
+	env *app.Flags
+}
+
+func NewApp(
+	ctx context.Context,
+	cfg *cfg.Config,
+	env *app.Flags,
+) *Catalog {
+	return &Catalog{
+		ctx: ctx,
+		cfg: cfg,
+		env: env,
+	}
+}
+
+func (c *Catalog) Run() error {
+	if c.cfg.Settings.Catalog == "" {
+		return errors.New("backup catalog is disabled, set settings.catalog")
+	}
+
+	store := catalog.New(c.cfg.Settings.Catalog)
+
+	if c.env.Show != "" {
+		return c.show(store)
+	}
+
+	return c.list(store)
+}
+
+func (c *Catalog) list(store *catalog.Catalog) error {
+	logging.L(c.ctx).Info("Listing backup catalog")
+
+	entries, err := store.List()
+	if err != nil {
+		return err
+	}
+
+	var dbList []string
+	if c.env.DbNameList != "" {
+		dbList = strings.Split(c.env.DbNameList, ",")
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "ID\tDB\tSERVER\tFORMAT\tSIZE\tSTATUS\tSTARTED")
+
+	for _, entry := range entries {
+		if dbList != nil && !slices.Contains(dbList, entry.Database) {
+			continue
+		}
+
+		_, _ = fmt.Fprintf(
+			w,
+			"%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			entry.ID,
+			entry.Database,
+			entry.Server,
+			entry.Format,
+			format.FormatBytes(entry.Size),
+			entry.Status,
+			entry.StartedAt.Local().Format("2006-01-02 15:04:05"),
+		)
+	}
+
+	return w.Flush()
+}
+
+func (c *Catalog) show(store *catalog.Catalog) error {
+	logging.L(c.ctx).Info("Showing backup catalog entry", logging.StringAttr("id", c.env.Show))
+
+	entry, err := store.Get(c.env.Show)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode catalog entry: %v", err)
+	}
+
+	fmt.Println(string(data))
+
+	return nil
+}
@@ -0,0 +1,214 @@
+package daemon
+
+import (
+	"context"
+	"dumper/internal/app/automation"
+	walApp "dumper/internal/app/wal"
+	"dumper/internal/domain/app"
+	cfg "dumper/internal/domain/config"
+	"dumper/internal/metrics"
+	"dumper/pkg/logging"
+	"dumper/pkg/utils/cron"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+type Daemon struct {
+	ctx context.Context
+	cfg *cfg.Config
+	env *app.Flags
+}
+
+type job struct {
+	key      string
+	schedule *cron.Schedule
+	// wal ships the WAL archive of the database instead of backing it up
+	wal bool
+}
+
+func (j job) name() string {
+	if j.wal {
+		return j.key + " (wal)"
+	}
+	return j.key
+}
+
+func NewApp(
+	ctx context.Context,
+	cfg *cfg.Config,
+	env *app.Flags,
+) *Daemon {
+	return &Daemon{
+		ctx: ctx,
+		cfg: cfg,
+		env: env,
+	}
+}
+
+// Run schedules every database that has a cron expression, or only the ones
+// given with -db, and blocks until the context is cancelled. Each database
+// runs in its own loop, so a job is never started again while its previous
+// run is still going.
+func (d *Daemon) Run() error {
+	jobs, err := d.jobs()
+	if err != nil {
+		return err
+	}
+
+	if len(jobs) == 0 {
+		return errors.New("no database has a schedule, set settings.schedule, databases.<key>.schedule or options.wal_schedule")
+	}
+
+	if m := d.cfg.Settings.Metrics; m != nil && m.Textfile != "" {
+		if err := metrics.ReadTextfile(m.Textfile); err != nil {
+			logging.L(d.ctx).Warn("Failed to read metrics textfile", logging.ErrAttr(err))
+		}
+	}
+
+	if m := d.cfg.Settings.Metrics; m != nil && m.Listen != "" {
+		if err := metrics.Serve(d.ctx, m.Listen); err != nil {
+			return fmt.Errorf("failed to start metrics server: %w", err)
+		}
+		fmt.Printf("Metrics available on %s/metrics\n", m.Listen)
+	}
+
+	logging.L(d.ctx).Info("Daemon started", logging.IntAttr("jobs", len(jobs)))
+	fmt.Printf("Daemon started with %d scheduled databases\n", len(jobs))
+
+	wg := &sync.WaitGroup{}
+	for _, j := range jobs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			d.loop(j)
+		}()
+	}
+
+	wg.Wait()
+
+	logging.L(d.ctx).Info("Daemon stopped")
+	fmt.Println("Daemon stopped")
+
+	return nil
+}
+
+func (d *Daemon) jobs() ([]job, error) {
+	var jobs []job
+
+	var only []string
+	if d.env.DbNameList != "" {
+		only = strings.Split(d.env.DbNameList, ",")
+	}
+
+	for key, db := range d.cfg.Databases {
+		if only != nil && !slices.Contains(only, key) {
+			continue
+		}
+
+		if walExpr := db.GetOptions().WalSchedule; walExpr != "" {
+			schedule, err := cron.Parse(walExpr)
+			if err != nil {
+				return nil, fmt.Errorf("database '%s' wal_schedule invalid: %w", key, err)
+			}
+
+			jobs = append(jobs, job{key: key, schedule: schedule, wal: true})
+		}
+
+		expr := db.GetSchedule(d.cfg.Settings.Schedule)
+		if expr == "" {
+			continue
+		}
+
+		schedule, err := cron.Parse(expr)
+		if err != nil {
+			return nil, fmt.Errorf("database '%s' schedule invalid: %w", key, err)
+		}
+
+		jobs = append(jobs, job{key: key, schedule: schedule})
+	}
+
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].name() < jobs[j].name()
+	})
+
+	return jobs, nil
+}
+
+func (d *Daemon) loop(j job) {
+	for {
+		next := j.schedule.Next(time.Now())
+		if next.IsZero() {
+			logging.L(d.ctx).Warn(
+				"Schedule never fires, job disabled",
+				logging.StringAttr("db", j.name()),
+				logging.StringAttr("schedule", j.schedule.String()),
+			)
+			return
+		}
+
+		logging.L(d.ctx).Info(
+			"Next backup scheduled",
+			logging.StringAttr("db", j.name()),
+			logging.StringAttr("at", next.Format(time.DateTime)),
+		)
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-d.ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		d.run(j, next)
+	}
+}
+
+func (d *Daemon) run(j job, scheduledAt time.Time) {
+	logging.L(d.ctx).Info("Scheduled backup started", logging.StringAttr("db", j.name()))
+	fmt.Printf("[%s] Scheduled backup started for %s\n", time.Now().Format(time.DateTime), j.name())
+
+	env := *d.env
+	env.DbNameList = j.key
+	env.All = false
+
+	runJob := automation.NewApp(d.ctx, d.cfg, &env).Run
+	if j.wal {
+		runJob = walApp.NewApp(d.ctx, d.cfg, &env).Run
+	}
+
+	startedAt := time.Now()
+	if err := runJob(); err != nil {
+		logging.L(d.ctx).Error(
+			"Scheduled backup failed",
+			logging.StringAttr("db", j.name()),
+			logging.ErrAttr(err),
+		)
+		fmt.Printf("[%s] Scheduled backup failed for %s: %v\n", time.Now().Format(time.DateTime), j.name(), err)
+	} else {
+		logging.L(d.ctx).Info(
+			"Scheduled backup finished",
+			logging.StringAttr("db", j.name()),
+			logging.StringAttr("time", fmt.Sprintf("%.2f sec", time.Since(startedAt).Seconds())),
+		)
+	}
+
+	if m := d.cfg.Settings.Metrics; m != nil && m.Textfile != "" {
+		if err := metrics.WriteTextfile(m.Textfile); err != nil {
+			logging.L(d.ctx).Warn("Failed to write metrics textfile", logging.ErrAttr(err))
+		}
+	}
+
+	if missed := j.schedule.Next(scheduledAt); !missed.IsZero() && missed.Before(time.Now()) {
+		logging.L(d.ctx).Warn(
+			"Backup overran its schedule, skipped runs while it was in progress",
+			logging.StringAttr("db", j.name()),
+			logging.StringAttr("missed", missed.Format(time.DateTime)),
+		)
+	}
+}
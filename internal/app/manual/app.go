@@ -6,12 +6,13 @@ import (
 	remote "dumper/internal/config/remote"
 	"dumper/internal/connect"
 	connecterror "dumper/internal/connect/connect-error"
+	"dumper/internal/discover"
 	"dumper/internal/domain/app"
 	cfg "dumper/internal/domain/config"
 	dbConnect "dumper/internal/domain/config/db-connect"
 	"dumper/internal/domain/config/server"
 	"dumper/internal/domain/config/storage"
-	connectDomain "dumper/internal/domain/connect"
+	"dumper/internal/notify"
 	_select "dumper/internal/select"
 	t "dumper/internal/temr"
 	"dumper/pkg/logging"
@@ -83,7 +84,10 @@ func (m *Manual) Run() error {
 			return err
 		}
 	} else {
-		dataDBConnect = m.prepareDBConnect()
+		dataDBConnect, err = m.discoverDatabases(srv, serverKey, m.prepareDBConnect())
+		if err != nil {
+			return err
+		}
 	}
 
 	dbList, dbKeys := _select.OptionDataBaseList(dataDBConnect, serverKey)
@@ -101,17 +105,10 @@ func (m *Manual) Run() error {
 
 	logging.L(m.ctx).Info("Selected database", logging.StringAttr("database", dbKey))
 
-	connectDto := &connectDomain.Connect{
-		Server:       dbConn.Server.Host,
-		Username:     dbConn.Server.User,
-		Port:         dbConn.Server.GetPort(&m.cfg.Settings.SrvPost),
-		Password:     dbConn.Server.Password,
-		PrivateKey:   dbConn.Server.GetPrivateKey(&m.cfg.Settings.SSH.PrivateKey),
-		Passphrase:   dbConn.Server.GetPassphrase(&m.cfg.Settings.SSH.Passphrase),
-		IsPassphrase: dbConn.Server.GetIsPassphrase(*m.cfg.Settings.SSH.IsPassphrase),
-	}
+	connectDto := connect.FromServer(dbConn.Server, m.cfg.Settings)
 
 	connectApp := connect.NewApp(m.ctx, connectDto)
+	defer connectApp.Close()
 
 	backupApp := backup.NewApp(m.ctx, m.cfg, dbConn, connectApp)
 
@@ -137,6 +134,8 @@ func (m *Manual) Run() error {
 		},
 	)
 
+	_ = notify.NewApp(m.ctx, m.cfg.Notifications).Send(backupApp.Result())
+
 	if err != nil {
 		return err
 	}
@@ -149,17 +148,7 @@ func (m *Manual) prepareRemoteDatabaseList(
 ) (map[string]dbConnect.DBConnect, error) {
 	logging.L(m.ctx).Info("Prepare connection")
 
-	connectDto := &connectDomain.Connect{
-		Server:       server.Host,
-		Username:     server.User,
-		Port:         server.GetPort(&m.cfg.Settings.SrvPost),
-		Password:     server.GetPassword(&m.cfg.Settings.SSH.Password),
-		PrivateKey:   server.GetPrivateKey(&m.cfg.Settings.SSH.PrivateKey),
-		Passphrase:   server.GetPassphrase(&m.cfg.Settings.SSH.Passphrase),
-		IsPassphrase: server.GetIsPassphrase(*m.cfg.Settings.SSH.IsPassphrase),
-	}
-
-	conn := connect.NewApp(m.ctx, connectDto)
+	conn := m.serverConnect(server)
 
 	if err := runner.RunWithCtx(m.ctx, conn.Connect); err != nil {
 		logging.L(m.ctx).Error(
@@ -191,6 +180,51 @@ func (m *Manual) prepareRemoteDatabaseList(
 	return rmt.Config(), nil
 }
 
+// discoverDatabases replaces the discover entries of the selected server by
+// the databases found on it, so they can be picked like any other.
+func (m *Manual) discoverDatabases(
+	server server.Server,
+	serverKey string,
+	data map[string]dbConnect.DBConnect,
+) (map[string]dbConnect.DBConnect, error) {
+	var entries []string
+	for key, dbConn := range data {
+		if dbConn.Database.Server == serverKey && dbConn.Database.IsDiscover() {
+			entries = append(entries, key)
+		}
+	}
+
+	if len(entries) == 0 {
+		return data, nil
+	}
+
+	conn := m.serverConnect(server)
+	defer conn.Close()
+
+	discoverApp := discover.NewApp(m.ctx, m.cfg, conn)
+
+	for _, key := range entries {
+		found, err := discoverApp.Databases(data[key])
+		if err != nil {
+			logging.L(m.ctx).Error("Failed to discover databases", logging.StringAttr("db", key), logging.ErrAttr(err))
+			return nil, err
+		}
+
+		delete(data, key)
+		for _, dbConn := range found {
+			data[dbConn.Database.Key] = dbConn
+		}
+	}
+
+	return data, nil
+}
+
+func (m *Manual) serverConnect(server server.Server) *connect.Connect {
+	connectDto := connect.FromServer(server, m.cfg.Settings)
+
+	return connect.NewApp(m.ctx, connectDto)
+}
+
 func (m *Manual) prepareDBConnect() map[string]dbConnect.DBConnect {
 	connectDBs := make(map[string]dbConnect.DBConnect, len(m.cfg.Databases))
 	for idx, database := range m.cfg.Databases {
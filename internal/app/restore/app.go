@@ -0,0 +1,93 @@
+package restore
+
+import (
+	"context"
+	"dumper/internal/connect"
+	connecterror "dumper/internal/connect/connect-error"
+	"dumper/internal/domain/app"
+	cfg "dumper/internal/domain/config"
+	dbConnect "dumper/internal/domain/config/db-connect"
+	"dumper/internal/restore"
+	"dumper/pkg/logging"
+	"dumper/pkg/utils/retry"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type Restore struct {
+	ctx context.Context
+	cfg *cfg.Config
+	env *app.Flags
+}
+
+func NewApp(
+	ctx context.Context,
+	cfg *cfg.Config,
+	env *app.Flags,
+) *Restore {
+	return &Restore{
+		ctx: ctx,
+		cfg: cfg,
+		env: env,
+	}
+}
+
+func (m *Restore) Run() error {
+	logging.L(m.ctx).Info("Prepare data for restore")
+
+	if m.env.DbNameList == "" || strings.Contains(m.env.DbNameList, ",") {
+		return errors.New("restore requires a single database key: -db <key>")
+	}
+
+	storageName, fileName, ok := strings.Cut(m.env.From, ":")
+	if !ok || storageName == "" || fileName == "" {
+		return fmt.Errorf("invalid restore source '%s', expected -from <storage>:<file>", m.env.From)
+	}
+
+	database, ok := m.cfg.Databases[m.env.DbNameList]
+	if !ok {
+		logging.L(m.ctx).Warn("Database not found", logging.StringAttr("name", m.env.DbNameList))
+		return fmt.Errorf("database %s not found", m.env.DbNameList)
+	}
+
+	dbConn := dbConnect.DBConnect{
+		Server:   m.cfg.Servers[database.Server],
+		Database: database,
+	}
+
+	connectDto := connect.FromServer(dbConn.Server, m.cfg.Settings)
+	connectApp := connect.NewApp(m.ctx, connectDto)
+
+	restoreApp := restore.NewApp(m.ctx, m.cfg, dbConn, connectApp, storageName, fileName, m.env.Password, m.env.Identity)
+
+	err := retry.WithRetry(
+		m.ctx, m.cfg.Settings.RetryConnect,
+		func() error {
+			return restoreApp.Run()
+		},
+		func(err error) bool {
+			var connErr *connecterror.ConnectError
+			return errors.As(err, &connErr)
+		},
+		func(attempt int, err error) {
+			var connErr *connecterror.ConnectError
+			_ = errors.As(err, &connErr)
+			logging.L(m.ctx).Warn(
+				"Connection error, retrying",
+				logging.StringAttr("db", dbConn.Database.Name),
+				logging.StringAttr("addr", connErr.Addr),
+				logging.IntAttr("attempt", attempt),
+				logging.ErrAttr(err),
+			)
+		},
+	)
+
+	if err != nil {
+		return fmt.Errorf("restore failed for %s: %w", dbConn.Database.Name, err)
+	}
+
+	logging.L(m.ctx).Info("Database restore is done")
+
+	return nil
+}
@@ -0,0 +1,79 @@
+package verify
+
+import (
+	"context"
+	"dumper/internal/catalog"
+	"dumper/internal/domain/app"
+	cfg "dumper/internal/domain/config"
+	"dumper/internal/verify"
+	"dumper/pkg/logging"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type Verify struct {
+	ctx context.Context
+	cfg *cfg.Config
+	env *app.Flags
+}
+
+func NewApp(
+	ctx context.Context,
+	cfg *cfg.Config,
+	env *app.Flags,
+) *Verify {
+	return &Verify{
+		ctx: ctx,
+		cfg: cfg,
+		env: env,
+	}
+}
+
+// Run verifies either a single stored file given as <storage>:<file>, or
+// every stored copy of a catalog entry given by its id.
+func (m *Verify) Run() error {
+	logging.L(m.ctx).Info("Prepare data for verify")
+
+	verifyApp := verify.NewApp(m.ctx, m.cfg)
+
+	if storageName, fileName, ok := strings.Cut(m.env.Verify, ":"); ok {
+		if storageName == "" || fileName == "" {
+			return fmt.Errorf("invalid verify target '%s', expected -verify <storage>:<file> or <catalog id>", m.env.Verify)
+		}
+		return verifyApp.File(storageName, fileName, "")
+	}
+
+	if m.cfg.Settings.Catalog == "" {
+		return errors.New("backup catalog is disabled, use -verify <storage>:<file>")
+	}
+
+	entry, err := catalog.New(m.cfg.Settings.Catalog).Get(m.env.Verify)
+	if err != nil {
+		return err
+	}
+
+	if len(entry.Storages) == 0 {
+		return fmt.Errorf("catalog entry %s has no stored copies", entry.ID)
+	}
+
+	// Each upload records the sum of what its storage received.
+	sums := make(map[string]string, len(entry.Uploads))
+	for _, upload := range entry.Uploads {
+		sums[upload.Storage] = upload.Sha256
+	}
+
+	var errs []error
+	for _, storageName := range entry.Storages {
+		expected, ok := sums[storageName]
+		if !ok {
+			expected = entry.Sha256
+		}
+
+		if err := verifyApp.File(storageName, entry.DumpName, expected); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
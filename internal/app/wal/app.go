@@ -0,0 +1,116 @@
+package wal
+
+import (
+	"context"
+	"dumper/internal/connect"
+	"dumper/internal/domain/app"
+	cfg "dumper/internal/domain/config"
+	dbConnect "dumper/internal/domain/config/db-connect"
+	"dumper/internal/domain/config/storage"
+	"dumper/internal/wal"
+	"dumper/pkg/logging"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type WAL struct {
+	ctx context.Context
+	cfg *cfg.Config
+	env *app.Flags
+}
+
+func NewApp(
+	ctx context.Context,
+	cfg *cfg.Config,
+	env *app.Flags,
+) *WAL {
+	return &WAL{
+		ctx: ctx,
+		cfg: cfg,
+		env: env,
+	}
+}
+
+// Run ships the WAL archive of the databases given with -db, or of every
+// database with a wal_dir when none is given.
+func (m *WAL) Run() error {
+	logging.L(m.ctx).Info("Prepare data for WAL shipping")
+
+	keys, err := m.keys()
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, key := range keys {
+		if err := m.ship(key); err != nil {
+			logging.L(m.ctx).Error("WAL shipping failed", logging.StringAttr("db", key), logging.ErrAttr(err))
+			errs = append(errs, fmt.Errorf("WAL shipping failed for %s: %w", key, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (m *WAL) keys() ([]string, error) {
+	if m.env.DbNameList != "" {
+		keys := strings.Split(m.env.DbNameList, ",")
+		for _, key := range keys {
+			database, ok := m.cfg.Databases[key]
+			if !ok {
+				return nil, fmt.Errorf("database %s not found", key)
+			}
+			if database.GetOptions().WalDir == "" {
+				return nil, fmt.Errorf("database %s has no wal_dir", key)
+			}
+		}
+		return keys, nil
+	}
+
+	var keys []string
+	for key, database := range m.cfg.Databases {
+		if database.GetOptions().WalDir != "" {
+			keys = append(keys, key)
+		}
+	}
+
+	if len(keys) == 0 {
+		return nil, errors.New("no database has a wal_dir, set databases.<key>.options.wal_dir")
+	}
+
+	sort.Strings(keys)
+	return keys, nil
+}
+
+func (m *WAL) ship(key string) error {
+	database := m.cfg.Databases[key]
+
+	storageList := database.GetOptions().WalStorages
+	if len(storageList) == 0 {
+		storageList = database.GetStorages(&m.cfg.Settings.Storages)
+	}
+
+	dbConn := dbConnect.DBConnect{
+		Server:   m.cfg.Servers[database.Server],
+		Database: database,
+		Storages: m.prepareStorages(storageList),
+	}
+
+	connectDto := connect.FromServer(dbConn.Server, m.cfg.Settings)
+	connectApp := connect.NewApp(m.ctx, connectDto)
+	defer connectApp.Close()
+
+	return wal.NewApp(m.ctx, m.cfg, dbConn, connectApp).Run()
+}
+
+func (m *WAL) prepareStorages(list []string) map[string]storage.Storage {
+	storages := make(map[string]storage.Storage, len(list))
+	for _, storageType := range list {
+		st := m.cfg.Storages[storageType]
+		st.PrivateKey = st.GetPrivateKey(m.cfg.Settings.SSH.PrivateKey)
+		storages[storageType] = st
+	}
+	return storages
+}
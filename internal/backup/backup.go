@@ -5,12 +5,19 @@ import (
 	backupLocalDirect "dumper/internal/backup/local-direct"
 	backupLocalSSH "dumper/internal/backup/local-ssh"
 	backupByServer "dumper/internal/backup/server"
+	"dumper/internal/catalog"
 	command "dumper/internal/command/database"
 	"dumper/internal/connect"
 	connecterror "dumper/internal/connect/connect-error"
+	backupDomain "dumper/internal/domain/backup"
+	catalogDomain "dumper/internal/domain/catalog"
+	commandDomain "dumper/internal/domain/command"
 	commandConfig "dumper/internal/domain/command-config"
 	"dumper/internal/domain/config"
+	"dumper/internal/domain/config/archive"
 	dbConnect "dumper/internal/domain/config/db-connect"
+	"dumper/internal/metrics"
+	"dumper/internal/retention"
 	"dumper/internal/shell"
 	"dumper/internal/upload"
 	"dumper/pkg/logging"
@@ -18,6 +25,8 @@ import (
 	"dumper/pkg/utils/runner"
 	"dumper/pkg/utils/template"
 	"fmt"
+	"path/filepath"
+	"time"
 )
 
 type Backup struct {
@@ -26,6 +35,8 @@ type Backup struct {
 	conn      *connect.Connect
 	dbConnect dbConnect.DBConnect
 	cmdConfig *commandConfig.Config
+	result    catalogDomain.Entry
+	warnings  []string
 }
 
 func NewApp(
@@ -43,65 +54,125 @@ func NewApp(
 }
 
 func (b *Backup) Run() error {
+	startedAt := time.Now()
+	b.warnings = nil
+
+	err := b.run()
+	b.result = b.entry(startedAt, err)
+	b.record()
+	metrics.ObserveBackup(b.result)
+
+	return err
+}
+
+// Result describes the last run, it is filled in once Run returns.
+func (b *Backup) Result() catalogDomain.Entry {
+	return b.result
+}
+
+func (b *Backup) run() error {
 
 	b.prepareBackupConfig()
+	b.cmdConfig.Phase = backupDomain.PhasePrepare
 
-	logging.L(b.ctx).Info("Prepare command for dump")
+	var shellConn *connect.Connect
 
-	cmdApp := command.NewApp(b.ctx, b.cmdConfig)
-	cmdDB, err := cmdApp.GetCommand()
+	if b.cfg.Settings.DumpLocation != "local-direct" {
+		logging.L(b.ctx).Info("Prepare connection")
+		b.cmdConfig.Phase = backupDomain.PhaseConnect
 
+		if err := runner.RunWithCtx(b.ctx, b.conn.Connect); err != nil {
+			logging.L(b.ctx).Error(
+				"Error connecting to server",
+				logging.StringAttr("server", b.dbConnect.Server.Host),
+				logging.ErrAttr(err),
+			)
+			return &connecterror.ConnectError{
+				Addr: b.dbConnect.Server.Host,
+				Err:  err,
+			}
+		}
+
+		logging.L(b.ctx).Info("The connection is established")
+		shellConn = b.conn
+	}
+
+	b.cmdConfig.Phase = backupDomain.PhasePrepare
+	logging.L(b.ctx).Info("Prepare command for dump")
+
+	cmdDB, err := b.command()
 	if err != nil {
 		logging.L(b.ctx).Error("failed generate command")
 		return fmt.Errorf("failed generate command: %w", err)
 	}
 
-	b.cmdConfig.Command = cmdDB.Command
-	b.cmdConfig.DumpName = cmdDB.DumpPath
-
-	logging.L(b.ctx).Info("Prepare connection")
-
-	if err := runner.RunWithCtx(b.ctx, b.conn.Connect); err != nil {
-		logging.L(b.ctx).Error(
-			"Error connecting to server",
-			logging.StringAttr("server", b.dbConnect.Server.Host),
-			logging.ErrAttr(err),
-		)
-		return &connecterror.ConnectError{
-			Addr: b.dbConnect.Server.Host,
-			Err:  err,
-		}
+	companions, err := b.companionCommands()
+	if err != nil {
+		logging.L(b.ctx).Error("failed generate companion commands")
+		return fmt.Errorf("failed generate companion commands: %w", err)
 	}
 
-	logging.L(b.ctx).Info("The connection is established")
+	b.cmdConfig.Command = cmdDB.Command
+	b.cmdConfig.Env = cmdDB.Env
+	b.cmdConfig.DumpName = cmdDB.DumpPath
 
-	shellApp := shell.NewApp(b.ctx, b.cmdConfig, b.conn)
+	b.cmdConfig.Phase = backupDomain.PhaseShellBefore
+	shellApp := shell.NewApp(b.ctx, b.cmdConfig, shellConn)
 	if err := runner.RunWithCtx(b.ctx, shellApp.RunScriptBefore); err != nil {
 		logging.L(b.ctx).Error("Error run shell script before start backup")
 		return err
 	}
 
 	logging.L(b.ctx).Info("Preparing for backup creation")
+	b.cmdConfig.Phase = backupDomain.PhaseDump
 
-	if err := runner.RunWithCtx(b.ctx, b.backup); err != nil {
+	if err := runner.RunWithCtx(b.ctx, func() error { return b.backup(b.cmdConfig) }); err != nil {
 		logging.L(b.ctx).Error("Error creating backup database")
 		return err
 	}
 	logging.L(b.ctx).Info("Backup was successfully created and downloaded")
 
+	b.cmdConfig.Phase = backupDomain.PhaseShellAfter
 	if err := runner.RunWithCtx(b.ctx, shellApp.RunScriptAfter); err != nil {
 		logging.L(b.ctx).Error("Error run shell script after finished backup")
 		return err
 	}
 
-	uploadApp := upload.New(b.ctx, b.conn, b.cmdConfig)
-	if err := runner.RunWithCtx(b.ctx, uploadApp.Uploading); err != nil {
-		logging.L(b.ctx).Error("Error upload backup file")
+	if b.cfg.Settings.DumpLocation != "local-ssh" {
+		b.cmdConfig.Phase = backupDomain.PhaseUpload
+		uploadApp := upload.New(b.ctx, b.conn, b.cmdConfig)
+		if err := runner.RunWithCtx(b.ctx, uploadApp.Uploading); err != nil {
+			logging.L(b.ctx).Error("Error upload backup file")
+			return err
+		}
+	}
+
+	if err := b.commit(cmdDB.Commit); err != nil {
 		return err
 	}
 
+	if err := b.companions(companions); err != nil {
+		return err
+	}
+
+	retentionPattern := template.GetTemplatePattern(template.TemplateData{
+		Server:   b.dbConnect.Server.GetName(),
+		Database: b.dbConnect.Database.GetName(),
+		Template: b.cfg.Settings.Template,
+	})
+
+	b.cmdConfig.Phase = backupDomain.PhaseRetention
+	retentionApp := retention.NewApp(b.ctx, b.cmdConfig, retentionPattern)
+	// the backup is stored and cataloged already, a retention failure is
+	// reported with it and does not fail the run
+	if err := runner.RunWithCtx(b.ctx, retentionApp.Run); err != nil {
+		logging.L(b.ctx).Warn("Error applying retention to old backups", logging.ErrAttr(err))
+		b.warnings = append(b.warnings, err.Error())
+	}
+
 	if b.cfg.Settings.DirArchived != "" {
 		logging.L(b.ctx).Info("Search for old backups")
+		b.cmdConfig.Phase = backupDomain.PhaseArchive
 		dbNamePrefix := fmt.Sprintf("%s_%s",
 			b.dbConnect.Server.GetName(),
 			b.dbConnect.Database.GetName(),
@@ -120,16 +191,191 @@ func (b *Backup) Run() error {
 	return nil
 }
 
-func (b *Backup) backup() error {
+func (b *Backup) entry(startedAt time.Time, runErr error) catalogDomain.Entry {
+	entry := catalogDomain.Entry{
+		ID:         catalog.NewID(startedAt),
+		Database:   b.dbConnect.Database.Key,
+		Server:     b.dbConnect.Server.GetName(),
+		Driver:     b.cmdConfig.Database.Driver,
+		Format:     b.cmdConfig.Database.Format,
+		DumpName:   filepath.Base(b.cmdConfig.StoredName()),
+		Path:       b.cmdConfig.DumpName,
+		Size:       b.cmdConfig.FileSize,
+		Sha256:     b.cmdConfig.Checksum,
+		Storages:   b.cmdConfig.Uploaded,
+		Companions: b.cmdConfig.Companions,
+		Uploads:    b.cmdConfig.Uploads,
+		StartedAt:  startedAt,
+		FinishedAt: time.Now(),
+		Status:     catalog.StatusSuccess,
+		Warnings:   b.warnings,
+	}
+
+	if b.cmdConfig.Encrypt.IsEnabled() {
+		entry.Encryption = b.cmdConfig.Encrypt.Type
+	}
+
+	if runErr != nil {
+		entry.Status = catalog.StatusFailed
+		entry.Phase = b.cmdConfig.Phase
+		entry.Error = runErr.Error()
+	}
+
+	return entry
+}
+
+func (b *Backup) record() {
+	if b.cfg.Settings.Catalog == "" {
+		return
+	}
+
+	if err := catalog.New(b.cfg.Settings.Catalog).Append(b.result); err != nil {
+		logging.L(b.ctx).Warn("Failed to write backup catalog", logging.ErrAttr(err))
+		return
+	}
+
+	logging.L(b.ctx).Info("Backup recorded in catalog", logging.StringAttr("id", b.result.ID))
+}
+
+// command generates the dump command. On local-ssh the dump passes through
+// dumper, so when the server lacks the compressor the dump is taken
+// uncompressed and gzipped on the stream instead.
+func (b *Backup) command() (*commandDomain.DBCommand, error) {
+	cmdDB, err := command.NewApp(b.ctx, b.cmdConfig).GetCommand()
+	if err != nil {
+		return nil, err
+	}
+
+	compressor := b.cmdConfig.Archive
+	if b.cfg.Settings.DumpLocation != "local-ssh" || !compressor.IsEnabled() {
+		return cmdDB, nil
+	}
+
+	if _, err := b.conn.RunCommand("command -v " + compressor.Algo); err == nil {
+		return cmdDB, nil
+	}
+
+	b.cmdConfig.Archive = archive.Archive{Algo: archive.AlgoNone}
+	plainDB, err := command.NewApp(b.ctx, b.cmdConfig).GetCommand()
+	if err != nil {
+		return nil, err
+	}
+
+	// the format compresses by itself, nothing runs the compressor
+	if plainDB.DumpPath == cmdDB.DumpPath {
+		b.cmdConfig.Archive = compressor
+		return cmdDB, nil
+	}
+
+	logging.L(b.ctx).Warn(
+		"Compressor not found on the server, the dump is gzipped while streaming",
+		logging.StringAttr("algo", compressor.Algo),
+	)
+
+	b.cmdConfig.Archive = archive.Archive{Algo: archive.AlgoGzip}
+	if compressor.Algo == archive.AlgoGzip || compressor.Algo == archive.AlgoPigz {
+		b.cmdConfig.Archive.Level = min(compressor.Level, 9)
+	}
+	b.cmdConfig.StreamCompress = true
+	plainDB.DumpPath += "." + b.cmdConfig.Archive.Ext()
+
+	return plainDB, nil
+}
+
+// companionCommands generates the extra dumps of the driver. When the main
+// dump is gzipped on the stream, the companions are too.
+func (b *Backup) companionCommands() ([]*commandDomain.DBCommand, error) {
+	if !b.cmdConfig.StreamCompress {
+		return command.NewApp(b.ctx, b.cmdConfig).GetCompanionCommands()
+	}
+
+	compressor := b.cmdConfig.Archive
+	b.cmdConfig.Archive = archive.Archive{Algo: archive.AlgoNone}
+	defer func() { b.cmdConfig.Archive = compressor }()
+
+	list, err := command.NewApp(b.ctx, b.cmdConfig).GetCompanionCommands()
+	for _, cmdDB := range list {
+		cmdDB.DumpPath += "." + compressor.Ext()
+	}
+
+	return list, err
+}
+
+// companions runs every companion through the dump location and the upload
+// of the main dump, a failed companion fails the backup.
+func (b *Backup) companions(list []*commandDomain.DBCommand) error {
+	for _, cmdDB := range list {
+		companion := *b.cmdConfig
+		companion.Command = cmdDB.Command
+		companion.Env = cmdDB.Env
+		companion.DumpName = cmdDB.DumpPath
+		companion.FileSize = 0
+		companion.Checksum = ""
+		companion.FileRemoveList = nil
+		companion.Uploaded = nil
+		companion.Uploads = nil
+
+		logging.L(b.ctx).Info("Creating companion dump", logging.StringAttr("name", cmdDB.DumpPath))
+
+		b.cmdConfig.Phase = backupDomain.PhaseDump
+		if err := runner.RunWithCtx(b.ctx, func() error { return b.backup(&companion) }); err != nil {
+			logging.L(b.ctx).Error("Error creating companion dump", logging.StringAttr("name", cmdDB.DumpPath))
+			return err
+		}
+
+		if b.cfg.Settings.DumpLocation != "local-ssh" {
+			b.cmdConfig.Phase = backupDomain.PhaseUpload
+			uploadApp := upload.New(b.ctx, b.conn, &companion)
+			if err := runner.RunWithCtx(b.ctx, uploadApp.Uploading); err != nil {
+				logging.L(b.ctx).Error("Error upload companion dump", logging.StringAttr("name", cmdDB.DumpPath))
+				return err
+			}
+		}
+
+		b.cmdConfig.Companions = append(b.cmdConfig.Companions, filepath.Base(companion.StoredName()))
+	}
+
+	return nil
+}
+
+// commit runs the commit command of the dump once every storage holds the
+// dump. A dump that is missing somewhere is not committed, so the next one
+// starts again from the same state.
+func (b *Backup) commit(cmd string) error {
+	if cmd == "" {
+		return nil
+	}
+
+	if len(b.cmdConfig.Uploaded) != len(b.cmdConfig.Storages) {
+		return fmt.Errorf("dump reached %d of %d storages, it is not committed",
+			len(b.cmdConfig.Uploaded), len(b.cmdConfig.Storages))
+	}
+
+	logging.L(b.ctx).Info("Committing dump", logging.StringAttr("name", b.cmdConfig.DumpName))
+
+	var err error
+	if b.cfg.Settings.DumpLocation == "local-direct" {
+		_, err = runner.RunLocalCommand(b.ctx, "", cmd)
+	} else {
+		_, err = b.conn.RunCommand(cmd)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to commit dump %s: %w", b.cmdConfig.DumpName, err)
+	}
+
+	return nil
+}
+
+func (b *Backup) backup(cmdConfig *commandConfig.Config) error {
 	switch b.cfg.Settings.DumpLocation {
 	case "server":
-		byServer := backupByServer.NewApp(b.ctx, b.conn, b.cmdConfig)
+		byServer := backupByServer.NewApp(b.ctx, b.conn, cmdConfig)
 		return byServer.Run()
 	case "local-ssh":
-		localSSH := backupLocalSSH.NewApp(b.ctx, b.conn, b.cmdConfig)
+		localSSH := backupLocalSSH.NewApp(b.ctx, b.conn, cmdConfig)
 		return localSSH.Run()
 	case "local-direct":
-		localDirect := backupLocalDirect.NewApp(b.ctx, b.conn, b.cmdConfig)
+		localDirect := backupLocalDirect.NewApp(b.ctx, b.conn, cmdConfig)
 		return localDirect.Run()
 	default:
 		logging.L(b.ctx).Error(
@@ -140,14 +386,6 @@ func (b *Backup) backup() error {
 	}
 }
 
-func (b *Backup) backupByLocalSSH() error {
-	panic("not implement")
-}
-
-func (b *Backup) backupLocalDirect() error {
-	panic("not implement")
-}
-
 func (b *Backup) prepareBackupConfig() {
 	logging.L(b.ctx).Info("Prepare command config")
 
@@ -158,14 +396,26 @@ func (b *Backup) prepareBackupConfig() {
 	}
 	nameFile := template.GetTemplateFileName(dataFormat)
 	dirRemote := b.dbConnect.Database.GetDirRemote(&b.cfg.Settings.DirRemote)
+	dbHost := b.dbConnect.Database.Host
+
+	if b.cfg.Settings.DumpLocation == "local-direct" {
+		dirRemote = b.cfg.Settings.DirDump
+		if absDir, err := filepath.Abs(dirRemote); err == nil {
+			dirRemote = absDir
+		}
+		dbHost = b.dbConnect.Database.GetHost(b.dbConnect.Server.Host)
+	}
+
 	fullPath := template.GetFullPath(dirRemote, nameFile)
 	shellScript := b.dbConnect.Server.GetShell(b.cfg.Settings.Shell)
 
 	b.cmdConfig = &commandConfig.Config{
 		Database: commandConfig.Database{
+			Key:      b.dbConnect.Database.Key,
 			User:     b.dbConnect.Database.User,
 			Password: b.dbConnect.Database.Password,
 			Name:     b.dbConnect.Database.GetName(),
+			Host:     dbHost,
 			Port:     b.dbConnect.Database.GetPort(&b.cfg.Settings.DBPort),
 			Format:   b.dbConnect.Database.GetFormat(&b.cfg.Settings.DumpFormat),
 			Driver:   b.dbConnect.Database.GetDriver(&b.cfg.Settings.Driver),
@@ -180,7 +430,7 @@ func (b *Backup) prepareBackupConfig() {
 		},
 		Storages:            b.dbConnect.Storages,
 		DumpLocation:        b.cfg.Settings.DumpLocation,
-		Archive:             b.dbConnect.Database.IsArchive(*b.cfg.Settings.Archive),
+		Archive:             b.dbConnect.Database.GetArchive(b.cfg.Settings.Archive),
 		DumpDirLocal:        b.cfg.Settings.DirDump,
 		DumpName:            fullPath,
 		DumpNameTemplate:    nameFile,
@@ -188,6 +438,9 @@ func (b *Backup) prepareBackupConfig() {
 		RemoveBackup:        b.dbConnect.Database.GetRemoveDump(b.cfg.Settings.RemoveDump),
 		Encrypt:             b.dbConnect.Database.GetEncrypt(b.cfg.Settings.Encrypt),
 		MaxParallelDownload: b.cfg.Settings.MaxParallelDownload,
+		RetryConnect:        b.cfg.Settings.RetryConnect,
 		Shell:               b.dbConnect.Database.GetShell(&shellScript),
+		Retention:           b.dbConnect.Database.GetRetention(b.cfg.Settings.Retention),
+		Catalog:             b.cfg.Settings.Catalog,
 	}
 }
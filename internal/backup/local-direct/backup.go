@@ -3,7 +3,17 @@ package local_direct
 import (
 	"context"
 	"dumper/internal/connect"
+	backupDomain "dumper/internal/domain/backup"
 	commandConfig "dumper/internal/domain/command-config"
+	"dumper/internal/metrics"
+	"dumper/pkg/logging"
+	"dumper/pkg/utils/checksum"
+	"dumper/pkg/utils/format"
+	"dumper/pkg/utils/runner"
+	"dumper/pkg/utils/spiner"
+	"fmt"
+	"os"
+	"time"
 )
 
 type BackupServer struct {
@@ -25,5 +35,93 @@ func NewApp(
 }
 
 func (b *BackupServer) Run() error {
-	panic("implement me")
+	if err := os.MkdirAll(b.config.DumpDirLocal, 0755); err != nil {
+		logging.L(b.ctx).Error(
+			"error while creating backup directory",
+			logging.StringAttr("dir", b.config.DumpDirLocal),
+			logging.ErrAttr(err),
+		)
+		return fmt.Errorf("failed to create backup directory %s: %v", b.config.DumpDirLocal, err)
+	}
+
+	isRemoveDump := b.config.RemoveBackup
+	var totalSize int64
+
+	if _, err := os.Stat(b.config.DumpName); err == nil {
+		logging.L(b.ctx).Info("Dump already exists", logging.StringAttr("name", b.config.DumpName))
+		fmt.Println("Dump already exists:", b.config.DumpName)
+		isRemoveDump = false
+
+		totalSize, err = b.FileSize()
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("\rFile dump size: %s [%d bytes]\n", format.FormatBytes(totalSize), totalSize)
+	} else {
+		stop := make(chan struct{})
+		dumpCreateTimeNow := time.Now()
+
+		logging.L(b.ctx).Info("File dump name", logging.StringAttr("name", b.config.DumpName))
+		fmt.Println("File dump name:", b.config.DumpName)
+
+		go spiner.Spinner(stop)
+
+		msg, err := runner.RunLocalCommandWithInput(b.ctx, b.config.DumpDirLocal, b.config.Command, b.config.Env, nil)
+		close(stop)
+
+		if err != nil {
+			logging.L(b.ctx).Error(
+				"Failed to create dump",
+				logging.StringAttr("msg", msg),
+				logging.ErrAttr(err),
+			)
+			_ = os.Remove(b.config.DumpName)
+			return fmt.Errorf("failed to create dump: %v", err)
+		}
+
+		elapsed := time.Since(dumpCreateTimeNow)
+		metrics.ObserveDump(b.config.Database.Key, elapsed)
+
+		totalSize, err = b.FileSize()
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("\rDump created successfully in %.2f sec\n", elapsed.Seconds())
+		fmt.Printf("\rFile dump size: %s [%d bytes]\n", format.FormatBytes(totalSize), totalSize)
+
+		logging.L(b.ctx).Info(
+			"The dump was successfully created",
+			logging.StringAttr("time", fmt.Sprintf("%.2f sec", elapsed.Seconds())),
+			logging.Int64Attr("size", totalSize),
+		)
+	}
+
+	var fileList []backupDomain.FileRemoveList
+	fileList = append(fileList, backupDomain.FileRemoveList{
+		Name:     b.config.DumpName,
+		IsRemove: isRemoveDump,
+	})
+
+	sum, err := checksum.File(b.config.DumpName)
+	if err != nil {
+		return fmt.Errorf("failed to get file checksum. path: %s err: %v", b.config.DumpName, err)
+	}
+	logging.L(b.ctx).Info("File dump checksum", logging.StringAttr("sha256", sum))
+
+	b.config.FileRemoveList = fileList
+	b.config.FileSize = totalSize
+	b.config.Checksum = sum
+
+	return nil
+}
+
+func (b *BackupServer) FileSize() (int64, error) {
+	info, err := os.Stat(b.config.DumpName)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get file size. path: %s err: %v", b.config.DumpName, err)
+	}
+
+	return info.Size(), nil
 }
@@ -3,7 +3,13 @@ package local_ssh
 import (
 	"context"
 	"dumper/internal/connect"
+	backupDomain "dumper/internal/domain/backup"
 	commandConfig "dumper/internal/domain/command-config"
+	"dumper/internal/upload"
+	"dumper/pkg/logging"
+	"dumper/pkg/utils/compress"
+	"fmt"
+	"io"
 )
 
 type BackupServer struct {
@@ -25,5 +31,54 @@ func NewApp(
 }
 
 func (b *BackupServer) Run() error {
-	panic("implement me")
+	if b.config.Encrypt.IsEnabled() {
+		logging.L(b.ctx).Info("File dump will be encrypted while streaming")
+	}
+
+	logging.L(b.ctx).Info("File dump name", logging.StringAttr("name", b.config.StoredName()))
+	fmt.Println("File dump name:", b.config.StoredName())
+
+	stream, err := b.conn.StreamCommand(b.config.Command, b.config.Env)
+	if err != nil {
+		logging.L(b.ctx).Error("Failed to start dump", logging.ErrAttr(err))
+		return fmt.Errorf("failed to start dump: %v", err)
+	}
+
+	var dump io.Reader = stream.Stdout
+	if b.config.StreamCompress {
+		gz, err := compress.NewGzip(stream.Stdout, b.config.Archive.Level)
+		if err != nil {
+			_ = stream.Close()
+			return fmt.Errorf("failed to compress dump: %v", err)
+		}
+		defer gz.Close()
+		dump = gz
+	}
+
+	var waitErr error
+	wait := func() error {
+		if waitErr = stream.Wait(); waitErr != nil {
+			return fmt.Errorf("failed to create dump: %v", waitErr)
+		}
+		return nil
+	}
+
+	uploadApp := upload.New(b.ctx, b.conn, b.config)
+	if err := uploadApp.Streaming(dump, wait); err != nil {
+		_ = stream.Close()
+		if waitErr != nil {
+			b.config.Phase = backupDomain.PhaseDump
+			logging.L(b.ctx).Error("Failed to create dump", logging.ErrAttr(waitErr))
+			return fmt.Errorf("failed to create dump: %v", waitErr)
+		}
+		logging.L(b.ctx).Error("Failed to stream dump", logging.ErrAttr(err))
+		return err
+	}
+
+	logging.L(b.ctx).Info(
+		"The dump was successfully streamed",
+		logging.Int64Attr("size", b.config.FileSize),
+	)
+
+	return nil
 }
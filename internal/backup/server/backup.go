@@ -2,12 +2,12 @@ package server
 
 import (
 	"context"
-	encryptCommand "dumper/internal/command/encrypt"
 	"dumper/internal/connect"
 	backupDomain "dumper/internal/domain/backup"
 	commandConfig "dumper/internal/domain/command-config"
-	encryptDomain "dumper/internal/domain/encrypt"
+	"dumper/internal/metrics"
 	"dumper/pkg/logging"
+	"dumper/pkg/utils/checksum"
 	"dumper/pkg/utils/format"
 	"dumper/pkg/utils/spiner"
 	"fmt"
@@ -88,18 +88,21 @@ func (b *BackupServer) Run() error {
 
 		go spiner.Spinner(stop)
 
-		if msg, err := b.conn.RunCommand(b.config.Command); err != nil {
+		msg, err := b.conn.RunCommandWithEnv(b.config.Command, b.config.Env)
+		close(stop)
+
+		if err != nil {
 			logging.L(b.ctx).Error(
 				"Failed to create dump",
 				logging.StringAttr("msg", msg),
 				logging.ErrAttr(err),
 			)
+			b.removePartial()
 			return fmt.Errorf("failed to create dump: %v", err)
 		}
 
-		close(stop)
-
 		elapsed := time.Since(dumpCreateTimeNow)
+		metrics.ObserveDump(b.config.Database.Key, elapsed)
 
 		totalSize, err = b.FileSize()
 		if err != nil {
@@ -123,33 +126,11 @@ func (b *BackupServer) Run() error {
 		IsRemove: isRemoveDump,
 	})
 
-	if b.config.Encrypt.Type != "" && b.config.Encrypt.Password != "" && *b.config.Encrypt.Enabled {
-		encOpts := encryptDomain.Options{
-			FilePath: b.config.DumpName,
-			Password: b.config.Encrypt.Password,
-			Type:     b.config.Encrypt.Type,
-			Crypt:    "encrypt",
-		}
-		encryptApp := encryptCommand.NewApp(&encOpts)
-		encryptCmd, _ := encryptApp.Generate()
-
-		if msg, err := b.conn.RunCommand(encryptCmd.CMD); err != nil {
-			logging.L(b.ctx).Error(
-				"Failed to encrypt dump",
-				logging.StringAttr("msg", msg),
-				logging.ErrAttr(err),
-			)
-			return fmt.Errorf("failed to encrypt dump on server: %v, msg: %s", err, msg)
-		}
-
-		logging.L(b.ctx).Info("File dump encrypted successfully")
-		fmt.Println("File dump encrypted successfully")
-
-		b.config.DumpName = encryptCmd.Name
-		fileList = append(fileList, backupDomain.FileRemoveList{
-			Name:     encryptCmd.Name,
-			IsRemove: true,
-		})
+	if sum, err := b.Checksum(); err == nil {
+		logging.L(b.ctx).Info("File dump checksum", logging.StringAttr("sha256", sum))
+		b.config.Checksum = sum
+	} else {
+		logging.L(b.ctx).Warn("Failed to compute dump checksum, uploads will not be verified", logging.ErrAttr(err))
 	}
 
 	b.config.FileRemoveList = fileList
@@ -158,6 +139,28 @@ func (b *BackupServer) Run() error {
 	return nil
 }
 
+// removePartial drops what a failed dump command left behind, so the next
+// run does not take it for a finished dump.
+func (b *BackupServer) removePartial() {
+	if msg, err := b.conn.RunCommand(fmt.Sprintf("rm -f %s", b.config.DumpName)); err != nil {
+		logging.L(b.ctx).Warn(
+			"Failed to remove partial dump",
+			logging.StringAttr("name", b.config.DumpName),
+			logging.StringAttr("msg", msg),
+			logging.ErrAttr(err),
+		)
+	}
+}
+
+func (b *BackupServer) Checksum() (string, error) {
+	output, err := b.conn.RunCommand(fmt.Sprintf("sha256sum %s", b.config.DumpName))
+	if err != nil {
+		return "", fmt.Errorf("failed to get file checksum. path: %s err: %v", b.config.DumpName, err)
+	}
+
+	return checksum.Parse(output)
+}
+
 func (b *BackupServer) FileSize() (int64, error) {
 	sizeOutput, err := b.conn.RunCommand(fmt.Sprintf("stat -c %%s %s", b.config.DumpName))
 
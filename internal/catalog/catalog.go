@@ -0,0 +1,203 @@
+package catalog
+
+import (
+	"bufio"
+	"crypto/rand"
+	catalogDomain "dumper/internal/domain/catalog"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	StatusSuccess = "success"
+	StatusFailed  = "failed"
+	// StatusDeleted marks a backup retention removed from every storage.
+	StatusDeleted = "deleted"
+)
+
+// appends from concurrent backups must not interleave within one line
+var mu sync.Mutex
+
+type Catalog struct {
+	path string
+}
+
+func New(path string) *Catalog {
+	return &Catalog{path: path}
+}
+
+func NewID(t time.Time) string {
+	buf := make([]byte, 3)
+	_, _ = rand.Read(buf)
+	return fmt.Sprintf("%s-%s", t.UTC().Format("20060102T150405"), hex.EncodeToString(buf))
+}
+
+func (c *Catalog) Append(entry catalogDomain.Entry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode catalog entry: %w", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if dir := filepath.Dir(c.path); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create catalog directory: %w", err)
+		}
+	}
+
+	file, err := os.OpenFile(c.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open catalog: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write catalog entry: %w", err)
+	}
+
+	return nil
+}
+
+func (c *Catalog) List() ([]catalogDomain.Entry, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	return c.list()
+}
+
+func (c *Catalog) list() ([]catalogDomain.Entry, error) {
+	file, err := os.Open(c.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open catalog: %w", err)
+	}
+	defer file.Close()
+
+	var entries []catalogDomain.Entry
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var entry catalogDomain.Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("catalog %s line %d is corrupted: %w", c.path, line, err)
+		}
+		entries = append(entries, entry)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read catalog: %w", err)
+	}
+
+	return entries, nil
+}
+
+func (c *Catalog) Get(id string) (*catalogDomain.Entry, error) {
+	entries, err := c.List()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range entries {
+		if entries[i].ID == id {
+			return &entries[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("backup %s not found in catalog", id)
+}
+
+// Forget records that dumpName was deleted from storageName: the storage
+// leaves the entry and its upload is marked deleted. An entry left without
+// stored copies gets StatusDeleted, so -list, -show and -verify stop offering
+// files that are gone.
+func (c *Catalog) Forget(storageName, dumpName string) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	entries, err := c.list()
+	if err != nil {
+		return err
+	}
+
+	changed := false
+	for i := range entries {
+		entry := &entries[i]
+		if entry.DumpName != dumpName || !slices.Contains(entry.Storages, storageName) {
+			continue
+		}
+
+		entry.Storages = slices.DeleteFunc(entry.Storages, func(name string) bool { return name == storageName })
+		for j := range entry.Uploads {
+			if entry.Uploads[j].Storage == storageName && entry.Uploads[j].Status == StatusSuccess {
+				entry.Uploads[j].Status = StatusDeleted
+			}
+		}
+		if len(entry.Storages) == 0 && entry.Status == StatusSuccess {
+			entry.Status = StatusDeleted
+		}
+		changed = true
+	}
+
+	if !changed {
+		return nil
+	}
+
+	return c.rewrite(entries)
+}
+
+// rewrite replaces the catalog with entries through a temporary file, so a
+// crash never leaves it half written.
+func (c *Catalog) rewrite(entries []catalogDomain.Entry) error {
+	tmp := c.path + ".tmp"
+
+	file, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open catalog: %w", err)
+	}
+
+	writer := bufio.NewWriter(file)
+	for _, entry := range entries {
+		data, err := json.Marshal(entry)
+		if err != nil {
+			_ = file.Close()
+			_ = os.Remove(tmp)
+			return fmt.Errorf("failed to encode catalog entry: %w", err)
+		}
+		_, _ = writer.Write(append(data, '\n'))
+	}
+
+	if err := writer.Flush(); err != nil {
+		_ = file.Close()
+		_ = os.Remove(tmp)
+		return fmt.Errorf("failed to write catalog: %w", err)
+	}
+
+	if err := file.Close(); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("failed to write catalog: %w", err)
+	}
+
+	if err := os.Rename(tmp, c.path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("failed to replace catalog: %w", err)
+	}
+
+	return nil
+}
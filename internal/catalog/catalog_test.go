@@ -0,0 +1,122 @@
+package catalog_test
+
+import (
+	"dumper/internal/catalog"
+	catalogDomain "dumper/internal/domain/catalog"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCatalog_AppendAndList(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "catalog.jsonl")
+	c := catalog.New(path)
+
+	entries, err := c.List()
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	started := time.Date(2025, 6, 30, 12, 0, 0, 0, time.UTC)
+	first := catalogDomain.Entry{
+		ID:        catalog.NewID(started),
+		Database:  "app",
+		Server:    "prod",
+		Driver:    "psql",
+		Format:    "plain",
+		DumpName:  "prod_app_2025.06.30.sql.gz",
+		Size:      1024,
+		Storages:  []string{"s3"},
+		StartedAt: started,
+		Status:    catalog.StatusSuccess,
+	}
+	second := first
+	second.ID = catalog.NewID(started.Add(time.Hour))
+	second.Status = catalog.StatusFailed
+	second.Error = "boom"
+
+	require.NoError(t, c.Append(first))
+	require.NoError(t, c.Append(second))
+
+	entries, err = c.List()
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, first, entries[0])
+	assert.Equal(t, "boom", entries[1].Error)
+
+	got, err := c.Get(second.ID)
+	require.NoError(t, err)
+	assert.Equal(t, catalog.StatusFailed, got.Status)
+
+	_, err = c.Get("missing")
+	require.Error(t, err)
+}
+
+func TestCatalog_ConcurrentAppend(t *testing.T) {
+	c := catalog.New(filepath.Join(t.TempDir(), "catalog.jsonl"))
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, c.Append(catalogDomain.Entry{ID: catalog.NewID(time.Now()), Status: catalog.StatusSuccess}))
+		}()
+	}
+	wg.Wait()
+
+	entries, err := c.List()
+	require.NoError(t, err)
+	assert.Len(t, entries, 20)
+}
+
+func TestCatalog_CorruptedLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "catalog.jsonl")
+	require.NoError(t, os.WriteFile(path, []byte("{\"id\":\"a\"}\nnot json\n"), 0600))
+
+	_, err := catalog.New(path).List()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "line 2")
+}
+
+func TestCatalog_Forget(t *testing.T) {
+	c := catalog.New(filepath.Join(t.TempDir(), "catalog.jsonl"))
+
+	entry := catalogDomain.Entry{
+		ID:       "a",
+		DumpName: "srv_app_1.sql.gz",
+		Storages: []string{"s3", "disk"},
+		Uploads: []catalogDomain.Upload{
+			{Storage: "s3", Status: catalog.StatusSuccess},
+			{Storage: "disk", Status: catalog.StatusSuccess},
+		},
+		Status: catalog.StatusSuccess,
+	}
+	other := catalogDomain.Entry{ID: "b", DumpName: "srv_app_2.sql.gz", Storages: []string{"s3"}, Status: catalog.StatusSuccess}
+
+	require.NoError(t, c.Append(entry))
+	require.NoError(t, c.Append(other))
+
+	require.NoError(t, c.Forget("s3", "srv_app_1.sql.gz"))
+
+	got, err := c.Get("a")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"disk"}, got.Storages)
+	assert.Equal(t, catalog.StatusDeleted, got.Uploads[0].Status)
+	assert.Equal(t, catalog.StatusSuccess, got.Status)
+
+	require.NoError(t, c.Forget("disk", "srv_app_1.sql.gz"))
+
+	got, err = c.Get("a")
+	require.NoError(t, err)
+	assert.Empty(t, got.Storages)
+	assert.Equal(t, catalog.StatusDeleted, got.Status)
+
+	untouched, err := c.Get("b")
+	require.NoError(t, err)
+	assert.Equal(t, other, *untouched)
+}
@@ -10,7 +10,7 @@ import (
 type Generator struct{}
 
 func (g *Generator) Generate(data *cmdCfg.Config) (*commandDomain.DBCommand, error) {
-	ext := ".tar.gz"
+	ext := "." + data.Archive.TarExt()
 
 	baseCmd := fmt.Sprintf("%s snapshot -t %s",
 		data.Database.Options.Source,
@@ -57,7 +57,7 @@ func prepare(
 
 	if len(data.Database.Options.IncTables) > 0 {
 		tables += "--kt-list"
-		archive += fmt.Sprintf("tar -czf %s $(find ./%s", archiveName, data.Database.Name)
+		archive += fmt.Sprintf("%s %s $(find ./%s", data.Archive.Tar(), archiveName, data.Database.Name)
 		for _, table := range data.Database.Options.IncTables {
 			tablesArr = append(tablesArr, fmt.Sprintf("%s.%s", data.Database.Name, table))
 			archiveArr = append(archiveArr, fmt.Sprintf("-path \"./%s/%s-*\"", data.Database.Name, table))
@@ -67,7 +67,7 @@ func prepare(
 		archive += fmt.Sprintf(" \\( %s \\) -path \"*/snapshots/*\")", strings.Join(archiveArr, " -o "))
 	} else {
 		tables = ""
-		archive = fmt.Sprintf("tar -czf %s $(find ./%s -path \"*/snapshots/*\")", archiveName, data.Database.Name)
+		archive = fmt.Sprintf("%s %s $(find ./%s -path \"*/snapshots/*\")", data.Archive.Tar(), archiveName, data.Database.Name)
 	}
 
 	return tables, archive
@@ -4,6 +4,7 @@ import (
 	"dumper/internal/command/database/cassandra"
 	commandDomain "dumper/internal/domain/command"
 	cmdCfg "dumper/internal/domain/command-config"
+	"dumper/internal/domain/config/archive"
 	"dumper/internal/domain/config/option"
 	"testing"
 
@@ -29,6 +30,7 @@ func TestCassandraGenerator_Generate_Variations(t *testing.T) {
 					},
 				},
 				DumpName:         "dump",
+				Archive:          archive.Archive{Algo: archive.AlgoGzip},
 				DumpDirRemote:    "/opt/cassandra/data/data",
 				DumpNameTemplate: "dump",
 			},
@@ -51,6 +53,7 @@ func TestCassandraGenerator_Generate_Variations(t *testing.T) {
 					},
 				},
 				DumpName:         "table_dump",
+				Archive:          archive.Archive{Algo: archive.AlgoGzip},
 				DumpDirRemote:    "/data",
 				DumpNameTemplate: "table_dump",
 			},
@@ -72,6 +75,7 @@ func TestCassandraGenerator_Generate_Variations(t *testing.T) {
 					},
 				},
 				DumpName:         "multi",
+				Archive:          archive.Archive{Algo: archive.AlgoGzip},
 				DumpDirRemote:    "/backup",
 				DumpNameTemplate: "multi",
 			},
@@ -93,6 +97,7 @@ func TestCassandraGenerator_Generate_Variations(t *testing.T) {
 					},
 				},
 				DumpName:         "cleanup",
+				Archive:          archive.Archive{Algo: archive.AlgoGzip},
 				DumpDirRemote:    "/backup",
 				DumpNameTemplate: "cleanup",
 				RemoveBackup:     true,
@@ -113,6 +118,7 @@ func TestCassandraGenerator_Generate_Variations(t *testing.T) {
 					},
 				},
 				DumpName:         "analytics_dump",
+				Archive:          archive.Archive{Algo: archive.AlgoGzip},
 				DumpDirRemote:    "/mnt/backups",
 				DumpNameTemplate: "analytics_dump",
 			},
@@ -156,6 +162,7 @@ func TestCassandraGenerator_CommandIntegrity(t *testing.T) {
 			},
 		},
 		DumpName:         "prod_backup",
+		Archive:          archive.Archive{Algo: archive.AlgoGzip},
 		DumpDirRemote:    "/data",
 		DumpNameTemplate: "prod_backup",
 	}
@@ -20,6 +20,7 @@ import (
 	"dumper/internal/docker"
 	commandDomain "dumper/internal/domain/command"
 	commandConfig "dumper/internal/domain/command-config"
+	"dumper/pkg/utils/mapping"
 	"fmt"
 )
 
@@ -60,6 +61,39 @@ var dataBaseGeneratorList = map[string]Generator{
 	"elastic":    &elasticsearch.Generator{},
 }
 
+// Companion is implemented by generators that dump extra files next to the
+// main dump, such as the PostgreSQL globals. They go through the same dump
+// location and upload as the main dump.
+type Companion interface {
+	Companions(*commandConfig.Config) ([]*commandDomain.DBCommand, error)
+}
+
+// Discoverer is implemented by the drivers able to list the databases of a
+// server, for the entries with discover enabled. The command prints one
+// database per line, the system databases are left out.
+type Discoverer interface {
+	Discover(*commandConfig.Config) (*commandDomain.DBCommand, error)
+}
+
+// Restorer builds the command that loads a dump back into the database.
+// The dump is piped into the command's stdin unless DumpPath is set, in
+// which case the file is placed at DumpPath before the command runs.
+type Restorer interface {
+	Restore(*commandConfig.Config) (*commandDomain.DBCommand, error)
+}
+
+var dataBaseRestorerList = map[string]Restorer{
+	"psql":     &postgres.Restorer{},
+	"mysql":    &mysql.Restorer{},
+	"mongo":    &mongodb.Restorer{},
+	"sqlite":   &sqlite.Restorer{},
+	"mariadb":  &mariadb.Restorer{},
+	"redis":    &redis.Restorer{},
+	"mssql":    &mssql.Restorer{},
+	"neo4j":    &neo4j.Restorer{},
+	"firebird": &firebird.Restorer{},
+}
+
 func (s *Settings) GetCommand() (*commandDomain.DBCommand, error) {
 
 	generator, ok := dataBaseGeneratorList[s.Config.Database.Driver]
@@ -68,6 +102,14 @@ func (s *Settings) GetCommand() (*commandDomain.DBCommand, error) {
 		return nil, fmt.Errorf("unsupported database driver: %s", s.Config.Database.Driver)
 	}
 
+	if s.Config.DumpLocation == "local-ssh" &&
+		!mapping.IsStreamFormatDump(s.Config.Database.Driver, s.Config.Database.Format) {
+		return nil, fmt.Errorf(
+			"database driver %s with format %s can't be streamed over local-ssh",
+			s.Config.Database.Driver, s.Config.Database.Format,
+		)
+	}
+
 	cmdData, err := generator.Generate(s.Config)
 
 	if err != nil {
@@ -81,3 +123,71 @@ func (s *Settings) GetCommand() (*commandDomain.DBCommand, error) {
 
 	return cmdData, nil
 }
+
+func (s *Settings) GetCompanionCommands() ([]*commandDomain.DBCommand, error) {
+
+	generator, ok := dataBaseGeneratorList[s.Config.Database.Driver].(Companion)
+
+	if !ok {
+		return nil, nil
+	}
+
+	list, err := generator.Companions(s.Config)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if *s.Config.Database.Docker.Enabled {
+		for _, cmdData := range list {
+			dockerApp := docker.NewApp(s.ctx, cmdData, s.Config)
+			dockerApp.Prepare()
+		}
+	}
+
+	return list, nil
+}
+
+func (s *Settings) GetDiscoverCommand() (*commandDomain.DBCommand, error) {
+
+	discoverer, ok := dataBaseGeneratorList[s.Config.Database.Driver].(Discoverer)
+
+	if !ok {
+		return nil, fmt.Errorf("discover is not supported for database driver: %s", s.Config.Database.Driver)
+	}
+
+	cmdData, err := discoverer.Discover(s.Config)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if *s.Config.Database.Docker.Enabled {
+		dockerApp := docker.NewApp(s.ctx, cmdData, s.Config)
+		dockerApp.Prepare()
+	}
+
+	return cmdData, nil
+}
+
+func (s *Settings) GetRestoreCommand() (*commandDomain.DBCommand, error) {
+
+	restorer, ok := dataBaseRestorerList[s.Config.Database.Driver]
+
+	if !ok {
+		return nil, fmt.Errorf("restore is not supported for database driver: %s", s.Config.Database.Driver)
+	}
+
+	cmdData, err := restorer.Restore(s.Config)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if *s.Config.Database.Docker.Enabled {
+		dockerApp := docker.NewApp(s.ctx, cmdData, s.Config)
+		dockerApp.Prepare()
+	}
+
+	return cmdData, nil
+}
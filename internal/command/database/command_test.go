@@ -8,6 +8,7 @@ import (
 	"dumper/internal/command/database"
 	commandDomain "dumper/internal/domain/command"
 	cmdCfg "dumper/internal/domain/command-config"
+	"dumper/internal/domain/config/archive"
 	"dumper/internal/domain/config/option"
 
 	"github.com/stretchr/testify/assert"
@@ -38,7 +39,7 @@ func buildConfig(driver string, format ...string) *cmdCfg.Config {
 			},
 		},
 		DumpName:     "dumpfile",
-		Archive:      false,
+		Archive:      archive.Archive{Algo: archive.AlgoNone},
 		DumpLocation: "local",
 		Server:       cmdCfg.Server{Host: "localhost"},
 	}
@@ -116,3 +117,61 @@ func TestSettings_GetCommand_UnsupportedDriver(t *testing.T) {
 	assert.Nil(t, cmd)
 	assert.Contains(t, err.Error(), "unsupported database driver")
 }
+
+func TestSettings_GetCommand_LocalSSHStreamFormats(t *testing.T) {
+	cfg := buildConfig("psql", "plain")
+	cfg.DumpLocation = "local-ssh"
+
+	cmd, err := database.NewApp(context.Background(), cfg).GetCommand()
+	require.NoError(t, err)
+	assert.NotContains(t, cmd.Command, ">")
+
+	cfg = buildConfig("mssql", "bac")
+	cfg.DumpLocation = "local-ssh"
+
+	cmd, err = database.NewApp(context.Background(), cfg).GetCommand()
+	require.Error(t, err)
+	assert.Nil(t, cmd)
+	assert.Contains(t, err.Error(), "local-ssh")
+}
+
+func TestSettings_GetRestoreCommand_AllDrivers(t *testing.T) {
+	tests := []struct {
+		name      string
+		driver    string
+		format    string
+		expectErr bool
+		wantFile  bool
+	}{
+		{"Postgres driver", "psql", "plain", false, false},
+		{"MySQL driver", "mysql", "sql", false, false},
+		{"MongoDB driver", "mongo", "archive", false, false},
+		{"SQLite driver", "sqlite", "sql", false, false},
+		{"MariaDB driver", "mariadb", "sql", false, false},
+		{"Redis driver", "redis", "rdb", false, true},
+		{"MSSQL driver", "mssql", "bac", false, true},
+		{"Neo4j driver", "neo4j", "dump", false, false},
+		{"Firebird driver", "firebird", "fbk", false, false},
+		{"DynamoDB driver", "dynamodb", "json", true, false},
+		{"Unsupported driver", "unknown", "", true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := buildConfig(tt.driver, tt.format)
+
+			cmd, err := database.NewApp(context.Background(), cfg).GetRestoreCommand()
+
+			if tt.expectErr {
+				require.Error(t, err)
+				assert.Nil(t, cmd)
+				return
+			}
+
+			require.NoError(t, err)
+			require.NotNil(t, cmd)
+			assert.NotEmpty(t, cmd.Command)
+			assert.Equal(t, tt.wantFile, cmd.DumpPath != "")
+		})
+	}
+}
@@ -28,10 +28,11 @@ func (g *Generator) Generate(data *commandConfig.Config) (*commandDomain.DBComma
 	)
 
 	baseCmd = fmt.Sprintf("mkdir -p %s && %s", dirToCreateDump, baseCmd)
-	archivePath := backupPath + ".tar.gz"
+	archivePath := backupPath + "." + data.Archive.TarExt()
 
-	baseCmd = fmt.Sprintf("%s && tar -czf %s -C %s %s",
+	baseCmd = fmt.Sprintf("%s && %s %s -C %s %s",
 		baseCmd,
+		data.Archive.Tar(),
 		archivePath,
 		data.DumpDirRemote,
 		data.DumpNameTemplate,
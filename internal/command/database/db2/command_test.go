@@ -4,6 +4,7 @@ import (
 	"dumper/internal/command/database/db2"
 	commandDomain "dumper/internal/domain/command"
 	cmdCfg "dumper/internal/domain/command-config"
+	"dumper/internal/domain/config/archive"
 	"dumper/internal/domain/config/option"
 	"testing"
 
@@ -29,6 +30,7 @@ func TestDB2Generator_Generate_Variations(t *testing.T) {
 					},
 				},
 				DumpName:         "appdb_dump",
+				Archive:          archive.Archive{Algo: archive.AlgoGzip},
 				DumpDirRemote:    "/database/dumps",
 				DumpNameTemplate: "appdb_dump",
 			},
@@ -50,6 +52,7 @@ func TestDB2Generator_Generate_Variations(t *testing.T) {
 					},
 				},
 				DumpName:         "online_dump",
+				Archive:          archive.Archive{Algo: archive.AlgoGzip},
 				DumpDirRemote:    "/database/dumps",
 				DumpNameTemplate: "online_dump",
 			},
@@ -69,6 +72,7 @@ func TestDB2Generator_Generate_Variations(t *testing.T) {
 					},
 				},
 				DumpName:         "cleanup_dump",
+				Archive:          archive.Archive{Algo: archive.AlgoGzip},
 				DumpDirRemote:    "/database/dumps",
 				DumpNameTemplate: "cleanup_dump",
 				RemoveBackup:     true,
@@ -89,6 +93,7 @@ func TestDB2Generator_Generate_Variations(t *testing.T) {
 					},
 				},
 				DumpName:         "testdb_backup",
+				Archive:          archive.Archive{Algo: archive.AlgoGzip},
 				DumpDirRemote:    "/backups",
 				DumpNameTemplate: "testdb_backup",
 			},
@@ -97,6 +102,40 @@ func TestDB2Generator_Generate_Variations(t *testing.T) {
 			},
 			expectedArchive: "testdb_backup.tar.gz",
 		},
+		{
+			name: "xz compressed tarball",
+			config: &cmdCfg.Config{
+				Database: cmdCfg.Database{
+					Name:    "SAMPLE",
+					Options: option.Options{Source: "db2"},
+				},
+				DumpName:         "xz_backup",
+				Archive:          archive.Archive{Algo: archive.AlgoXz, Level: 6, Threads: 2},
+				DumpDirRemote:    "/backups",
+				DumpNameTemplate: "xz_backup",
+			},
+			expectedContains: []string{
+				"tar -I 'xz -6 -T2' -cf xz_backup.tar.xz -C /backups xz_backup",
+			},
+			expectedArchive: "xz_backup.tar.xz",
+		},
+		{
+			name: "uncompressed tarball",
+			config: &cmdCfg.Config{
+				Database: cmdCfg.Database{
+					Name:    "SAMPLE",
+					Options: option.Options{Source: "db2"},
+				},
+				DumpName:         "plain_backup",
+				Archive:          archive.Archive{Algo: archive.AlgoNone},
+				DumpDirRemote:    "/backups",
+				DumpNameTemplate: "plain_backup",
+			},
+			expectedContains: []string{
+				"tar -cf plain_backup.tar -C /backups plain_backup",
+			},
+			expectedArchive: "plain_backup.tar",
+		},
 	}
 
 	gen := db2.Generator{}
@@ -131,6 +170,7 @@ func TestDB2Generator_CommandIntegrity(t *testing.T) {
 			},
 		},
 		DumpName:         "integrity",
+		Archive:          archive.Archive{Algo: archive.AlgoGzip},
 		DumpDirRemote:    "/database/dumps",
 		DumpNameTemplate: "integrity",
 	}
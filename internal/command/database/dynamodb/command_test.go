@@ -4,6 +4,7 @@ import (
 	"dumper/internal/command/database/dynamodb"
 	commandDomain "dumper/internal/domain/command"
 	cmdCfg "dumper/internal/domain/command-config"
+	"dumper/internal/domain/config/archive"
 	"dumper/internal/domain/config/option"
 	"dumper/pkg/utils/mapping"
 	"testing"
@@ -31,7 +32,7 @@ func TestDynamoDBGenerator_Generate_AllScenarios(t *testing.T) {
 					},
 				},
 				DumpName:     "dump1",
-				Archive:      false,
+				Archive:      archive.Archive{Algo: archive.AlgoNone},
 				DumpLocation: "local",
 			},
 			expectedContains: []string{
@@ -54,7 +55,7 @@ func TestDynamoDBGenerator_Generate_AllScenarios(t *testing.T) {
 					},
 				},
 				DumpName:     "products",
-				Archive:      false,
+				Archive:      archive.Archive{Algo: archive.AlgoNone},
 				DumpLocation: "local",
 			},
 			expectedContains: []string{
@@ -77,7 +78,7 @@ func TestDynamoDBGenerator_Generate_AllScenarios(t *testing.T) {
 					},
 				},
 				DumpName:     "archive1",
-				Archive:      true,
+				Archive:      archive.Archive{Algo: archive.AlgoGzip},
 				DumpLocation: "local",
 			},
 			expectedContains: []string{
@@ -101,7 +102,7 @@ func TestDynamoDBGenerator_Generate_AllScenarios(t *testing.T) {
 					},
 				},
 				DumpName:     "localDump",
-				Archive:      false,
+				Archive:      archive.Archive{Algo: archive.AlgoNone},
 				DumpLocation: "local",
 			},
 			expectedContains: []string{
@@ -124,7 +125,7 @@ func TestDynamoDBGenerator_Generate_AllScenarios(t *testing.T) {
 					},
 				},
 				DumpName:     "serverDump",
-				Archive:      false,
+				Archive:      archive.Archive{Algo: archive.AlgoNone},
 				DumpLocation: "server",
 			},
 			expectedContains: []string{
@@ -146,7 +147,7 @@ func TestDynamoDBGenerator_Generate_AllScenarios(t *testing.T) {
 					},
 				},
 				DumpName:     "serverArchive",
-				Archive:      true,
+				Archive:      archive.Archive{Algo: archive.AlgoGzip},
 				DumpLocation: "server",
 			},
 			expectedContains: []string{
@@ -193,7 +194,7 @@ func TestDynamoDBGenerator_CommandIntegrity(t *testing.T) {
 			},
 		},
 		DumpName:     "backup",
-		Archive:      false,
+		Archive:      archive.Archive{Algo: archive.AlgoNone},
 		DumpLocation: "local",
 	}
 
@@ -28,18 +28,19 @@ func (g *Generator) Generate(data *cmdCfg.Config) (*commandDomain.DBCommand, err
 	if data.Database.Options.FastAndStable {
 		baseCmd += fmt.Sprintf(" -se service_mgr %s", data.Database.Options.Path)
 	} else {
-		fileSource := fmt.Sprintf("%s/%s:/%s", "localhost", data.Database.Port, data.Database.Options.Path)
+		fileSource := fmt.Sprintf("%s/%s:/%s", data.Database.GetHost("localhost"), data.Database.Port, data.Database.Options.Path)
 		fileSource = template.GetFullPath(fileSource)
 		baseCmd += fmt.Sprintf(" %s", fileSource)
 	}
 
-	baseCmd += fmt.Sprintf(" %s -user %s -password %s", backupFilePath, data.Database.User, data.Database.Password)
+	baseCmd += fmt.Sprintf(" %s", backupFilePath)
 
-	if data.Archive {
+	if data.Archive.IsEnabled() {
 		originFileName := fmt.Sprintf("%s.%s", data.DumpNameTemplate, ext)
-		backupFilePath = fmt.Sprintf("%s.tar.gz", data.DumpName)
+		backupFilePath = fmt.Sprintf("%s.%s", data.DumpName, data.Archive.TarExt())
 		baseCmd += fmt.Sprintf(
-			" && tar -czf %s -C %s %s",
+			" && %s %s -C %s %s",
+			data.Archive.Tar(),
 			backupFilePath,
 			data.DumpDirRemote,
 			originFileName,
@@ -54,11 +55,22 @@ func (g *Generator) Generate(data *cmdCfg.Config) (*commandDomain.DBCommand, err
 		return &commandDomain.DBCommand{
 			Command:  baseCmd,
 			DumpPath: backupFilePath,
+			Env:      credentialsEnv(data),
 		}, nil
 	}
 
 	return &commandDomain.DBCommand{
 		Command:  baseCmd,
 		DumpPath: backupFilePath,
+		Env:      credentialsEnv(data),
 	}, nil
 }
+
+// credentialsEnv hands the credentials to gbak through ISC_USER and
+// ISC_PASSWORD instead of -user and -password.
+func credentialsEnv(data *cmdCfg.Config) map[string]string {
+	return commandDomain.SecretEnv(map[string]string{
+		"ISC_USER":     data.Database.User,
+		"ISC_PASSWORD": data.Database.Password,
+	})
+}
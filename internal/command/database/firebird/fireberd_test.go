@@ -4,6 +4,7 @@ import (
 	"dumper/internal/command/database/firebird"
 	commandDomain "dumper/internal/domain/command"
 	cmdCfg "dumper/internal/domain/command-config"
+	"dumper/internal/domain/config/archive"
 	"dumper/internal/domain/config/option"
 	"testing"
 
@@ -38,7 +39,7 @@ func TestFirebirdDbGenerator_Generate_Variations(t *testing.T) {
 			expectedContains: []string{
 				"gbak -b",
 				"localhost/3050:/var/lib/firebird/data.fdb",
-				"backup1.fbk -user sysdba -password masterkey",
+				"backup1.fbk",
 			},
 			expectedArchive: "backup1.fbk",
 		},
@@ -102,7 +103,7 @@ func TestFirebirdDbGenerator_Generate_Variations(t *testing.T) {
 				DumpName:         "backup4",
 				DumpDirRemote:    "/backups",
 				DumpNameTemplate: "backup4",
-				Archive:          true,
+				Archive:          archive.Archive{Algo: archive.AlgoGzip},
 				RemoveBackup:     true,
 			},
 			expectedContains: []string{
@@ -129,7 +130,7 @@ func TestFirebirdDbGenerator_Generate_Variations(t *testing.T) {
 				DumpLocation:     "server",
 			},
 			expectedContains: []string{
-				"server_backup.fbk -user sysdba -password masterkey",
+				"server_backup.fbk",
 			},
 			expectedArchive: "server_backup.fbk",
 		},
@@ -151,6 +152,9 @@ func TestFirebirdDbGenerator_Generate_Variations(t *testing.T) {
 			}
 
 			assert.Equal(t, tt.expectedArchive, cmd.DumpPath)
+
+			assert.NotContains(t, cmd.Command, "-password", "Password must not be on the command line")
+			assert.Equal(t, tt.config.Database.Password, cmd.Env["ISC_PASSWORD"])
 		})
 	}
 
@@ -186,7 +190,7 @@ func TestFirebirdDbGenerator_EdgeCases(t *testing.T) {
 			expectedErr: false,
 			expectedOutput: []string{
 				"-b -g -se service_mgr /data/db.fdb",
-				"backup_edge1.fbk -user sysdba -password masterkey",
+				"backup_edge1.fbk",
 			},
 		},
 		{
@@ -204,7 +208,7 @@ func TestFirebirdDbGenerator_EdgeCases(t *testing.T) {
 				DumpName:         "backup_edge2",
 				DumpDirRemote:    "/backups",
 				DumpNameTemplate: "backup_edge2",
-				Archive:          true,
+				Archive:          archive.Archive{Algo: archive.AlgoGzip},
 				RemoveBackup:     false,
 			},
 			expectedErr: false,
@@ -230,7 +234,7 @@ func TestFirebirdDbGenerator_EdgeCases(t *testing.T) {
 			},
 			expectedErr: false,
 			expectedOutput: []string{
-				" -b localhost/3050:/data/db.fdb backup_edge3.fbk -user sysdba -password masterkey",
+				" -b localhost/3050:/data/db.fdb backup_edge3.fbk",
 			},
 		},
 		{
@@ -251,7 +255,7 @@ func TestFirebirdDbGenerator_EdgeCases(t *testing.T) {
 			},
 			expectedErr: false,
 			expectedOutput: []string{
-				"gbak -b localhost/3050: backup_edge4.fbk -user sysdba -password masterkey",
+				"gbak -b localhost/3050: backup_edge4.fbk",
 			},
 		},
 	}
@@ -0,0 +1,32 @@
+package firebird
+
+import (
+	commandDomain "dumper/internal/domain/command"
+	cmdCfg "dumper/internal/domain/command-config"
+	"dumper/pkg/utils/template"
+	"fmt"
+	"strings"
+)
+
+type Restorer struct{}
+
+func (r *Restorer) Restore(data *cmdCfg.Config) (*commandDomain.DBCommand, error) {
+	fileTarget := fmt.Sprintf("%s/%s:/%s", data.Database.GetHost("localhost"), data.Database.Port, data.Database.Options.Path)
+	fileTarget = template.GetFullPath(fileTarget)
+
+	baseCmd := fmt.Sprintf(
+		"%s -rep stdin %s",
+		data.Database.Options.Source,
+		fileTarget,
+	)
+
+	// archived backups are a tarball holding a single .fbk file
+	if strings.HasSuffix(data.DumpName, ".tar") {
+		baseCmd = fmt.Sprintf("tar -xOf - | %s", baseCmd)
+	}
+
+	return &commandDomain.DBCommand{
+		Command: baseCmd,
+		Env:     credentialsEnv(data),
+	}, nil
+}
@@ -12,6 +12,7 @@ type Generator struct{}
 type GenCommand struct {
 	BaseCommand string
 	ArchivePath string
+	Env         map[string]string
 }
 
 func (g *Generator) Generate(
@@ -23,6 +24,7 @@ func (g *Generator) Generate(
 	return &commandDomain.DBCommand{
 		Command:  genCommand.BaseCommand,
 		DumpPath: genCommand.ArchivePath,
+		Env:      genCommand.Env,
 	}, nil
 }
 
@@ -38,11 +40,10 @@ func getCommandByVersion(data *cmdCfg.Config) *GenCommand {
 func generateComandVersion2x(data *cmdCfg.Config) *GenCommand {
 	backupPath := fmt.Sprintf("%s", data.DumpName)
 
-	baseCmd := fmt.Sprintf("%s backup --host %s:%s --token %s",
+	baseCmd := fmt.Sprintf("%s backup --host %s:%s",
 		data.Database.Options.Source,
 		data.Database.Options.Host,
 		data.Database.Port,
-		data.Database.Token,
 	)
 
 	if data.Database.Options.Bucket != "" {
@@ -75,9 +76,10 @@ func generateComandVersion2x(data *cmdCfg.Config) *GenCommand {
 
 	baseCmd += fmt.Sprintf(" %s", backupPath)
 
-	archivePath := backupPath + ".tar.gz"
-	baseCmd = fmt.Sprintf("%s && tar -czf %s -C %s %s",
+	archivePath := backupPath + "." + data.Archive.TarExt()
+	baseCmd = fmt.Sprintf("%s && %s %s -C %s %s",
 		baseCmd,
+		data.Archive.Tar(),
 		archivePath,
 		data.DumpDirRemote,
 		data.DumpNameTemplate,
@@ -90,12 +92,13 @@ func generateComandVersion2x(data *cmdCfg.Config) *GenCommand {
 	return &GenCommand{
 		BaseCommand: baseCmd,
 		ArchivePath: archivePath,
+		Env:         commandDomain.SecretEnv(map[string]string{"INFLUX_TOKEN": data.Database.Token}),
 	}
 }
 
 func generateComandVersion3x(data *cmdCfg.Config) *GenCommand {
 	backupPath := fmt.Sprintf("%s", data.DumpName)
-	archivePath := backupPath + ".tar.gz"
+	archivePath := backupPath + "." + data.Archive.TarExt()
 
 	dataDirPath := template.GetFullPath(data.Database.Options.DataDir, data.Database.Options.NodeId)
 	baseCmd := fmt.Sprintf("cp -r %s/snapshots %s", dataDirPath, backupPath)
@@ -104,8 +107,9 @@ func generateComandVersion3x(data *cmdCfg.Config) *GenCommand {
 	baseCmd = fmt.Sprintf("%s && cp -r %s/catalog %s", baseCmd, dataDirPath, backupPath)
 	baseCmd = fmt.Sprintf("%s && cp %s/_catalog_checkpoint %s", baseCmd, dataDirPath, backupPath)
 
-	baseCmd = fmt.Sprintf("%s && tar -czf %s -C %s %s",
+	baseCmd = fmt.Sprintf("%s && %s %s -C %s %s",
 		baseCmd,
+		data.Archive.Tar(),
 		archivePath,
 		data.DumpDirRemote,
 		data.DumpNameTemplate,
@@ -4,6 +4,7 @@ import (
 	"dumper/internal/command/database/influxdb"
 	commandDomain "dumper/internal/domain/command"
 	cmdCfg "dumper/internal/domain/command-config"
+	"dumper/internal/domain/config/archive"
 	"dumper/internal/domain/config/option"
 	"testing"
 
@@ -32,13 +33,13 @@ func TestInfluxDB2Generator_Generate_Variations(t *testing.T) {
 					},
 				},
 				DumpName:         "dump",
+				Archive:          archive.Archive{Algo: archive.AlgoGzip},
 				DumpDirRemote:    "/tmp",
 				DumpNameTemplate: "dump",
 			},
 			expectedContains: []string{
 				"influx backup",
 				"--host localhost:8086",
-				"--token token",
 				"dump",
 				"tar -czf dump.tar.gz",
 			},
@@ -59,6 +60,7 @@ func TestInfluxDB2Generator_Generate_Variations(t *testing.T) {
 					},
 				},
 				DumpName:         "bucket_dump",
+				Archive:          archive.Archive{Algo: archive.AlgoGzip},
 				DumpDirRemote:    "/data",
 				DumpNameTemplate: "bucket_dump",
 			},
@@ -82,6 +84,7 @@ func TestInfluxDB2Generator_Generate_Variations(t *testing.T) {
 					},
 				},
 				DumpName:         "bucket_id_dump",
+				Archive:          archive.Archive{Algo: archive.AlgoGzip},
 				DumpDirRemote:    "/data",
 				DumpNameTemplate: "bucket_id_dump",
 			},
@@ -105,6 +108,7 @@ func TestInfluxDB2Generator_Generate_Variations(t *testing.T) {
 					},
 				},
 				DumpName:         "org_dump",
+				Archive:          archive.Archive{Algo: archive.AlgoGzip},
 				DumpDirRemote:    "/data",
 				DumpNameTemplate: "org_dump",
 			},
@@ -128,6 +132,7 @@ func TestInfluxDB2Generator_Generate_Variations(t *testing.T) {
 					},
 				},
 				DumpName:         "org_id_dump",
+				Archive:          archive.Archive{Algo: archive.AlgoGzip},
 				DumpDirRemote:    "/data",
 				DumpNameTemplate: "org_id_dump",
 			},
@@ -152,6 +157,7 @@ func TestInfluxDB2Generator_Generate_Variations(t *testing.T) {
 					},
 				},
 				DumpName:         "timerange",
+				Archive:          archive.Archive{Algo: archive.AlgoGzip},
 				DumpDirRemote:    "/data",
 				DumpNameTemplate: "timerange",
 			},
@@ -176,6 +182,7 @@ func TestInfluxDB2Generator_Generate_Variations(t *testing.T) {
 					},
 				},
 				DumpName:         "filter_dump",
+				Archive:          archive.Archive{Algo: archive.AlgoGzip},
 				DumpDirRemote:    "/data",
 				DumpNameTemplate: "filter_dump",
 			},
@@ -198,6 +205,7 @@ func TestInfluxDB2Generator_Generate_Variations(t *testing.T) {
 					},
 				},
 				DumpName:         "cleanup",
+				Archive:          archive.Archive{Algo: archive.AlgoGzip},
 				DumpDirRemote:    "/data",
 				DumpNameTemplate: "cleanup",
 				RemoveBackup:     true,
@@ -219,6 +227,7 @@ func TestInfluxDB2Generator_Generate_Variations(t *testing.T) {
 					},
 				},
 				DumpName:         "backup3x",
+				Archive:          archive.Archive{Algo: archive.AlgoGzip},
 				DumpDirRemote:    "/backups",
 				DumpNameTemplate: "backup3x",
 			},
@@ -270,6 +279,7 @@ func TestInfluxDB2Generator_CommandIntegrity_2x(t *testing.T) {
 			},
 		},
 		DumpName:         "integrity",
+		Archive:          archive.Archive{Algo: archive.AlgoGzip},
 		DumpDirRemote:    "/tmp",
 		DumpNameTemplate: "integrity",
 	}
@@ -282,9 +292,11 @@ func TestInfluxDB2Generator_CommandIntegrity_2x(t *testing.T) {
 	assert.Contains(
 		t,
 		cmd.Command,
-		"influx backup --host localhost:8086 --token secure",
+		"influx backup --host localhost:8086 integrity",
 	)
 	assert.Equal(t, "integrity.tar.gz", cmd.DumpPath)
+	assert.NotContains(t, cmd.Command, "secure")
+	assert.Equal(t, map[string]string{"INFLUX_TOKEN": "secure"}, cmd.Env)
 }
 
 func boolPtr(v bool) *bool {
@@ -1,6 +1,7 @@
 package mariadb
 
 import (
+	"dumper/internal/command/database/mysql"
 	commandDomain "dumper/internal/domain/command"
 	cmdCfg "dumper/internal/domain/command-config"
 	"dumper/internal/domain/config/option"
@@ -10,14 +11,22 @@ import (
 type Generator struct{}
 
 func (g *Generator) Generate(data *cmdCfg.Config) (*commandDomain.DBCommand, error) {
+	switch data.Database.Format {
+	case "mydumper":
+		return mysql.Mydumper(data), nil
+	case "mariabackup":
+		return mysql.Physical(data), nil
+	}
+
 	ext := "sql"
 
 	baseCmd := fmt.Sprintf(
-		"%s -u%s -p%s -h127.0.0.1 -P%s %s",
+		"%s -u%s -h%s -P%s%s %s",
 		data.Database.Options.Source,
 		data.Database.User,
-		data.Database.Password,
+		data.Database.GetHost("127.0.0.1"),
 		data.Database.Port,
+		mysql.ConsistencyFlags(&data.Database.Options),
 		data.Database.Name,
 	)
 
@@ -30,24 +39,28 @@ func (g *Generator) Generate(data *cmdCfg.Config) (*commandDomain.DBCommand, err
 		baseCmd = fmt.Sprintf("%s %s", baseCmd, tables)
 	}
 
-	if data.Archive {
-		baseCmd += " | gzip"
-		ext += ".gz"
+	if data.Archive.IsEnabled() {
+		baseCmd += data.Archive.Pipe()
+		ext += "." + data.Archive.Ext()
 	}
 
+	env := commandDomain.SecretEnv(map[string]string{"MYSQL_PWD": data.Database.Password})
+
 	fileName := fmt.Sprintf("%s.%s", data.DumpName, ext)
 	remotePath := fmt.Sprintf("%s", fileName)
 
-	if data.DumpLocation == "server" {
+	if data.DumpLocation == "local-ssh" {
 		return &commandDomain.DBCommand{
-			Command:  fmt.Sprintf("%s > %s", baseCmd, remotePath),
+			Command:  baseCmd,
 			DumpPath: remotePath,
+			Env:      env,
 		}, nil
 	}
 
 	return &commandDomain.DBCommand{
-		Command:  baseCmd,
+		Command:  fmt.Sprintf("%s > %s", baseCmd, remotePath),
 		DumpPath: remotePath,
+		Env:      env,
 	}, nil
 }
 
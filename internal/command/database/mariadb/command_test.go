@@ -4,8 +4,10 @@ import (
 	"dumper/internal/command/database/mariadb"
 	commandDomain "dumper/internal/domain/command"
 	cmdCfg "dumper/internal/domain/command-config"
+	"dumper/internal/domain/config/archive"
 	"dumper/internal/domain/config/option"
 	"dumper/pkg/utils/mapping"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -31,13 +33,12 @@ func TestMariaDbGenerator_Generate(t *testing.T) {
 					Options:  option.Options{Source: source},
 				},
 				DumpName:     "dump",
-				Archive:      false,
+				Archive:      archive.Archive{Algo: archive.AlgoNone},
 				DumpLocation: "local",
 			},
 			expectedContains: []string{
 				"mariadb-dump",
 				"-uroot",
-				"-p123",
 				"-P3306",
 				"testdb",
 			},
@@ -54,7 +55,7 @@ func TestMariaDbGenerator_Generate(t *testing.T) {
 					Options:  option.Options{Source: source},
 				},
 				DumpName:     "dump",
-				Archive:      true,
+				Archive:      archive.Archive{Algo: archive.AlgoGzip},
 				DumpLocation: "local",
 			},
 			expectedContains: []string{
@@ -74,7 +75,7 @@ func TestMariaDbGenerator_Generate(t *testing.T) {
 					Options:  option.Options{Source: source},
 				},
 				DumpName:     "server_dump",
-				Archive:      false,
+				Archive:      archive.Archive{Algo: archive.AlgoNone},
 				DumpLocation: "server",
 			},
 			expectedContains: []string{
@@ -94,7 +95,7 @@ func TestMariaDbGenerator_Generate(t *testing.T) {
 					Options:  option.Options{Source: source},
 				},
 				DumpName:     "server_dump",
-				Archive:      true,
+				Archive:      archive.Archive{Algo: archive.AlgoGzip},
 				DumpLocation: "server",
 			},
 			expectedContains: []string{
@@ -118,12 +119,12 @@ func TestMariaDbGenerator_Generate(t *testing.T) {
 					},
 				},
 				DumpName:     "server_dump",
-				Archive:      false,
+				Archive:      archive.Archive{Algo: archive.AlgoNone},
 				DumpLocation: "server",
 			},
 			expectedContains: []string{
 				"mariadb-dump",
-				"-uroot -p123 -h127.0.0.1 -P3306 testdb ",
+				"-uroot -h127.0.0.1 -P3306 testdb ",
 				"users roles",
 				"> server_dump.sql",
 			},
@@ -143,12 +144,12 @@ func TestMariaDbGenerator_Generate(t *testing.T) {
 					},
 				},
 				DumpName:     "server_dump",
-				Archive:      false,
+				Archive:      archive.Archive{Algo: archive.AlgoNone},
 				DumpLocation: "server",
 			},
 			expectedContains: []string{
 				"mariadb-dump",
-				"-uroot -p123 -h127.0.0.1 -P3306 testdb ",
+				"-uroot -h127.0.0.1 -P3306 testdb ",
 				"--ignore-table=testdb.users --ignore-table=testdb.roles",
 				"> server_dump.sql",
 			},
@@ -189,7 +190,7 @@ func TestMariaDbGenerator_CommandIntegrity(t *testing.T) {
 			Options:  option.Options{Source: source},
 		},
 		DumpName:     "backup",
-		Archive:      false,
+		Archive:      archive.Archive{Algo: archive.AlgoNone},
 		DumpLocation: "local",
 	}
 
@@ -198,7 +199,59 @@ func TestMariaDbGenerator_CommandIntegrity(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, cmd)
 
-	expectedPrefix := "mariadb-dump -uadmin -psecret -h127.0.0.1 -P3307 mydb"
+	expectedPrefix := "mariadb-dump -uadmin -h127.0.0.1 -P3307 mydb"
 	assert.Contains(t, cmd.Command, expectedPrefix, "Command must be constructed correctly")
 	assert.Equal(t, "backup.sql", cmd.DumpPath, "DumpPath should match expected filename")
+	assert.Equal(t, map[string]string{"MYSQL_PWD": "secret"}, cmd.Env)
+}
+
+func TestMariaDbGenerator_ConsistencyFlags(t *testing.T) {
+	enabled := true
+
+	cfg := &cmdCfg.Config{
+		Database: cmdCfg.Database{
+			User:    "root",
+			Port:    "3306",
+			Name:    "shop",
+			Options: option.Options{Source: mapping.GetDBSource("mariadb", "sql"), SingleTransaction: &enabled, Routines: true},
+		},
+		DumpName:     "shop",
+		Archive:      archive.Archive{Algo: archive.AlgoNone},
+		DumpLocation: "server",
+	}
+
+	gen := mariadb.Generator{}
+	cmd, err := gen.Generate(cfg)
+
+	require.NoError(t, err)
+	assert.Equal(t, "mariadb-dump -uroot -h127.0.0.1 -P3306 --single-transaction --routines shop > shop.sql", cmd.Command)
+}
+
+func TestMariaDbGenerator_Mariabackup(t *testing.T) {
+	cfg := &cmdCfg.Config{
+		Database: cmdCfg.Database{
+			Key:    "shop",
+			User:   "backup",
+			Port:   "3306",
+			Format: "mariabackup",
+			Options: option.Options{
+				Source:      mapping.GetDBSource("mariadb", "mariabackup"),
+				Incremental: true,
+			},
+		},
+		DumpName:      "/backups/shop",
+		DumpDirRemote: "/backups",
+		Archive:       archive.Archive{Algo: archive.AlgoZstd},
+		DumpLocation:  "server",
+	}
+
+	gen := mariadb.Generator{}
+	cmd, err := gen.Generate(cfg)
+
+	require.NoError(t, err)
+	assert.Contains(t, cmd.Command, "mariabackup --backup --stream=xbstream --host=127.0.0.1 --port=3306 --user=backup")
+	assert.Contains(t, cmd.Command, "--incremental-basedir=/backups/shop.lsn)")
+	assert.True(t, strings.HasSuffix(cmd.Command, "| zstd -q > /backups/shop.xbstream.zst"))
+	assert.Equal(t, "/backups/shop.xbstream.zst", cmd.DumpPath)
+	assert.Equal(t, "sh -c 'rm -rf /backups/shop.lsn && mv /backups/shop.lsn.next /backups/shop.lsn'", cmd.Commit)
 }
@@ -0,0 +1,23 @@
+package mariadb
+
+import (
+	"dumper/internal/command/database/mysql"
+	commandDomain "dumper/internal/domain/command"
+	cmdCfg "dumper/internal/domain/command-config"
+	"fmt"
+)
+
+// Discover lists the databases with the mariadb client next to the dump tool.
+func (g *Generator) Discover(data *cmdCfg.Config) (*commandDomain.DBCommand, error) {
+	return &commandDomain.DBCommand{
+		Command: fmt.Sprintf(
+			"%s -u%s -h%s -P%s -N -B -e \"%s\"",
+			mysql.Client(data.Database.Options.Source, "mariadb"),
+			data.Database.User,
+			data.Database.GetHost("127.0.0.1"),
+			data.Database.Port,
+			mysql.SystemQuery,
+		),
+		Env: commandDomain.SecretEnv(map[string]string{"MYSQL_PWD": data.Database.Password}),
+	}, nil
+}
@@ -0,0 +1,35 @@
+package mariadb
+
+import (
+	"dumper/internal/command/database/mysql"
+	commandDomain "dumper/internal/domain/command"
+	cmdCfg "dumper/internal/domain/command-config"
+	"fmt"
+)
+
+type Restorer struct{}
+
+func (r *Restorer) Restore(data *cmdCfg.Config) (*commandDomain.DBCommand, error) {
+	switch data.Database.Format {
+	case "mydumper":
+		return mysql.Myloader(data), nil
+	case "mariabackup":
+		return nil, fmt.Errorf(
+			"format mariabackup is a physical copy, prepare it with mariabackup --prepare and copy it back with the server stopped",
+		)
+	}
+
+	baseCmd := fmt.Sprintf(
+		"%s -u%s -h%s -P%s %s",
+		data.Database.Options.Source,
+		data.Database.User,
+		data.Database.GetHost("127.0.0.1"),
+		data.Database.Port,
+		data.Database.Name,
+	)
+
+	return &commandDomain.DBCommand{
+		Command: baseCmd,
+		Env:     commandDomain.SecretEnv(map[string]string{"MYSQL_PWD": data.Database.Password}),
+	}, nil
+}
@@ -3,6 +3,7 @@ package mongodb
 import (
 	commandDomain "dumper/internal/domain/command"
 	cmdCfg "dumper/internal/domain/command-config"
+	"dumper/internal/domain/config/archive"
 	"dumper/internal/domain/config/option"
 	"fmt"
 	"net/url"
@@ -20,9 +21,10 @@ func (g *Generator) Generate(data *cmdCfg.Config) (*commandDomain.DBCommand, err
 	}
 
 	uri := fmt.Sprintf(
-		"mongodb://%s:%s@127.0.0.1:%s/",
+		"mongodb://%s:%s@%s:%s/",
 		url.QueryEscape(data.Database.User),
 		url.QueryEscape(data.Database.Password),
+		data.Database.GetHost("127.0.0.1"),
 		data.Database.Port,
 	)
 
@@ -42,9 +44,8 @@ func (g *Generator) Generate(data *cmdCfg.Config) (*commandDomain.DBCommand, err
 		uri += "?" + params
 	}
 
-	baseCmd := fmt.Sprintf("%s --uri \"%s\" --db %s",
-		data.Database.Options.Source,
-		uri,
+	baseCmd := fmt.Sprintf("%s --db %s",
+		withURI(data.Database.Options.Source),
 		data.Database.Name,
 	)
 
@@ -53,37 +54,47 @@ func (g *Generator) Generate(data *cmdCfg.Config) (*commandDomain.DBCommand, err
 		baseCmd = fmt.Sprintf("%s %s", baseCmd, tables)
 	}
 
-	if data.Archive {
-		if formatFlag == "--archive" {
+	// gzip is left to mongodump, it compresses the collections inside the
+	// archive. Any other algorithm compresses the archive as a whole.
+	isGzip := data.Archive.Algo == archive.AlgoGzip
+
+	// the dump tool runs in one shell with the printf feeding it the URI,
+	// so with docker enabled both run in the container. Compression piped
+	// after it stays where it was.
+	switch {
+	case formatFlag != "--archive":
+		ext = data.Archive.TarExt()
+		baseCmd = commandDomain.Shell(fmt.Sprintf("%s --out ./ && %s %s.%s %s", baseCmd, data.Archive.Tar(), data.DumpName, ext, data.Database.Name))
+	case data.DumpLocation == "local-ssh":
+		ext = "archive"
+		baseCmd += " --archive"
+		if isGzip {
 			ext = "gz"
-			baseCmd = fmt.Sprintf("%s --archive=%s.%s --gzip", baseCmd, data.DumpName, ext)
+			baseCmd = commandDomain.Shell(baseCmd + " --gzip")
+		} else if data.Archive.IsEnabled() {
+			ext += "." + data.Archive.Ext()
+			baseCmd = commandDomain.Shell(baseCmd) + data.Archive.Pipe()
 		} else {
-			baseCmd = fmt.Sprintf("%s --out ./ && tar -czf %s.tar.gz %s", baseCmd, data.DumpName, data.Database.Name)
-			ext = "tar.gz"
-		}
-	} else {
-		if formatFlag == "--archive" {
-			ext = "archive"
-			baseCmd = fmt.Sprintf("%s --archive=%s.%s", baseCmd, data.DumpName, ext)
-		} else {
-			ext = "tar.gz"
-			baseCmd = fmt.Sprintf("%s --out ./ && tar -czf %s.tar.gz %s", baseCmd, data.DumpName, data.Database.Name)
+			baseCmd = commandDomain.Shell(baseCmd)
 		}
+	case isGzip:
+		ext = "gz"
+		baseCmd = commandDomain.Shell(fmt.Sprintf("%s --archive=%s.%s --gzip", baseCmd, data.DumpName, ext))
+	case data.Archive.IsEnabled():
+		ext = "archive." + data.Archive.Ext()
+		baseCmd = fmt.Sprintf("%s%s > %s.%s", commandDomain.Shell(baseCmd+" --archive"), data.Archive.Pipe(), data.DumpName, ext)
+	default:
+		ext = "archive"
+		baseCmd = commandDomain.Shell(fmt.Sprintf("%s --archive=%s.%s", baseCmd, data.DumpName, ext))
 	}
 
 	fileName := fmt.Sprintf("%s.%s", data.DumpName, ext)
 	remotePath := fmt.Sprintf("%s", fileName)
 
-	if data.DumpLocation == "server" {
-		return &commandDomain.DBCommand{
-			Command:  baseCmd,
-			DumpPath: remotePath,
-		}, nil
-	}
-
 	return &commandDomain.DBCommand{
 		Command:  baseCmd,
 		DumpPath: remotePath,
+		Env:      map[string]string{uriEnv: uri},
 	}, nil
 }
 
@@ -108,3 +119,15 @@ func prepareTables(
 
 	return out
 }
+
+// uriEnv carries the connection string, password included, so it never
+// shows up in the process list.
+const uriEnv = "DUMPER_MONGO_URI"
+
+// withURI runs the mongo tool at source with the connection string as a
+// --config file read from stdin. The URI is query escaped, so it holds no
+// character that needs quoting in YAML. The result is a pipeline, callers
+// run it through commandDomain.Shell.
+func withURI(source string) string {
+	return fmt.Sprintf(`printf 'uri: "%%s"\n' "$%s" | %s --config /dev/stdin`, uriEnv, source)
+}
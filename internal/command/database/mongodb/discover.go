@@ -0,0 +1,44 @@
+package mongodb
+
+import (
+	commandDomain "dumper/internal/domain/command"
+	cmdCfg "dumper/internal/domain/command-config"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// Discover lists the databases with mongosh, admin, config and local are
+// left out.
+func (g *Generator) Discover(data *cmdCfg.Config) (*commandDomain.DBCommand, error) {
+	uri := fmt.Sprintf(
+		"mongodb://%s:%s@%s:%s/",
+		url.QueryEscape(data.Database.User),
+		url.QueryEscape(data.Database.Password),
+		data.Database.GetHost("127.0.0.1"),
+		data.Database.Port,
+	)
+
+	if data.Database.Options.AuthSource != "" {
+		uri += fmt.Sprintf("?authSource=%s", url.QueryEscape(data.Database.Options.AuthSource))
+	}
+
+	shell := "mongosh"
+	if strings.Contains(data.Database.Options.Source, "/") {
+		shell = path.Join(path.Dir(data.Database.Options.Source), shell)
+	}
+
+	// mongosh connects from inside the script, so the URI is read from the
+	// environment instead of the command line
+	return &commandDomain.DBCommand{
+		Command: fmt.Sprintf(
+			"%s --nodb --quiet --eval \"new Mongo(process.env.%s).getDB('admin')"+
+				".adminCommand({listDatabases: 1, nameOnly: true}).databases"+
+				".forEach(d => { if (!['admin', 'config', 'local'].includes(d.name)) print(d.name) })\"",
+			shell,
+			uriEnv,
+		),
+		Env: map[string]string{uriEnv: uri},
+	}, nil
+}
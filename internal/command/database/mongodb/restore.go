@@ -0,0 +1,53 @@
+package mongodb
+
+import (
+	commandDomain "dumper/internal/domain/command"
+	cmdCfg "dumper/internal/domain/command-config"
+	"dumper/pkg/utils/template"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+type Restorer struct{}
+
+func (r *Restorer) Restore(data *cmdCfg.Config) (*commandDomain.DBCommand, error) {
+	uri := fmt.Sprintf(
+		"mongodb://%s:%s@%s:%s/",
+		url.QueryEscape(data.Database.User),
+		url.QueryEscape(data.Database.Password),
+		data.Database.GetHost("127.0.0.1"),
+		data.Database.Port,
+	)
+
+	if data.Database.Options.AuthSource != "" {
+		uri += fmt.Sprintf("?authSource=%s", url.QueryEscape(data.Database.Options.AuthSource))
+	}
+
+	baseCmd := fmt.Sprintf("%s --drop --nsInclude \"%s.*\"",
+		withURI(data.Database.Options.Source),
+		data.Database.Name,
+	)
+
+	if data.Database.Format == "archive" {
+		// stdin carries the config, so the archive is read through fd 3.
+		// mongodump --gzip compresses the collections inside the archive,
+		// so the file itself stays a plain archive with a .gz extension.
+		baseCmd += " --archive=/dev/fd/3"
+		if strings.HasSuffix(data.DumpName, ".gz") {
+			baseCmd += " --gzip"
+		}
+		baseCmd = fmt.Sprintf("{ %s; } 3<&0", baseCmd)
+	} else {
+		dumpDir := template.GetFullPath(data.DumpDirRemote, data.Database.Name)
+		baseCmd = fmt.Sprintf(
+			"mkdir -p %s && tar -xf - -C %s && %s %s && rm -rf %s",
+			data.DumpDirRemote, data.DumpDirRemote, baseCmd, data.DumpDirRemote, dumpDir,
+		)
+	}
+
+	return &commandDomain.DBCommand{
+		Command: commandDomain.Shell(baseCmd),
+		Env:     map[string]string{uriEnv: uri},
+	}, nil
+}
@@ -22,12 +22,15 @@ func (g *Generator) Generate(data *cmdCfg.Config) (*commandDomain.DBCommand, err
 		return nil, fmt.Errorf("unsupported database format: %s", data.Database.Format)
 	}
 
-	if data.Archive {
+	// the dump is packed in a tarball of the one file, the restore unpacks
+	// it again
+	if data.Archive.IsEnabled() {
+		archivePath := baseCmd.DumpPath + "." + data.Archive.Ext()
 		baseCmd.Command = fmt.Sprintf(
-			"%s && tar -czf %s.gz %s",
-			baseCmd.Command, baseCmd.DumpPath, baseCmd.DumpPath,
+			"%s && %s %s %s",
+			baseCmd.Command, data.Archive.Tar(), archivePath, baseCmd.DumpPath,
 		)
-		baseCmd.DumpPath += ".gz"
+		baseCmd.DumpPath = archivePath
 	}
 
 	if data.DumpLocation == "server" {
@@ -45,11 +48,10 @@ func genFormatBak(
 	remotePath := fmt.Sprintf("%s", fileName)
 
 	baseCmd := fmt.Sprintf(
-		"%s -S %s -C -U %s -P %s -Q \"BACKUP DATABASE [%s] TO DISK=\\\"%s\\\" WITH FORMAT, INIT\"",
+		"%s -S %s -C -U %s -Q \"BACKUP DATABASE [%s] TO DISK=\\\"%s\\\" WITH FORMAT, INIT\"",
 		data.Database.Options.Source,
-		"localhost",
+		data.Database.GetHost("localhost"),
 		data.Database.User,
-		data.Database.Password,
 		data.Database.Name,
 		remotePath,
 	)
@@ -57,6 +59,7 @@ func genFormatBak(
 	return &commandDomain.DBCommand{
 		Command:  baseCmd,
 		DumpPath: remotePath,
+		Env:      commandDomain.SecretEnv(map[string]string{"SQLCMDPASSWORD": data.Database.Password}),
 	}
 }
 
@@ -67,10 +70,13 @@ func genFormatBacpac(
 	fileName := fmt.Sprintf("%s.%s", data.DumpName, ext)
 	remotePath := fmt.Sprintf("%s", fileName)
 
+	// sqlpackage only takes the password as an argument, it has no
+	// environment variable or option file for it. Unlike bac, a bacpac
+	// export leaves it visible in the process list of the server.
 	baseCmd := fmt.Sprintf(
 		"%s /Action:Export /SourceServerName:%s /SourceDatabaseName:%s /SourceUser:%s /SourcePassword:%s /TargetFile:%s",
 		data.Database.Options.Source,
-		"localhost",
+		data.Database.GetHost("localhost"),
 		data.Database.Name,
 		data.Database.User,
 		data.Database.Password,
@@ -4,6 +4,7 @@ import (
 	"dumper/internal/command/database/mssql"
 	commandDomain "dumper/internal/domain/command"
 	cmdCfg "dumper/internal/domain/command-config"
+	"dumper/internal/domain/config/archive"
 	"dumper/internal/domain/config/option"
 	"dumper/pkg/utils/mapping"
 	"testing"
@@ -37,7 +38,7 @@ func TestMSQLGenerator_Generate_AllScenarios(t *testing.T) {
 					Options:  option.Options{SSL: &falseVal, Source: sourceBac},
 				},
 				DumpName:     "backup1",
-				Archive:      false,
+				Archive:      archive.Archive{Algo: archive.AlgoNone},
 				DumpLocation: "local",
 			},
 			expectedContains: []string{
@@ -59,7 +60,7 @@ func TestMSQLGenerator_Generate_AllScenarios(t *testing.T) {
 					Options:  option.Options{SSL: &falseVal, Source: sourceBacpac},
 				},
 				DumpName:     "export1",
-				Archive:      false,
+				Archive:      archive.Archive{Algo: archive.AlgoNone},
 				DumpLocation: "local",
 			},
 			expectedContains: []string{
@@ -82,7 +83,7 @@ func TestMSQLGenerator_Generate_AllScenarios(t *testing.T) {
 					Options:  option.Options{SSL: &trueVal, Source: sourceBacpac},
 				},
 				DumpName:     "exportSSL",
-				Archive:      false,
+				Archive:      archive.Archive{Algo: archive.AlgoNone},
 				DumpLocation: "local",
 			},
 			expectedContains: []string{
@@ -102,13 +103,11 @@ func TestMSQLGenerator_Generate_AllScenarios(t *testing.T) {
 					Options:  option.Options{SSL: &falseVal, Source: sourceBac},
 				},
 				DumpName:     "backup2",
-				Archive:      true,
+				Archive:      archive.Archive{Algo: archive.AlgoGzip},
 				DumpLocation: "local",
 			},
 			expectedContains: []string{
-				"tar -czf",
-				"backup2.bak.gz",
-				"backup2.bak",
+				"tar -czf backup2.bak.gz backup2.bak",
 			},
 			expectedExt: ".bak.gz",
 		},
@@ -124,13 +123,11 @@ func TestMSQLGenerator_Generate_AllScenarios(t *testing.T) {
 					Options:  option.Options{SSL: &falseVal, Source: sourceBacpac},
 				},
 				DumpName:     "export2",
-				Archive:      true,
+				Archive:      archive.Archive{Algo: archive.AlgoGzip},
 				DumpLocation: "local",
 			},
 			expectedContains: []string{
-				"tar -czf",
-				"export2.bacpac.gz",
-				"export2.bacpac",
+				"tar -czf export2.bacpac.gz export2.bacpac",
 			},
 			expectedExt: ".bacpac.gz",
 		},
@@ -146,7 +143,7 @@ func TestMSQLGenerator_Generate_AllScenarios(t *testing.T) {
 					Options:  option.Options{SSL: &falseVal, Source: sourceBac},
 				},
 				DumpName:     "dumpServer",
-				Archive:      false,
+				Archive:      archive.Archive{Algo: archive.AlgoNone},
 				DumpLocation: "server",
 			},
 			expectedContains: []string{
@@ -167,7 +164,7 @@ func TestMSQLGenerator_Generate_AllScenarios(t *testing.T) {
 					Options:  option.Options{SSL: &falseVal, Source: sourceBac},
 				},
 				DumpName:     "badDump",
-				Archive:      false,
+				Archive:      archive.Archive{Algo: archive.AlgoNone},
 				DumpLocation: "local",
 			},
 			expectErr: true,
@@ -188,7 +185,7 @@ func TestMSQLGenerator_Generate_AllScenarios(t *testing.T) {
 					},
 				},
 				DumpName:     "export1",
-				Archive:      false,
+				Archive:      archive.Archive{Algo: archive.AlgoNone},
 				DumpLocation: "local",
 			},
 			expectedContains: []string{
@@ -226,6 +223,11 @@ func TestMSQLGenerator_Generate_AllScenarios(t *testing.T) {
 				assert.Contains(t, cmd.Command, expect, "Expected command to contain: %s", expect)
 			}
 
+			if tt.config.Database.Format == "bac" {
+				assert.NotContains(t, cmd.Command, " -P ", "Password must not be on the command line")
+				assert.Equal(t, tt.config.Database.Password, cmd.Env["SQLCMDPASSWORD"])
+			}
+
 			assert.Contains(t, cmd.DumpPath, tt.expectedExt, "DumpPath should end with expected extension")
 		})
 	}
@@ -0,0 +1,54 @@
+package mssql
+
+import (
+	commandDomain "dumper/internal/domain/command"
+	cmdCfg "dumper/internal/domain/command-config"
+	"fmt"
+)
+
+type Restorer struct{}
+
+func (r *Restorer) Restore(data *cmdCfg.Config) (*commandDomain.DBCommand, error) {
+	var baseCmd string
+	var env map[string]string
+
+	switch data.Database.Format {
+	case "bac":
+		baseCmd = fmt.Sprintf(
+			"%s -S %s -C -U %s -Q \"RESTORE DATABASE [%s] FROM DISK=\\\"%s\\\" WITH REPLACE\"",
+			data.Database.Options.Source,
+			data.Database.GetHost("localhost"),
+			data.Database.User,
+			data.Database.Name,
+			data.DumpName,
+		)
+		env = commandDomain.SecretEnv(map[string]string{"SQLCMDPASSWORD": data.Database.Password})
+	case "bacpac":
+		// like the export, sqlpackage only takes the password as an argument
+		baseCmd = fmt.Sprintf(
+			"%s /Action:Import /SourceFile:%s /TargetServerName:%s /TargetDatabaseName:%s /TargetUser:%s /TargetPassword:%s",
+			data.Database.Options.Source,
+			data.DumpName,
+			data.Database.GetHost("localhost"),
+			data.Database.Name,
+			data.Database.User,
+			data.Database.Password,
+		)
+	default:
+		return nil, fmt.Errorf("unsupported database format: %s", data.Database.Format)
+	}
+
+	// a compressed dump is a tarball of the one file, the restore gets it
+	// decompressed and takes the file out of it. The ustar magic tells it
+	// from a plain dump, a bacpac is a zip some tar builds would list.
+	baseCmd = fmt.Sprintf(
+		"if [ \"$(dd if=%s bs=1 skip=257 count=5 2>/dev/null)\" = ustar ]; then tar -xOf %s > %s.tmp && mv %s.tmp %s; fi && %s",
+		data.DumpName, data.DumpName, data.DumpName, data.DumpName, data.DumpName, baseCmd,
+	)
+
+	return &commandDomain.DBCommand{
+		Command:  baseCmd,
+		DumpPath: data.DumpName,
+		Env:      env,
+	}, nil
+}
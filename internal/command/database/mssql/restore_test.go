@@ -0,0 +1,39 @@
+package mssql_test
+
+import (
+	"dumper/internal/command/database/mssql"
+	cmdCfg "dumper/internal/domain/command-config"
+	"dumper/internal/domain/config/option"
+	"dumper/pkg/utils/mapping"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMSSQLRestorer_Restore(t *testing.T) {
+	cfg := &cmdCfg.Config{
+		Database: cmdCfg.Database{
+			Format:   "bac",
+			User:     "sa",
+			Password: "secret",
+			Name:     "shop",
+			Options:  option.Options{Source: mapping.GetRestoreSource("mssql", "bac")},
+		},
+		DumpName: "/tmp/shop.bak",
+	}
+
+	restorer := mssql.Restorer{}
+	cmd, err := restorer.Restore(cfg)
+
+	require.NoError(t, err)
+	assert.Equal(t,
+		`if [ "$(dd if=/tmp/shop.bak bs=1 skip=257 count=5 2>/dev/null)" = ustar ]; then `+
+			`tar -xOf /tmp/shop.bak > /tmp/shop.bak.tmp && mv /tmp/shop.bak.tmp /tmp/shop.bak; fi && `+
+			`sqlcmd -S localhost -C -U sa -Q "RESTORE DATABASE [shop] FROM DISK=\"/tmp/shop.bak\" WITH REPLACE"`,
+		cmd.Command,
+	)
+	assert.Equal(t, "/tmp/shop.bak", cmd.DumpPath)
+	assert.Equal(t, "secret", cmd.Env["SQLCMDPASSWORD"])
+	assert.NotContains(t, cmd.Command, "secret", "Password must not be on the command line")
+}
@@ -5,20 +5,33 @@ import (
 	cmdCfg "dumper/internal/domain/command-config"
 	"dumper/internal/domain/config/option"
 	"fmt"
+	"strings"
 )
 
 type Generator struct{}
 
 func (g *Generator) Generate(data *cmdCfg.Config) (*commandDomain.DBCommand, error) {
+	switch data.Database.Format {
+	case "mydumper":
+		return Mydumper(data), nil
+	case "xtrabackup":
+		return Physical(data), nil
+	}
+
 	ext := "sql"
 
-	baseCmd := fmt.Sprintf("%s -h 127.0.0.1 -P %s -u %s -p%s",
+	baseCmd := fmt.Sprintf("%s -h %s -P %s -u %s%s",
 		data.Database.Options.Source,
+		data.Database.GetHost("127.0.0.1"),
 		data.Database.Port,
 		data.Database.User,
-		data.Database.Password,
+		ConsistencyFlags(&data.Database.Options),
 	)
 
+	if data.Database.Options.SetGtidPurged != "" {
+		baseCmd += " --set-gtid-purged=" + strings.ToUpper(data.Database.Options.SetGtidPurged)
+	}
+
 	tables := prepareTables(
 		&data.Database.Options,
 		data.Database.Name+".",
@@ -29,29 +42,55 @@ func (g *Generator) Generate(data *cmdCfg.Config) (*commandDomain.DBCommand, err
 		baseCmd = fmt.Sprintf("%s --databases %s", baseCmd, data.Database.Name)
 	}
 
-	if data.Archive {
-		baseCmd += " | gzip"
-		ext += ".gz"
+	if data.Archive.IsEnabled() {
+		baseCmd += data.Archive.Pipe()
+		ext += "." + data.Archive.Ext()
 	}
 
+	env := commandDomain.SecretEnv(map[string]string{"MYSQL_PWD": data.Database.Password})
+
 	fileName := fmt.Sprintf("%s.%s", data.DumpName, ext)
 	remotePath := fmt.Sprintf("%s", fileName)
 
-	baseCmd = fmt.Sprintf("%s > %s", baseCmd, remotePath)
-
-	if data.DumpLocation == "server" {
+	if data.DumpLocation == "local-ssh" {
 		return &commandDomain.DBCommand{
 			Command:  baseCmd,
 			DumpPath: remotePath,
+			Env:      env,
 		}, nil
 	}
 
 	return &commandDomain.DBCommand{
-		Command:  baseCmd,
+		Command:  fmt.Sprintf("%s > %s", baseCmd, remotePath),
 		DumpPath: remotePath,
+		Env:      env,
 	}, nil
 }
 
+// ConsistencyFlags are the flags mysqldump shares with mariadb-dump. An
+// option left unset keeps the default of the tool.
+func ConsistencyFlags(options *option.Options) string {
+	out := ""
+
+	if options.SingleTransaction != nil && *options.SingleTransaction {
+		out += " --single-transaction"
+	}
+
+	if options.Routines {
+		out += " --routines"
+	}
+
+	if options.Triggers != nil && !*options.Triggers {
+		out += " --skip-triggers"
+	}
+
+	if options.Events {
+		out += " --events"
+	}
+
+	return out
+}
+
 func prepareTables(
 	options *option.Options,
 	prefix string,
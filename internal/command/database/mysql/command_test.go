@@ -1,9 +1,13 @@
 package mysql_test
 
 import (
+	"context"
 	"dumper/internal/command/database/mysql"
+	dockerApp "dumper/internal/docker"
 	commandDomain "dumper/internal/domain/command"
 	cmdCfg "dumper/internal/domain/command-config"
+	"dumper/internal/domain/config/archive"
+	"dumper/internal/domain/config/docker"
 	"dumper/internal/domain/config/option"
 	"dumper/pkg/utils/mapping"
 	"testing"
@@ -31,14 +35,36 @@ func TestMySQLGenerator_Generate_AllScenarios(t *testing.T) {
 					Options:  option.Options{Source: source},
 				},
 				DumpName:     "dump1",
-				Archive:      false,
+				Archive:      archive.Archive{Algo: archive.AlgoNone},
 				DumpLocation: "local",
 			},
 			expectedContains: []string{
 				"mysqldump",
 				"-h 127.0.0.1",
 				"-u root",
-				"-ppassword",
+				"testdb",
+			},
+			expectedExt: "sql",
+		},
+		{
+			name: "MySQL dump with custom host (local-direct)",
+			config: &cmdCfg.Config{
+				Database: cmdCfg.Database{
+					Port:     "3306",
+					User:     "root",
+					Password: "password",
+					Name:     "testdb",
+					Host:     "mysql.internal",
+					Options:  option.Options{Source: source},
+				},
+				DumpName:     "dump_direct",
+				Archive:      archive.Archive{Algo: archive.AlgoNone},
+				DumpLocation: "local-direct",
+			},
+			expectedContains: []string{
+				"mysqldump",
+				"-h mysql.internal",
+				"-P 3306",
 				"testdb",
 			},
 			expectedExt: "sql",
@@ -54,7 +80,7 @@ func TestMySQLGenerator_Generate_AllScenarios(t *testing.T) {
 					Options:  option.Options{Source: source},
 				},
 				DumpName:     "backup1",
-				Archive:      true,
+				Archive:      archive.Archive{Algo: archive.AlgoGzip},
 				DumpLocation: "local",
 			},
 			expectedContains: []string{
@@ -64,6 +90,26 @@ func TestMySQLGenerator_Generate_AllScenarios(t *testing.T) {
 			},
 			expectedExt: "sql.gz",
 		},
+		{
+			name: "Archived MySQL dump (zstd with level and threads)",
+			config: &cmdCfg.Config{
+				Database: cmdCfg.Database{
+					Port:     "3307",
+					User:     "admin",
+					Password: "1234",
+					Name:     "prod_db",
+					Options:  option.Options{Source: source},
+				},
+				DumpName:     "backup_zstd",
+				Archive:      archive.Archive{Algo: archive.AlgoZstd, Level: 19, Threads: 4},
+				DumpLocation: "local",
+			},
+			expectedContains: []string{
+				"mysqldump",
+				"| zstd -q -19 -T4",
+			},
+			expectedExt: "sql.zst",
+		},
 		{
 			name: "Server dump (no archive)",
 			config: &cmdCfg.Config{
@@ -75,7 +121,7 @@ func TestMySQLGenerator_Generate_AllScenarios(t *testing.T) {
 					Options:  option.Options{Source: source},
 				},
 				DumpName:     "dump_server",
-				Archive:      false,
+				Archive:      archive.Archive{Algo: archive.AlgoNone},
 				DumpLocation: "server",
 			},
 			expectedContains: []string{
@@ -95,7 +141,7 @@ func TestMySQLGenerator_Generate_AllScenarios(t *testing.T) {
 					Options:  option.Options{Source: source},
 				},
 				DumpName:     "dump_archive",
-				Archive:      true,
+				Archive:      archive.Archive{Algo: archive.AlgoGzip},
 				DumpLocation: "server",
 			},
 			expectedContains: []string{
@@ -119,14 +165,13 @@ func TestMySQLGenerator_Generate_AllScenarios(t *testing.T) {
 					},
 				},
 				DumpName:     "testdb",
-				Archive:      false,
+				Archive:      archive.Archive{Algo: archive.AlgoNone},
 				DumpLocation: "local",
 			},
 			expectedContains: []string{
 				"mysqldump",
 				"-h 127.0.0.1",
 				"-u root",
-				"-ppassword",
 				"testdb",
 				"t1 t2",
 			},
@@ -146,14 +191,13 @@ func TestMySQLGenerator_Generate_AllScenarios(t *testing.T) {
 					},
 				},
 				DumpName:     "testdb",
-				Archive:      false,
+				Archive:      archive.Archive{Algo: archive.AlgoNone},
 				DumpLocation: "local",
 			},
 			expectedContains: []string{
 				"mysqldump",
 				"-h 127.0.0.1",
 				"-u root",
-				"-ppassword",
 				"testdb",
 				"--ignore-table=testdb.t1 --ignore-table=testdb.t2",
 			},
@@ -178,7 +222,196 @@ func TestMySQLGenerator_Generate_AllScenarios(t *testing.T) {
 				assert.Contains(t, cmd.Command, expected, "Command should contain %s", expected)
 			}
 
+			assert.NotContains(t, cmd.Command, " -p", "Password must not be on the command line")
+			assert.Equal(t, tt.config.Database.Password, cmd.Env["MYSQL_PWD"])
+
 			assert.Contains(t, cmd.DumpPath, tt.expectedExt, "DumpPath should contain extension %s", tt.expectedExt)
 		})
 	}
 }
+
+func TestMySQLGenerator_Discover(t *testing.T) {
+	cfg := &cmdCfg.Config{
+		Database: cmdCfg.Database{
+			Port:     "3306",
+			User:     "root",
+			Password: "password",
+			Options:  option.Options{Source: mapping.GetDBSource("mysql", "sql")},
+		},
+	}
+
+	gen := mysql.Generator{}
+	cmd, err := gen.Discover(cfg)
+
+	require.NoError(t, err)
+	assert.Equal(t, "mysql -h 127.0.0.1 -P 3306 -u root -N -B -e \""+mysql.SystemQuery+"\"", cmd.Command)
+	assert.Equal(t, "password", cmd.Env["MYSQL_PWD"])
+}
+
+func TestMySQLGenerator_ConsistencyFlags(t *testing.T) {
+	enabled, disabled := true, false
+
+	cfg := &cmdCfg.Config{
+		Database: cmdCfg.Database{
+			Port: "3306",
+			User: "root",
+			Name: "shop",
+			Options: option.Options{
+				Source:            mapping.GetDBSource("mysql", "sql"),
+				SingleTransaction: &enabled,
+				Routines:          true,
+				Triggers:          &disabled,
+				Events:            true,
+				SetGtidPurged:     "off",
+			},
+		},
+		DumpName:     "shop",
+		Archive:      archive.Archive{Algo: archive.AlgoNone},
+		DumpLocation: "server",
+	}
+
+	gen := mysql.Generator{}
+	cmd, err := gen.Generate(cfg)
+
+	require.NoError(t, err)
+	assert.Equal(t,
+		"mysqldump -h 127.0.0.1 -P 3306 -u root --single-transaction --routines --skip-triggers --events "+
+			"--set-gtid-purged=OFF --databases shop > shop.sql",
+		cmd.Command,
+	)
+}
+
+func TestMySQLGenerator_Mydumper(t *testing.T) {
+	enabled := true
+
+	cfg := &cmdCfg.Config{
+		Database: cmdCfg.Database{
+			Port:     "3306",
+			User:     "root",
+			Password: "secret",
+			Name:     "shop",
+			Format:   "mydumper",
+			Options: option.Options{
+				Source:            mapping.GetDBSource("mysql", "mydumper"),
+				Jobs:              8,
+				SingleTransaction: &enabled,
+				Triggers:          &enabled,
+				ExcTables:         []string{"sessions", "audit_log"},
+			},
+		},
+		DumpName:         "/backups/shop",
+		DumpNameTemplate: "shop",
+		DumpDirRemote:    "/backups",
+		Archive:          archive.Archive{Algo: archive.AlgoZstd},
+		DumpLocation:     "server",
+	}
+
+	gen := mysql.Generator{}
+	cmd, err := gen.Generate(cfg)
+
+	require.NoError(t, err)
+	assert.Equal(t,
+		`sh -c 'mydumper -h 127.0.0.1 -P 3306 -u root -B shop -o /backups/shop --threads=8 --trx-consistency-only --triggers `+
+			`--regex '\''^(?!shop\.(sessions|audit_log)$)'\'' && `+
+			`tar -I '\''zstd -q'\'' -cf /backups/shop.tar.zst -C /backups shop && rm -rf /backups/shop'`,
+		cmd.Command,
+	)
+	assert.Equal(t, "/backups/shop.tar.zst", cmd.DumpPath)
+	assert.Equal(t, "secret", cmd.Env["MYSQL_PWD"])
+
+	cfg.Database.Options.ExcTables = nil
+	cfg.Database.Options.IncTables = []string{"orders", "customers"}
+	cmd, err = gen.Generate(cfg)
+
+	require.NoError(t, err)
+	assert.Contains(t, cmd.Command, " -T shop.orders,shop.customers &&")
+}
+
+func TestMySQLGenerator_Xtrabackup(t *testing.T) {
+	cfg := &cmdCfg.Config{
+		Database: cmdCfg.Database{
+			Key:      "shop",
+			Port:     "3306",
+			User:     "backup",
+			Password: "secret",
+			Format:   "xtrabackup",
+			Options:  option.Options{Source: mapping.GetDBSource("mysql", "xtrabackup"), Jobs: 4},
+		},
+		DumpName:      "/backups/shop",
+		DumpDirRemote: "/backups",
+		Archive:       archive.Archive{Algo: archive.AlgoGzip},
+		DumpLocation:  "server",
+	}
+
+	gen := mysql.Generator{}
+
+	t.Run("full", func(t *testing.T) {
+		cmd, err := gen.Generate(cfg)
+
+		require.NoError(t, err)
+		assert.Equal(t,
+			"xtrabackup --backup --stream=xbstream --host=127.0.0.1 --port=3306 --user=backup --parallel=4 "+
+				"| gzip > /backups/shop.xbstream.gz",
+			cmd.Command,
+		)
+		assert.Equal(t, "/backups/shop.xbstream.gz", cmd.DumpPath)
+		assert.Equal(t, "secret", cmd.Env["MYSQL_PWD"])
+	})
+
+	t.Run("incremental over local-ssh", func(t *testing.T) {
+		incCfg := *cfg
+		incCfg.Database.Options.Incremental = true
+		incCfg.DumpLocation = "local-ssh"
+
+		cmd, err := gen.Generate(&incCfg)
+
+		require.NoError(t, err)
+		assert.Equal(t,
+			"sh -c 'rm -rf /backups/shop.lsn.next && mkdir -p /backups/shop.lsn.next && "+
+				"xtrabackup --backup --stream=xbstream --host=127.0.0.1 --port=3306 --user=backup --parallel=4 "+
+				"--extra-lsndir=/backups/shop.lsn.next --target-dir=/backups/shop.lsn.next "+
+				"$([ -f /backups/shop.lsn/xtrabackup_checkpoints ] && echo --incremental-basedir=/backups/shop.lsn)' "+
+				"| gzip",
+			cmd.Command,
+		)
+		assert.Equal(t, "/backups/shop.xbstream.gz", cmd.DumpPath)
+		assert.Equal(t, "sh -c 'rm -rf /backups/shop.lsn && mv /backups/shop.lsn.next /backups/shop.lsn'", cmd.Commit)
+	})
+
+	t.Run("incremental in docker", func(t *testing.T) {
+		enabled := true
+		incCfg := *cfg
+		incCfg.Database.Options.Incremental = true
+		incCfg.Database.Docker = docker.Docker{Command: "docker exec mysql1", Enabled: &enabled}
+
+		cmd, err := gen.Generate(&incCfg)
+		require.NoError(t, err)
+		dockerApp.NewApp(context.Background(), cmd, &incCfg).Prepare()
+
+		assert.Equal(t,
+			"docker exec -e MYSQL_PWD mysql1 sh -c 'rm -rf /backups/shop.lsn.next && mkdir -p /backups/shop.lsn.next && "+
+				"xtrabackup --backup --stream=xbstream --host=127.0.0.1 --port=3306 --user=backup --parallel=4 "+
+				"--extra-lsndir=/backups/shop.lsn.next --target-dir=/backups/shop.lsn.next "+
+				"$([ -f /backups/shop.lsn/xtrabackup_checkpoints ] && echo --incremental-basedir=/backups/shop.lsn)' "+
+				"| gzip > /backups/shop.xbstream.gz",
+			cmd.Command,
+		)
+		assert.Equal(t,
+			"docker exec mysql1 sh -c 'rm -rf /backups/shop.lsn && mv /backups/shop.lsn.next /backups/shop.lsn'",
+			cmd.Commit,
+		)
+	})
+
+	t.Run("lsn dir option", func(t *testing.T) {
+		incCfg := *cfg
+		incCfg.Database.Options.Incremental = true
+		incCfg.Database.Options.LsnDir = "/var/lib/xtrabackup/lsn"
+
+		cmd, err := gen.Generate(&incCfg)
+
+		require.NoError(t, err)
+		assert.Contains(t, cmd.Command, "--incremental-basedir=/var/lib/xtrabackup/lsn)")
+		assert.NotContains(t, cmd.Command, "mv ")
+		assert.Equal(t, "sh -c 'rm -rf /var/lib/xtrabackup/lsn && mv /var/lib/xtrabackup/lsn.next /var/lib/xtrabackup/lsn'", cmd.Commit)
+	})
+}
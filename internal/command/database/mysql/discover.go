@@ -0,0 +1,37 @@
+package mysql
+
+import (
+	commandDomain "dumper/internal/domain/command"
+	cmdCfg "dumper/internal/domain/command-config"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// SystemQuery lists the schemas of the server without the system ones, it
+// is shared with mariadb.
+const SystemQuery = "SELECT schema_name FROM information_schema.schemata " +
+	"WHERE schema_name NOT IN ('mysql', 'information_schema', 'performance_schema', 'sys') ORDER BY schema_name"
+
+// Discover lists the databases with the mysql client next to the dump tool.
+func (g *Generator) Discover(data *cmdCfg.Config) (*commandDomain.DBCommand, error) {
+	return &commandDomain.DBCommand{
+		Command: fmt.Sprintf("%s -h %s -P %s -u %s -N -B -e \"%s\"",
+			Client(data.Database.Options.Source, "mysql"),
+			data.Database.GetHost("127.0.0.1"),
+			data.Database.Port,
+			data.Database.User,
+			SystemQuery,
+		),
+		Env: commandDomain.SecretEnv(map[string]string{"MYSQL_PWD": data.Database.Password}),
+	}, nil
+}
+
+// Client is the command line client installed next to source, the dump
+// tool of any format.
+func Client(source, client string) string {
+	if !strings.Contains(source, "/") {
+		return client
+	}
+	return path.Join(path.Dir(source), client)
+}
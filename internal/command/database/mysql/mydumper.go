@@ -0,0 +1,112 @@
+package mysql
+
+import (
+	commandDomain "dumper/internal/domain/command"
+	cmdCfg "dumper/internal/domain/command-config"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Mydumper dumps the database with mydumper, jobs threads in parallel,
+// into a directory that is tarred like a PostgreSQL directory dump. It is
+// shared with mariadb.
+func Mydumper(data *cmdCfg.Config) *commandDomain.DBCommand {
+	options := &data.Database.Options
+
+	baseCmd := fmt.Sprintf("%s -h %s -P %s -u %s -B %s -o %s",
+		options.Source,
+		data.Database.GetHost("127.0.0.1"),
+		data.Database.Port,
+		data.Database.User,
+		data.Database.Name,
+		data.DumpName,
+	)
+
+	if options.Jobs > 0 {
+		baseCmd += fmt.Sprintf(" --threads=%d", options.Jobs)
+	}
+
+	if options.SingleTransaction != nil && *options.SingleTransaction {
+		baseCmd += " --trx-consistency-only"
+	}
+
+	// mydumper leaves out routines, triggers and events unless asked
+	if options.Routines {
+		baseCmd += " --routines"
+	}
+
+	if options.Triggers != nil && *options.Triggers {
+		baseCmd += " --triggers"
+	}
+
+	if options.Events {
+		baseCmd += " --events"
+	}
+
+	baseCmd += mydumperTables(data.Database.Name, options.IncTables, options.ExcTables)
+
+	archivePath := fmt.Sprintf("%s.%s", data.DumpName, data.Archive.TarExt())
+
+	return &commandDomain.DBCommand{
+		Command: commandDomain.Shell(fmt.Sprintf("%s && %s %s -C %s %s && rm -rf %s",
+			baseCmd,
+			data.Archive.Tar(),
+			archivePath,
+			data.DumpDirRemote,
+			data.DumpNameTemplate,
+			data.DumpName,
+		)),
+		DumpPath: archivePath,
+		Env:      commandDomain.SecretEnv(map[string]string{"MYSQL_PWD": data.Database.Password}),
+	}
+}
+
+// Myloader loads a mydumper dump, the tarball is unpacked from stdin first.
+func Myloader(data *cmdCfg.Config) *commandDomain.DBCommand {
+	options := &data.Database.Options
+	dumpDir := strings.TrimSuffix(data.DumpName, ".tar")
+
+	baseCmd := fmt.Sprintf("%s -h %s -P %s -u %s -d %s -B %s --overwrite-tables",
+		options.Source,
+		data.Database.GetHost("127.0.0.1"),
+		data.Database.Port,
+		data.Database.User,
+		dumpDir,
+		data.Database.Name,
+	)
+
+	if options.Jobs > 0 {
+		baseCmd += fmt.Sprintf(" --threads=%d", options.Jobs)
+	}
+
+	return &commandDomain.DBCommand{
+		Command: commandDomain.Shell(fmt.Sprintf(
+			"mkdir -p %s && tar -xf - -C %s --strip-components=1 && %s && rm -rf %s",
+			dumpDir, dumpDir, baseCmd, dumpDir,
+		)),
+		Env: commandDomain.SecretEnv(map[string]string{"MYSQL_PWD": data.Database.Password}),
+	}
+}
+
+// mydumperTables lists the included tables, or skips the excluded ones with
+// a negative lookahead since mydumper has no flag for a plain list.
+func mydumperTables(database string, incTables, excTables []string) string {
+	if incTables != nil {
+		tables := make([]string, 0, len(incTables))
+		for _, table := range incTables {
+			tables = append(tables, database+"."+table)
+		}
+		return " -T " + strings.Join(tables, ",")
+	}
+
+	if excTables != nil {
+		tables := make([]string, 0, len(excTables))
+		for _, table := range excTables {
+			tables = append(tables, regexp.QuoteMeta(table))
+		}
+		return fmt.Sprintf(" --regex '^(?!%s\\.(%s)$)'", regexp.QuoteMeta(database), strings.Join(tables, "|"))
+	}
+
+	return ""
+}
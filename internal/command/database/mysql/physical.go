@@ -0,0 +1,77 @@
+package mysql
+
+import (
+	commandDomain "dumper/internal/domain/command"
+	cmdCfg "dumper/internal/domain/command-config"
+	"dumper/pkg/utils/template"
+	"fmt"
+)
+
+// Physical streams a hot copy of the server in xbstream with xtrabackup or
+// mariabackup, the tool being the source. It is shared with mariadb.
+//
+// With incremental set, a copy only holds the pages changed since the LSN
+// of the previous one. The checkpoints of the last copy are kept in
+// lsn_dir, a copy is full whenever lsn_dir has none. A copy writes its
+// checkpoints next to lsn_dir, and Commit moves them into lsn_dir once the
+// copy is on every storage.
+func Physical(data *cmdCfg.Config) *commandDomain.DBCommand {
+	options := &data.Database.Options
+
+	baseCmd := fmt.Sprintf("%s --backup --stream=xbstream --host=%s --port=%s --user=%s",
+		options.Source,
+		data.Database.GetHost("127.0.0.1"),
+		data.Database.Port,
+		data.Database.User,
+	)
+
+	if options.Jobs > 0 {
+		baseCmd += fmt.Sprintf(" --parallel=%d", options.Jobs)
+	}
+
+	lsnDir := LsnDir(data)
+	nextDir := lsnDir + ".next"
+
+	if options.Incremental {
+		// one shell, so the checkpoints land where the copy is taken, in
+		// the container too when docker is enabled
+		baseCmd = commandDomain.Shell(fmt.Sprintf(
+			"rm -rf %s && mkdir -p %s && %s --extra-lsndir=%s --target-dir=%s"+
+				" $([ -f %s/xtrabackup_checkpoints ] && echo --incremental-basedir=%s)",
+			nextDir, nextDir, baseCmd, nextDir, nextDir, lsnDir, lsnDir,
+		))
+	}
+
+	ext := "xbstream"
+	if data.Archive.IsEnabled() {
+		baseCmd += data.Archive.Pipe()
+		ext += "." + data.Archive.Ext()
+	}
+
+	remotePath := fmt.Sprintf("%s.%s", data.DumpName, ext)
+
+	if data.DumpLocation != "local-ssh" {
+		baseCmd = fmt.Sprintf("%s > %s", baseCmd, remotePath)
+	}
+
+	cmdDB := &commandDomain.DBCommand{
+		Command:  baseCmd,
+		DumpPath: remotePath,
+		Env:      commandDomain.SecretEnv(map[string]string{"MYSQL_PWD": data.Database.Password}),
+	}
+
+	if options.Incremental {
+		cmdDB.Commit = commandDomain.Shell(fmt.Sprintf("rm -rf %s && mv %s %s", lsnDir, nextDir, lsnDir))
+	}
+
+	return cmdDB
+}
+
+// LsnDir is where the checkpoints of the last physical copy are kept, by
+// default next to the dumps on the server.
+func LsnDir(data *cmdCfg.Config) string {
+	if data.Database.Options.LsnDir != "" {
+		return data.Database.Options.LsnDir
+	}
+	return template.GetFullPath(data.DumpDirRemote, data.Database.Key+".lsn")
+}
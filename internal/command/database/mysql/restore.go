@@ -0,0 +1,33 @@
+package mysql
+
+import (
+	commandDomain "dumper/internal/domain/command"
+	cmdCfg "dumper/internal/domain/command-config"
+	"fmt"
+)
+
+type Restorer struct{}
+
+func (r *Restorer) Restore(data *cmdCfg.Config) (*commandDomain.DBCommand, error) {
+	switch data.Database.Format {
+	case "mydumper":
+		return Myloader(data), nil
+	case "xtrabackup":
+		return nil, fmt.Errorf(
+			"format xtrabackup is a physical copy, prepare it with xtrabackup --prepare and copy it back with the server stopped",
+		)
+	}
+
+	baseCmd := fmt.Sprintf("%s -h %s -P %s -u %s %s",
+		data.Database.Options.Source,
+		data.Database.GetHost("127.0.0.1"),
+		data.Database.Port,
+		data.Database.User,
+		data.Database.Name,
+	)
+
+	return &commandDomain.DBCommand{
+		Command: baseCmd,
+		Env:     commandDomain.SecretEnv(map[string]string{"MYSQL_PWD": data.Database.Password}),
+	}, nil
+}
@@ -0,0 +1,73 @@
+package mysql_test
+
+import (
+	"dumper/internal/command/database/mysql"
+	cmdCfg "dumper/internal/domain/command-config"
+	"dumper/internal/domain/config/option"
+	"dumper/pkg/utils/mapping"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMySQLRestorer_Restore(t *testing.T) {
+	cfg := &cmdCfg.Config{
+		Database: cmdCfg.Database{
+			Port:     "3306",
+			User:     "root",
+			Password: "secret",
+			Name:     "shop",
+			Format:   "sql",
+			Options:  option.Options{Source: mapping.GetRestoreSource("mysql", "sql")},
+		},
+		DumpName: "/tmp/shop.sql",
+	}
+
+	restorer := mysql.Restorer{}
+	cmd, err := restorer.Restore(cfg)
+
+	require.NoError(t, err)
+	assert.Equal(t, "mysql -h 127.0.0.1 -P 3306 -u root shop", cmd.Command)
+	assert.Equal(t, "secret", cmd.Env["MYSQL_PWD"])
+}
+
+func TestMySQLRestorer_Restore_Mydumper(t *testing.T) {
+	cfg := &cmdCfg.Config{
+		Database: cmdCfg.Database{
+			Port:     "3306",
+			User:     "root",
+			Password: "secret",
+			Name:     "shop",
+			Format:   "mydumper",
+			Options:  option.Options{Source: mapping.GetRestoreSource("mysql", "mydumper"), Jobs: 4},
+		},
+		DumpName: "/tmp/shop.tar",
+	}
+
+	restorer := mysql.Restorer{}
+	cmd, err := restorer.Restore(cfg)
+
+	require.NoError(t, err)
+	assert.Equal(t,
+		"sh -c 'mkdir -p /tmp/shop && tar -xf - -C /tmp/shop --strip-components=1 && "+
+			"myloader -h 127.0.0.1 -P 3306 -u root -d /tmp/shop -B shop --overwrite-tables --threads=4 && rm -rf /tmp/shop'",
+		cmd.Command,
+	)
+	assert.Equal(t, "secret", cmd.Env["MYSQL_PWD"])
+}
+
+func TestMySQLRestorer_Restore_Xtrabackup(t *testing.T) {
+	cfg := &cmdCfg.Config{
+		Database: cmdCfg.Database{
+			Format:  "xtrabackup",
+			Options: option.Options{Source: mapping.GetRestoreSource("mysql", "xtrabackup")},
+		},
+		DumpName: "/tmp/shop.xbstream",
+	}
+
+	restorer := mysql.Restorer{}
+	_, err := restorer.Restore(cfg)
+
+	assert.ErrorContains(t, err, "format xtrabackup is a physical copy")
+}
@@ -31,9 +31,9 @@ func (g *Generator) Generate(data *cmdCfg.Config) (*commandDomain.DBCommand, err
 	pathDumpFile := template.GetFullPath(data.DumpDirRemote, nameTmpDumpFile)
 	baseCmd = fmt.Sprintf("%s && mv %s %s", baseCmd, pathDumpFile, fileName)
 
-	if data.Archive {
-		remotePath += ".gz"
-		baseCmd = fmt.Sprintf("%s && gzip -c %s > %s", baseCmd, fileName, remotePath)
+	if data.Archive.IsEnabled() {
+		remotePath += "." + data.Archive.Ext()
+		baseCmd = fmt.Sprintf("%s && %s", baseCmd, data.Archive.File(fileName, remotePath))
 
 		if data.RemoveBackup {
 			baseCmd = fmt.Sprintf("%s && rm %s", baseCmd, fileName)
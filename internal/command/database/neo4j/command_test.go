@@ -4,6 +4,7 @@ import (
 	"dumper/internal/command/database/neo4j"
 	commandDomain "dumper/internal/domain/command"
 	cmdCfg "dumper/internal/domain/command-config"
+	"dumper/internal/domain/config/archive"
 	"dumper/internal/domain/config/option"
 	"dumper/pkg/utils/mapping"
 	"testing"
@@ -28,7 +29,7 @@ func TestNeo4jGenerator_Generate_AllScenarios(t *testing.T) {
 					Options: option.Options{Source: source},
 				},
 				DumpName:      "dump1",
-				Archive:       false,
+				Archive:       archive.Archive{Algo: archive.AlgoNone},
 				DumpLocation:  "local",
 				DumpDirRemote: "/var/lib/neo4j/dumps",
 			},
@@ -47,7 +48,7 @@ func TestNeo4jGenerator_Generate_AllScenarios(t *testing.T) {
 					Options: option.Options{Source: source},
 				},
 				DumpName:      "archive1",
-				Archive:       true,
+				Archive:       archive.Archive{Algo: archive.AlgoGzip},
 				DumpLocation:  "local",
 				DumpDirRemote: "/var/lib/neo4j/dumps",
 			},
@@ -67,7 +68,7 @@ func TestNeo4jGenerator_Generate_AllScenarios(t *testing.T) {
 					Options: option.Options{Source: source},
 				},
 				DumpName:      "serverDump",
-				Archive:       false,
+				Archive:       archive.Archive{Algo: archive.AlgoNone},
 				DumpLocation:  "server",
 				DumpDirRemote: "/var/lib/neo4j/dumps",
 			},
@@ -86,7 +87,7 @@ func TestNeo4jGenerator_Generate_AllScenarios(t *testing.T) {
 					Options: option.Options{Source: source},
 				},
 				DumpName:      "serverArchive",
-				Archive:       true,
+				Archive:       archive.Archive{Algo: archive.AlgoGzip},
 				DumpLocation:  "server",
 				DumpDirRemote: "/var/lib/neo4j/dumps",
 			},
@@ -130,7 +131,7 @@ func TestNeo4jGenerator_CommandIntegrity(t *testing.T) {
 			Options: option.Options{Source: source},
 		},
 		DumpName:     "backup",
-		Archive:      false,
+		Archive:      archive.Archive{Algo: archive.AlgoNone},
 		DumpLocation: "local",
 	}
 
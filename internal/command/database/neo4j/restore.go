@@ -0,0 +1,22 @@
+package neo4j
+
+import (
+	commandDomain "dumper/internal/domain/command"
+	cmdCfg "dumper/internal/domain/command-config"
+	"fmt"
+)
+
+type Restorer struct{}
+
+func (r *Restorer) Restore(data *cmdCfg.Config) (*commandDomain.DBCommand, error) {
+	// Format: neo4j-admin database load <database-name> --from-stdin
+	baseCmd := fmt.Sprintf(
+		"%s database load %s --from-stdin --overwrite-destination=true",
+		data.Database.Options.Source,
+		data.Database.Name,
+	)
+
+	return &commandDomain.DBCommand{
+		Command: baseCmd,
+	}, nil
+}
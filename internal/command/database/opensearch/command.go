@@ -14,14 +14,19 @@ import (
 type Generator struct{}
 
 func (g *Generator) Generate(data *cmdCfg.Config) (*commandDomain.DBCommand, error) {
-	ext := ".tar.gz"
+	ext := "." + data.Archive.TarExt()
 
 	authData := curlAuth(data)
+	curl := data.Database.Options.Source
+	config := curlConfig(data)
+	if config != "" {
+		curl = fmt.Sprintf(`printf '%%s' "$%s" | %s -K -`, curlConfigEnv, curl)
+	}
 	snapshotFullDir := template.GetFullPath(data.Database.Options.SnapPath, data.DumpNameTemplate)
 
 	baseCmd := fmt.Sprintf(
 		"%s -f -X GET %s:%s/_snapshot/%s %s",
-		data.Database.Options.Source,
+		curl,
 		data.Database.Options.Host,
 		data.Database.Port,
 		data.DumpNameTemplate,
@@ -30,7 +35,7 @@ func (g *Generator) Generate(data *cmdCfg.Config) (*commandDomain.DBCommand, err
 
 	baseCmd += fmt.Sprintf(
 		" || %s -X PUT %s:%s/_snapshot/%s -H \"Content-Type: application/json\" -d '%s' %s",
-		data.Database.Options.Source,
+		curl,
 		data.Database.Options.Host,
 		data.Database.Port,
 		data.DumpNameTemplate,
@@ -40,7 +45,7 @@ func (g *Generator) Generate(data *cmdCfg.Config) (*commandDomain.DBCommand, err
 
 	baseCmd += fmt.Sprintf(
 		" && %s -X PUT %s:%s/_snapshot/%s/%s?wait_for_completion=true -H \"Content-Type: application/json\" -d '%s' %s",
-		data.Database.Options.Source,
+		curl,
 		data.Database.Options.Host,
 		data.Database.Port,
 		data.DumpNameTemplate,
@@ -52,7 +57,8 @@ func (g *Generator) Generate(data *cmdCfg.Config) (*commandDomain.DBCommand, err
 	archiveNamePath := fmt.Sprintf("%s%s", data.DumpName, ext)
 
 	baseCmd += fmt.Sprintf(
-		" && tar -czf %s -C %s %s",
+		" && %s %s -C %s %s",
+		data.Archive.Tar(),
 		archiveNamePath,
 		data.Database.Options.SnapPath,
 		data.DumpNameTemplate,
@@ -60,7 +66,7 @@ func (g *Generator) Generate(data *cmdCfg.Config) (*commandDomain.DBCommand, err
 
 	baseCmd += fmt.Sprintf(
 		" && %s -X DELETE %s:%s/_snapshot/%s -H \"Content-Type: application/json\" %s",
-		data.Database.Options.Source,
+		curl,
 		data.Database.Options.Host,
 		data.Database.Port,
 		data.DumpNameTemplate,
@@ -72,24 +78,41 @@ func (g *Generator) Generate(data *cmdCfg.Config) (*commandDomain.DBCommand, err
 		snapshotFullDir,
 	)
 
+	// the snapshot is written where the node runs, so the whole chain runs
+	// in one shell, in the container too when docker is enabled
 	return &commandDomain.DBCommand{
-		Command:  baseCmd,
+		Command:  commandDomain.Shell(baseCmd),
 		DumpPath: archiveNamePath,
+		Env:      commandDomain.SecretEnv(map[string]string{curlConfigEnv: config}),
 	}, nil
 }
 
-func curlAuth(data *cmdCfg.Config) string {
+// curlConfigEnv carries the secret curl options, which curl reads as a
+// config file from stdin so they never show up in the process list.
+const curlConfigEnv = "DUMPER_CURL_CONFIG"
 
-	auth := ""
+func curlConfig(data *cmdCfg.Config) string {
+	config := ""
 
 	if data.Database.User != "" {
-		auth += fmt.Sprintf("-u %s:%s ", data.Database.User, data.Database.Password)
+		config += commandDomain.CurlConfigLine("user", data.Database.User+":"+data.Database.Password)
 	}
 
 	if data.Database.Token != "" {
-		auth += fmt.Sprintf(`-H "Authorization: %s" `, data.Database.Token)
+		config += commandDomain.CurlConfigLine("header", "Authorization: "+data.Database.Token)
 	}
 
+	if data.Database.Options.CertPath != "" && data.Database.Options.KeyPass != "" {
+		config += commandDomain.CurlConfigLine("pass", data.Database.Options.KeyPass)
+	}
+
+	return config
+}
+
+func curlAuth(data *cmdCfg.Config) string {
+
+	auth := ""
+
 	if data.Database.Options.CACertPath != "" {
 		auth += fmt.Sprintf("--cacert %s ", data.Database.Options.CACertPath)
 	}
@@ -99,18 +122,10 @@ func curlAuth(data *cmdCfg.Config) string {
 	}
 
 	if data.Database.Options.CertPath != "" {
-		if data.Database.Options.KeyPass != "" {
-			auth += fmt.Sprintf(
-				"--cert %s:%s ",
-				data.Database.Options.CertPath,
-				data.Database.Options.KeyPass,
-			)
-		} else {
-			auth += fmt.Sprintf("--cert %s ", data.Database.Options.CertPath)
-		}
+		auth += fmt.Sprintf("--cert %s ", data.Database.Options.CertPath)
 	}
 
-	if len(auth) == 0 {
+	if len(auth) == 0 && data.Database.User == "" && data.Database.Token == "" {
 		auth += "-k "
 	}
 
@@ -1,10 +1,15 @@
 package opensearch_test
 
 import (
+	"context"
 	"dumper/internal/command/database/opensearch"
+	dockerApp "dumper/internal/docker"
 	commandDomain "dumper/internal/domain/command"
 	cmdCfg "dumper/internal/domain/command-config"
+	"dumper/internal/domain/config/archive"
+	"dumper/internal/domain/config/docker"
 	"dumper/internal/domain/config/option"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -18,6 +23,7 @@ func TestOpenSearchGenerator_Generate_Variations(t *testing.T) {
 		name             string
 		config           *cmdCfg.Config
 		expectedContains []string
+		expectedConfig   string
 		expectedArchive  string
 	}{
 		{
@@ -32,6 +38,7 @@ func TestOpenSearchGenerator_Generate_Variations(t *testing.T) {
 					},
 				},
 				DumpName:         "dump",
+				Archive:          archive.Archive{Algo: archive.AlgoGzip},
 				DumpNameTemplate: "repo",
 			},
 			expectedContains: []string{
@@ -56,11 +63,13 @@ func TestOpenSearchGenerator_Generate_Variations(t *testing.T) {
 					},
 				},
 				DumpName:         "secure",
+				Archive:          archive.Archive{Algo: archive.AlgoGzip},
 				DumpNameTemplate: "repo_secure",
 			},
 			expectedContains: []string{
-				"-u admin:secret",
+				`sh -c 'printf '\''%s'\'' "$DUMPER_CURL_CONFIG" | curl -K - -f -X GET https://node:9200/_snapshot/repo_secure`,
 			},
+			expectedConfig:  "user = \"admin:secret\"\n",
 			expectedArchive: "secure.tar.gz",
 		},
 		{
@@ -76,11 +85,13 @@ func TestOpenSearchGenerator_Generate_Variations(t *testing.T) {
 					},
 				},
 				DumpName:         "token_dump",
+				Archive:          archive.Archive{Algo: archive.AlgoGzip},
 				DumpNameTemplate: "repo_token",
 			},
 			expectedContains: []string{
-				`Authorization: Bearer abc123`,
+				"curl -K -",
 			},
+			expectedConfig:  "header = \"Authorization: Bearer abc123\"\n",
 			expectedArchive: "token_dump.tar.gz",
 		},
 		{
@@ -99,13 +110,15 @@ func TestOpenSearchGenerator_Generate_Variations(t *testing.T) {
 					},
 				},
 				DumpName:         "cert_dump",
+				Archive:          archive.Archive{Algo: archive.AlgoGzip},
 				DumpNameTemplate: "repo_cert",
 			},
 			expectedContains: []string{
 				"--cacert /certs/ca.crt",
 				"--key /certs/private.key",
-				"--cert /certs/crt.crt:keypass",
+				"--cert /certs/crt.crt ",
 			},
+			expectedConfig:  "pass = \"keypass\"\n",
 			expectedArchive: "cert_dump.tar.gz",
 		},
 		{
@@ -120,6 +133,7 @@ func TestOpenSearchGenerator_Generate_Variations(t *testing.T) {
 					},
 				},
 				DumpName:         "fallback",
+				Archive:          archive.Archive{Algo: archive.AlgoGzip},
 				DumpNameTemplate: "repo_fb",
 			},
 			expectedContains: []string{
@@ -142,6 +156,7 @@ func TestOpenSearchGenerator_Generate_Variations(t *testing.T) {
 					},
 				},
 				DumpName:         "indices_backup",
+				Archive:          archive.Archive{Algo: archive.AlgoGzip},
 				DumpNameTemplate: "repo_indices",
 			},
 			expectedContains: []string{
@@ -171,6 +186,11 @@ func TestOpenSearchGenerator_Generate_Variations(t *testing.T) {
 			}
 
 			assert.Equal(t, tt.expectedArchive, cmd.DumpPath)
+
+			assert.Equal(t, tt.expectedConfig, cmd.Env["DUMPER_CURL_CONFIG"])
+			assert.NotContains(t, cmd.Command, "secret", "Credentials must not be on the command line")
+			assert.NotContains(t, cmd.Command, "abc123", "Credentials must not be on the command line")
+			assert.NotContains(t, cmd.Command, "keypass", "Credentials must not be on the command line")
 		})
 	}
 }
@@ -187,6 +207,7 @@ func TestOpenSearchGenerator_CommandIntegrity(t *testing.T) {
 			},
 		},
 		DumpName:         "prod_backup",
+		Archive:          archive.Archive{Algo: archive.AlgoGzip},
 		DumpNameTemplate: "repo_prod",
 	}
 
@@ -210,3 +231,36 @@ func TestOpenSearchGenerator_CommandIntegrity(t *testing.T) {
 
 	assert.Equal(t, "prod_backup.tar.gz", cmd.DumpPath)
 }
+
+func TestOpenSearchGenerator_Docker(t *testing.T) {
+	enabled := true
+	cfg := &cmdCfg.Config{
+		Database: cmdCfg.Database{
+			User:     "admin",
+			Password: "secret",
+			Port:     "9200",
+			Docker:   docker.Docker{Command: "docker exec node1", Enabled: &enabled},
+			Options: option.Options{
+				Source:   "curl",
+				Host:     "https://localhost",
+				SnapPath: "/data",
+			},
+		},
+		DumpName:         "prod_backup",
+		Archive:          archive.Archive{Algo: archive.AlgoGzip},
+		DumpNameTemplate: "repo_prod",
+	}
+
+	gen := opensearch.Generator{}
+
+	cmd, err := gen.Generate(cfg)
+	require.NoError(t, err)
+	dockerApp.NewApp(context.Background(), cmd, cfg).Prepare()
+
+	// every curl, the tar and the rm run in the container
+	assert.True(t, strings.HasPrefix(cmd.Command,
+		`docker exec -e DUMPER_CURL_CONFIG node1 sh -c 'printf '\''%s'\'' "$DUMPER_CURL_CONFIG" | curl -K - -f -X GET `,
+	), cmd.Command)
+	assert.True(t, strings.HasSuffix(cmd.Command, "&& rm -Rf /data/repo_prod'"), cmd.Command)
+	assert.Equal(t, 1, strings.Count(cmd.Command, "docker exec"))
+}
@@ -1,15 +1,22 @@
 package postgres
 
 import (
+	backupDomain "dumper/internal/domain/backup"
 	commandDomain "dumper/internal/domain/command"
 	commandConfig "dumper/internal/domain/command-config"
 	"dumper/internal/domain/config/option"
 	"fmt"
+	"path"
+	"strings"
 )
 
 type Generator struct{}
 
 func (g *Generator) Generate(data *commandConfig.Config) (*commandDomain.DBCommand, error) {
+	if data.Database.Format == "basebackup" {
+		return basebackup(data), nil
+	}
+
 	formatFlag := "-Fp" // plain SQL
 	ext := "sql"
 
@@ -20,58 +27,202 @@ func (g *Generator) Generate(data *commandConfig.Config) (*commandDomain.DBComma
 	case "tar":
 		formatFlag = "-Ft"
 		ext = "tar"
+	case "directory":
+		formatFlag = "-Fd"
 	}
 
 	tables := prepareTables(&data.Database.Options)
 
 	baseCmd := fmt.Sprintf(
-		"%s --dbname=postgresql://%s:%s@127.0.0.1:%s/%s %s --clean --if-exists --no-owner %s",
+		"%s --dbname=%s%s --clean --if-exists --no-owner %s",
 		data.Database.Options.Source,
-		data.Database.User,
-		data.Database.Password,
-		data.Database.Port,
-		data.Database.Name,
+		dbURL(data, data.Database.Name),
 		tables,
 		formatFlag,
 	)
 
-	if data.Archive && formatFlag == "-Fp" { // gzip only for plain
-		baseCmd += " | gzip"
-		ext += ".gz"
+	env := commandDomain.SecretEnv(map[string]string{"PGPASSWORD": data.Database.Password})
+
+	// a directory dump is tarred so it uploads as a single file. The chain
+	// runs in one shell, so with docker enabled tar and rm find the
+	// directory pg_dump wrote in the container.
+	if formatFlag == "-Fd" {
+		if data.Database.Options.Jobs > 0 {
+			baseCmd += fmt.Sprintf(" --jobs=%d", data.Database.Options.Jobs)
+		}
+
+		dumpDir := data.DumpName
+		archivePath := fmt.Sprintf("%s.%s", data.DumpName, data.Archive.TarExt())
+
+		baseCmd = commandDomain.Shell(fmt.Sprintf("%s -f %s && %s %s -C %s %s && rm -rf %s",
+			baseCmd,
+			dumpDir,
+			data.Archive.Tar(),
+			archivePath,
+			data.DumpDirRemote,
+			data.DumpNameTemplate,
+			dumpDir,
+		))
+
+		return &commandDomain.DBCommand{
+			Command:  baseCmd,
+			DumpPath: archivePath,
+			Env:      env,
+		}, nil
+	}
+
+	if data.Archive.IsEnabled() && formatFlag == "-Fp" { // custom and tar are left to pg_dump
+		baseCmd += data.Archive.Pipe()
+		ext += "." + data.Archive.Ext()
 	}
 
 	fileName := fmt.Sprintf("%s.%s", data.DumpName, ext)
 	remotePath := fmt.Sprintf("%s", fileName)
 
-	if data.DumpLocation == "server" {
+	if data.DumpLocation == "local-ssh" {
 		return &commandDomain.DBCommand{
-			Command:  fmt.Sprintf("%s > %s", baseCmd, remotePath),
+			Command:  baseCmd,
 			DumpPath: remotePath,
+			Env:      env,
 		}, nil
 	}
+
 	return &commandDomain.DBCommand{
-		Command:  baseCmd,
+		Command:  fmt.Sprintf("%s > %s", baseCmd, remotePath),
 		DumpPath: remotePath,
+		Env:      env,
 	}, nil
 
 }
 
+// Companions dumps the roles and tablespaces with pg_dumpall when globals
+// are enabled, pg_dump leaves them out of every format.
+func (g *Generator) Companions(data *commandConfig.Config) ([]*commandDomain.DBCommand, error) {
+	// a base backup carries the roles and tablespaces already
+	if !data.Database.Options.Globals || data.Database.Format == "basebackup" {
+		return nil, nil
+	}
+
+	baseCmd := fmt.Sprintf(
+		"%s --dbname=%s --globals-only",
+		sibling(data.Database.Options.Source, "pg_dump", "pg_dumpall"),
+		dbURL(data, "postgres"),
+	)
+
+	remotePath := fmt.Sprintf("%s.%s", data.DumpName, backupDomain.ExtGlobals)
+	if data.Archive.IsEnabled() {
+		baseCmd += data.Archive.Pipe()
+		remotePath += "." + data.Archive.Ext()
+	}
+
+	if data.DumpLocation != "local-ssh" {
+		baseCmd = fmt.Sprintf("%s > %s", baseCmd, remotePath)
+	}
+
+	return []*commandDomain.DBCommand{{
+		Command:  baseCmd,
+		DumpPath: remotePath,
+		Env:      commandDomain.SecretEnv(map[string]string{"PGPASSWORD": data.Database.Password}),
+	}}, nil
+}
+
+// basebackup copies the whole cluster with pg_basebackup. The WAL needed to
+// make the copy consistent is streamed alongside, and the base and pg_wal
+// tarballs are packed into a single file like a directory dump, in the
+// same shell.
+func basebackup(data *commandConfig.Config) *commandDomain.DBCommand {
+	archivePath := fmt.Sprintf("%s.%s", data.DumpName, data.Archive.TarExt())
+
+	return &commandDomain.DBCommand{
+		Command: commandDomain.Shell(fmt.Sprintf(
+			"%s --dbname=%s --label=%s -D %s -Ft -X stream && %s %s -C %s %s && rm -rf %s",
+			data.Database.Options.Source,
+			dbURL(data, ""),
+			data.DumpNameTemplate,
+			data.DumpName,
+			data.Archive.Tar(),
+			archivePath,
+			data.DumpDirRemote,
+			data.DumpNameTemplate,
+			data.DumpName,
+		)),
+		DumpPath: archivePath,
+		Env:      commandDomain.SecretEnv(map[string]string{"PGPASSWORD": data.Database.Password}),
+	}
+}
+
+func dbURL(data *commandConfig.Config, name string) string {
+	return fmt.Sprintf(
+		"postgresql://%s@%s:%s/%s",
+		data.Database.User,
+		data.Database.GetHost("127.0.0.1"),
+		data.Database.Port,
+		name,
+	)
+}
+
+// sibling swaps the tool from for the tool to next to it, so a versioned
+// binary path such as /usr/lib/postgresql/16/bin stays on the same version.
+func sibling(source, from, to string) string {
+	switch path.Base(source) {
+	case to:
+		return source
+	case from:
+		return path.Join(path.Dir(source), to)
+	default:
+		return to
+	}
+}
+
 func prepareTables(
 	options *option.Options,
 ) string {
 	out := ""
 
+	for _, schema := range options.IncSchemas {
+		out += fmt.Sprintf(" --schema=%s", quotePattern(schema))
+	}
+
+	for _, schema := range options.ExcSchemas {
+		out += fmt.Sprintf(" --exclude-schema=%s", quotePattern(schema))
+	}
+
 	if options.IncTables != nil {
 		for _, table := range options.IncTables {
-			out += fmt.Sprintf(" %s%s%s", "--table=", "public.", table)
+			out += fmt.Sprintf(" %s%s", "--table=", qualify(table))
 		}
 	}
 
 	if options.IncTables == nil && options.ExcTables != nil {
 		for _, table := range options.ExcTables {
-			out += fmt.Sprintf(" %s%s%s", "--exclude-table=", "public.", table)
+			out += fmt.Sprintf(" %s%s", "--exclude-table=", qualify(table))
 		}
 	}
 
+	for _, table := range options.ExcTableData {
+		out += fmt.Sprintf(" --exclude-table-data=%s", qualify(table))
+	}
+
+	for _, section := range options.Sections {
+		out += fmt.Sprintf(" --section=%s", section)
+	}
+
 	return out
 }
+
+// qualify puts bare table names into the public schema, schema.table
+// patterns are passed on as written.
+func qualify(table string) string {
+	if !strings.Contains(table, ".") {
+		table = "public." + table
+	}
+	return quotePattern(table)
+}
+
+// quotePattern keeps the shell away from the wildcards of a pg_dump pattern.
+func quotePattern(pattern string) string {
+	if strings.ContainsAny(pattern, "*?[]\"$ ") {
+		return "'" + strings.ReplaceAll(pattern, "'", `'\''`) + "'"
+	}
+	return pattern
+}
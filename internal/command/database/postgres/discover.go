@@ -0,0 +1,20 @@
+package postgres
+
+import (
+	commandDomain "dumper/internal/domain/command"
+	commandConfig "dumper/internal/domain/command-config"
+	"fmt"
+)
+
+// Discover lists the databases of the cluster that accept connections,
+// the templates are left out.
+func (g *Generator) Discover(data *commandConfig.Config) (*commandDomain.DBCommand, error) {
+	return &commandDomain.DBCommand{
+		Command: fmt.Sprintf(
+			"%s --dbname=%s -Atc \"SELECT datname FROM pg_database WHERE NOT datistemplate AND datallowconn ORDER BY datname\"",
+			sibling(data.Database.Options.Source, "pg_dump", "psql"),
+			dbURL(data, "postgres"),
+		),
+		Env: commandDomain.SecretEnv(map[string]string{"PGPASSWORD": data.Database.Password}),
+	}, nil
+}
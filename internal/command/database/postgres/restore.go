@@ -0,0 +1,72 @@
+package postgres
+
+import (
+	backupDomain "dumper/internal/domain/backup"
+	commandDomain "dumper/internal/domain/command"
+	commandConfig "dumper/internal/domain/command-config"
+	"fmt"
+	"strings"
+)
+
+type Restorer struct{}
+
+func (r *Restorer) Restore(data *commandConfig.Config) (*commandDomain.DBCommand, error) {
+	if data.Database.Format == "basebackup" {
+		return nil, fmt.Errorf(
+			"format basebackup is a physical copy, unpack base.tar into an empty data directory " +
+				"and pg_wal.tar into its pg_wal with the server stopped",
+		)
+	}
+
+	env := commandDomain.SecretEnv(map[string]string{"PGPASSWORD": data.Database.Password})
+
+	// roles that already exist only raise errors, the rest still applies
+	if strings.HasSuffix(data.DumpName, "."+backupDomain.ExtGlobals) {
+		return &commandDomain.DBCommand{
+			Command: fmt.Sprintf(
+				"%s --dbname=%s",
+				sibling(data.Database.Options.Source, "pg_restore", "psql"),
+				dbURL(data, "postgres"),
+			),
+			Env: env,
+		}, nil
+	}
+
+	baseCmd := fmt.Sprintf(
+		"%s --dbname=%s -v ON_ERROR_STOP=1",
+		data.Database.Options.Source,
+		dbURL(data, data.Database.Name),
+	)
+
+	switch data.Database.Format {
+	case "dump", "tar":
+		baseCmd = fmt.Sprintf(
+			"%s --dbname=%s --clean --if-exists --no-owner",
+			data.Database.Options.Source,
+			dbURL(data, data.Database.Name),
+		)
+	case "directory":
+		dumpDir := strings.TrimSuffix(data.DumpName, ".tar")
+
+		baseCmd = fmt.Sprintf(
+			"%s --dbname=%s --clean --if-exists --no-owner",
+			data.Database.Options.Source,
+			dbURL(data, data.Database.Name),
+		)
+		if data.Database.Options.Jobs > 0 {
+			baseCmd += fmt.Sprintf(" --jobs=%d", data.Database.Options.Jobs)
+		}
+
+		// one shell, so with docker enabled the dump is unpacked where
+		// pg_restore reads it
+		baseCmd = commandDomain.Shell(fmt.Sprintf(
+			"mkdir -p %s && tar -xf - -C %s --strip-components=1 && %s -Fd %s && rm -rf %s",
+			dumpDir, dumpDir, baseCmd, dumpDir, dumpDir,
+		))
+	}
+
+	return &commandDomain.DBCommand{
+		Command: baseCmd,
+		Env:     env,
+	}, nil
+}
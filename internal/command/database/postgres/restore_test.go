@@ -0,0 +1,121 @@
+package postgres_test
+
+import (
+	"dumper/internal/command/database/postgres"
+	cmdCfg "dumper/internal/domain/command-config"
+	"dumper/internal/domain/config/option"
+	"dumper/pkg/utils/mapping"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPSQLRestorer_Restore(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		wantCmd string
+	}{
+		{
+			name:    "plain SQL is piped into psql",
+			format:  "plain",
+			wantCmd: "psql --dbname=postgresql://postgres@127.0.0.1:5432/testdb -v ON_ERROR_STOP=1",
+		},
+		{
+			name:    "custom format goes through pg_restore",
+			format:  "dump",
+			wantCmd: "pg_restore --dbname=postgresql://postgres@127.0.0.1:5432/testdb --clean --if-exists --no-owner",
+		},
+		{
+			name:    "tar format goes through pg_restore",
+			format:  "tar",
+			wantCmd: "pg_restore --dbname=postgresql://postgres@127.0.0.1:5432/testdb --clean --if-exists --no-owner",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &cmdCfg.Config{
+				Database: cmdCfg.Database{
+					Format:   tt.format,
+					User:     "postgres",
+					Password: "pass",
+					Port:     "5432",
+					Name:     "testdb",
+					Options:  option.Options{Source: mapping.GetRestoreSource("psql", tt.format)},
+				},
+				DumpName: "/tmp/testdb.sql",
+			}
+
+			restorer := postgres.Restorer{}
+			cmd, err := restorer.Restore(cfg)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantCmd, cmd.Command)
+			assert.Empty(t, cmd.DumpPath)
+			assert.Equal(t, map[string]string{"PGPASSWORD": "pass"}, cmd.Env)
+		})
+	}
+}
+
+func TestPSQLRestorer_Restore_Directory(t *testing.T) {
+	cfg := &cmdCfg.Config{
+		Database: cmdCfg.Database{
+			Format:   "directory",
+			User:     "postgres",
+			Password: "pass",
+			Port:     "5432",
+			Name:     "testdb",
+			Options:  option.Options{Source: mapping.GetRestoreSource("psql", "directory"), Jobs: 4},
+		},
+		DumpName: "/tmp/testdb.tar",
+	}
+
+	restorer := postgres.Restorer{}
+	cmd, err := restorer.Restore(cfg)
+
+	require.NoError(t, err)
+	assert.Equal(t,
+		"sh -c 'mkdir -p /tmp/testdb && tar -xf - -C /tmp/testdb --strip-components=1 && "+
+			"pg_restore --dbname=postgresql://postgres@127.0.0.1:5432/testdb --clean --if-exists --no-owner --jobs=4 -Fd /tmp/testdb && rm -rf /tmp/testdb'",
+		cmd.Command,
+	)
+	assert.Empty(t, cmd.DumpPath)
+}
+
+func TestPSQLRestorer_Restore_Globals(t *testing.T) {
+	cfg := &cmdCfg.Config{
+		Database: cmdCfg.Database{
+			Format:   "dump",
+			User:     "postgres",
+			Password: "pass",
+			Port:     "5432",
+			Name:     "testdb",
+			Options:  option.Options{Source: "/usr/lib/postgresql/16/bin/pg_restore"},
+		},
+		DumpName: "/tmp/prod.globals.sql",
+	}
+
+	restorer := postgres.Restorer{}
+	cmd, err := restorer.Restore(cfg)
+
+	require.NoError(t, err)
+	assert.Equal(t, "/usr/lib/postgresql/16/bin/psql --dbname=postgresql://postgres@127.0.0.1:5432/postgres", cmd.Command)
+	assert.Equal(t, map[string]string{"PGPASSWORD": "pass"}, cmd.Env)
+}
+
+func TestPSQLRestorer_Restore_Basebackup(t *testing.T) {
+	cfg := &cmdCfg.Config{
+		Database: cmdCfg.Database{
+			Format:  "basebackup",
+			Options: option.Options{Source: mapping.GetRestoreSource("psql", "basebackup")},
+		},
+		DumpName: "/tmp/prod_base.tar.gz",
+	}
+
+	restorer := postgres.Restorer{}
+	_, err := restorer.Restore(cfg)
+
+	assert.ErrorContains(t, err, "format basebackup is a physical copy")
+}
@@ -14,37 +14,47 @@ func (g *Generator) Generate(data *cmdCfg.Config) (*commandDomain.DBCommand, err
 	fileName := fmt.Sprintf("%s.%s", data.DumpName, ext)
 	remotePath := fmt.Sprintf("%s", fileName)
 
-	host := "127.0.0.1"
+	host := data.Database.GetHost("127.0.0.1")
 
 	baseCmd := fmt.Sprintf(
-		"%s -h %s -p %s -a %s --rdb",
-		data.Database.Options.Source, host, data.Database.Port, data.Database.Password,
+		"%s -h %s -p %s --rdb",
+		data.Database.Options.Source, host, data.Database.Port,
 	)
 
 	if data.Database.Options.Mode == "save" {
 		saveCmd := fmt.Sprintf(
-			"%s -h %s -p %s -a %s SAVE && ",
-			data.Database.Options.Source, host, data.Database.Port, data.Database.Password,
+			"%s -h %s -p %s SAVE && ",
+			data.Database.Options.Source, host, data.Database.Port,
 		)
 		baseCmd = saveCmd + baseCmd
 	}
 
-	if data.Archive {
-		remotePath += ".gz"
-		baseCmd = fmt.Sprintf("%s - | gzip > %s", baseCmd, remotePath)
-	} else {
-		baseCmd = fmt.Sprintf("%s %s", baseCmd, remotePath)
-	}
+	env := commandDomain.SecretEnv(map[string]string{"REDISCLI_AUTH": data.Database.Password})
+
+	if data.DumpLocation == "local-ssh" {
+		baseCmd += " -"
+		if data.Archive.IsEnabled() {
+			remotePath += "." + data.Archive.Ext()
+			baseCmd += data.Archive.Pipe()
+		}
 
-	if data.DumpLocation == "server" {
 		return &commandDomain.DBCommand{
 			Command:  baseCmd,
 			DumpPath: remotePath,
+			Env:      env,
 		}, nil
 	}
 
+	if data.Archive.IsEnabled() {
+		remotePath += "." + data.Archive.Ext()
+		baseCmd = fmt.Sprintf("%s -%s > %s", baseCmd, data.Archive.Pipe(), remotePath)
+	} else {
+		baseCmd = fmt.Sprintf("%s %s", baseCmd, remotePath)
+	}
+
 	return &commandDomain.DBCommand{
 		Command:  baseCmd,
 		DumpPath: remotePath,
+		Env:      env,
 	}, nil
 }
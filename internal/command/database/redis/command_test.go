@@ -4,6 +4,7 @@ import (
 	"dumper/internal/command/database/redis"
 	commandDomain "dumper/internal/domain/command"
 	cmdCfg "dumper/internal/domain/command-config"
+	"dumper/internal/domain/config/archive"
 	"dumper/internal/domain/config/option"
 	"dumper/pkg/utils/mapping"
 	"testing"
@@ -29,7 +30,7 @@ func TestRedisGenerator_Generate_AllScenarios(t *testing.T) {
 					Options:  option.Options{Mode: "", Source: source},
 				},
 				DumpName:     "dump1",
-				Archive:      false,
+				Archive:      archive.Archive{Algo: archive.AlgoNone},
 				DumpLocation: "local",
 			},
 			expectedContains: []string{
@@ -48,7 +49,7 @@ func TestRedisGenerator_Generate_AllScenarios(t *testing.T) {
 					Options:  option.Options{Mode: "save", Source: source},
 				},
 				DumpName:     "dump2",
-				Archive:      false,
+				Archive:      archive.Archive{Algo: archive.AlgoNone},
 				DumpLocation: "local",
 			},
 			expectedContains: []string{
@@ -67,7 +68,7 @@ func TestRedisGenerator_Generate_AllScenarios(t *testing.T) {
 					Options:  option.Options{Mode: "", Source: source},
 				},
 				DumpName:     "archive1",
-				Archive:      true,
+				Archive:      archive.Archive{Algo: archive.AlgoGzip},
 				DumpLocation: "local",
 			},
 			expectedContains: []string{
@@ -86,7 +87,7 @@ func TestRedisGenerator_Generate_AllScenarios(t *testing.T) {
 					Options:  option.Options{Mode: "save", Source: source},
 				},
 				DumpName:     "archive2",
-				Archive:      true,
+				Archive:      archive.Archive{Algo: archive.AlgoGzip},
 				DumpLocation: "local",
 			},
 			expectedContains: []string{
@@ -106,7 +107,7 @@ func TestRedisGenerator_Generate_AllScenarios(t *testing.T) {
 					Options:  option.Options{Mode: "", Source: source},
 				},
 				DumpName:     "serverDump",
-				Archive:      false,
+				Archive:      archive.Archive{Algo: archive.AlgoNone},
 				DumpLocation: "server",
 			},
 			expectedContains: []string{
@@ -124,7 +125,7 @@ func TestRedisGenerator_Generate_AllScenarios(t *testing.T) {
 					Options:  option.Options{Mode: "save", Source: source},
 				},
 				DumpName:     "serverArchive",
-				Archive:      true,
+				Archive:      archive.Archive{Algo: archive.AlgoGzip},
 				DumpLocation: "server",
 			},
 			expectedContains: []string{
@@ -153,7 +154,31 @@ func TestRedisGenerator_Generate_AllScenarios(t *testing.T) {
 				assert.Contains(t, cmd.Command, expected, "Command should contain %s", expected)
 			}
 
+			assert.NotContains(t, cmd.Command, " -a ", "Password must not be on the command line")
+			assert.Equal(t, tt.config.Database.Password, cmd.Env["REDISCLI_AUTH"])
+
 			assert.Contains(t, cmd.DumpPath, tt.expectedExt, "DumpPath should contain extension %s", tt.expectedExt)
 		})
 	}
 }
+
+func TestRedisGenerator_Generate_LocalSSH(t *testing.T) {
+	cfg := &cmdCfg.Config{
+		Database: cmdCfg.Database{
+			Port:     "6379",
+			Password: "pass",
+			Options:  option.Options{Source: mapping.GetDBSource("redis", "")},
+		},
+		DumpName:     "stream",
+		Archive:      archive.Archive{Algo: archive.AlgoGzip},
+		DumpLocation: "local-ssh",
+	}
+
+	gen := redis.Generator{}
+	cmd, err := gen.Generate(cfg)
+
+	require.NoError(t, err)
+	assert.Contains(t, cmd.Command, "--rdb - | gzip")
+	assert.NotContains(t, cmd.Command, ">", "streamed dump must not be written to the server")
+	assert.Equal(t, "stream.rdb.gz", cmd.DumpPath)
+}
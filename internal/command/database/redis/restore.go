@@ -0,0 +1,31 @@
+package redis
+
+import (
+	commandDomain "dumper/internal/domain/command"
+	cmdCfg "dumper/internal/domain/command-config"
+	"fmt"
+)
+
+type Restorer struct{}
+
+// Restore places the RDB file into the data directory of the running server.
+// Redis only loads it on start, so the server has to be restarted without
+// saving its current dataset for the restore to take effect.
+func (r *Restorer) Restore(data *cmdCfg.Config) (*commandDomain.DBCommand, error) {
+	host := data.Database.GetHost("127.0.0.1")
+	cli := fmt.Sprintf(
+		"%s -h %s -p %s --raw",
+		data.Database.Options.Source, host, data.Database.Port,
+	)
+
+	baseCmd := fmt.Sprintf(
+		"cp %s \"$(%s CONFIG GET dir | tail -n 1)/$(%s CONFIG GET dbfilename | tail -n 1)\"",
+		data.DumpName, cli, cli,
+	)
+
+	return &commandDomain.DBCommand{
+		Command:  baseCmd,
+		DumpPath: data.DumpName,
+		Env:      commandDomain.SecretEnv(map[string]string{"REDISCLI_AUTH": data.Database.Password}),
+	}, nil
+}
@@ -19,14 +19,12 @@ func (g *Generator) Generate(data *cmdCfg.Config) (*commandDomain.DBCommand, err
 		data.Database.Options.Path,
 	)
 
-	if data.Archive {
-		remotePath += ".gz"
-		baseCmd = fmt.Sprintf("%s | gzip > %s", baseCmd, remotePath)
-	} else {
-		baseCmd = fmt.Sprintf("%s > %s", baseCmd, remotePath)
+	if data.Archive.IsEnabled() {
+		remotePath += "." + data.Archive.Ext()
+		baseCmd += data.Archive.Pipe()
 	}
 
-	if data.DumpLocation == "server" {
+	if data.DumpLocation == "local-ssh" {
 		return &commandDomain.DBCommand{
 			Command:  baseCmd,
 			DumpPath: remotePath,
@@ -34,7 +32,7 @@ func (g *Generator) Generate(data *cmdCfg.Config) (*commandDomain.DBCommand, err
 	}
 
 	return &commandDomain.DBCommand{
-		Command:  baseCmd,
+		Command:  fmt.Sprintf("%s > %s", baseCmd, remotePath),
 		DumpPath: remotePath,
 	}, nil
 }
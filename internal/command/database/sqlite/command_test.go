@@ -4,6 +4,7 @@ import (
 	"dumper/internal/command/database/sqlite"
 	commandDomain "dumper/internal/domain/command"
 	cmdCfg "dumper/internal/domain/command-config"
+	"dumper/internal/domain/config/archive"
 	"dumper/internal/domain/config/option"
 	"dumper/pkg/utils/mapping"
 	"testing"
@@ -27,7 +28,7 @@ func TestSQLiteGenerator_Generate_AllScenarios(t *testing.T) {
 					Options: option.Options{Path: "test.db", Source: source},
 				},
 				DumpName:     "dump1",
-				Archive:      false,
+				Archive:      archive.Archive{Algo: archive.AlgoNone},
 				DumpLocation: "local",
 			},
 			expectedContains: []string{
@@ -43,7 +44,7 @@ func TestSQLiteGenerator_Generate_AllScenarios(t *testing.T) {
 					Options: option.Options{Path: "archive.db", Source: source},
 				},
 				DumpName:     "archive1",
-				Archive:      true,
+				Archive:      archive.Archive{Algo: archive.AlgoGzip},
 				DumpLocation: "local",
 			},
 			expectedContains: []string{
@@ -60,7 +61,7 @@ func TestSQLiteGenerator_Generate_AllScenarios(t *testing.T) {
 					Options: option.Options{Path: "server.db", Source: source},
 				},
 				DumpName:     "serverDump",
-				Archive:      false,
+				Archive:      archive.Archive{Algo: archive.AlgoNone},
 				DumpLocation: "server",
 			},
 			expectedContains: []string{
@@ -76,7 +77,7 @@ func TestSQLiteGenerator_Generate_AllScenarios(t *testing.T) {
 					Options: option.Options{Path: "serverArchive.db", Source: source},
 				},
 				DumpName:     "serverArchive",
-				Archive:      true,
+				Archive:      archive.Archive{Algo: archive.AlgoGzip},
 				DumpLocation: "server",
 			},
 			expectedContains: []string{
@@ -0,0 +1,20 @@
+package sqlite
+
+import (
+	commandDomain "dumper/internal/domain/command"
+	cmdCfg "dumper/internal/domain/command-config"
+	"fmt"
+)
+
+type Restorer struct{}
+
+func (r *Restorer) Restore(data *cmdCfg.Config) (*commandDomain.DBCommand, error) {
+	baseCmd := fmt.Sprintf("%s %s",
+		data.Database.Options.Source,
+		data.Database.Options.Path,
+	)
+
+	return &commandDomain.DBCommand{
+		Command: baseCmd,
+	}, nil
+}
@@ -1,8 +1,10 @@
 package local_config
 
 import (
+	"context"
 	"dumper/internal/crypt"
 	"dumper/internal/domain/config"
+	"dumper/internal/domain/config/archive"
 	"dumper/internal/domain/config/docker"
 	"dumper/internal/domain/config/encrypt"
 	"dumper/internal/domain/config/setting"
@@ -10,6 +12,8 @@ import (
 	sshConfig "dumper/internal/domain/config/ssh-config"
 	"dumper/internal/validation"
 	crypt2 "dumper/pkg/utils/crypt"
+	"dumper/pkg/utils/secret"
+	"fmt"
 	"os"
 
 	"github.com/creasty/defaults"
@@ -35,8 +39,17 @@ func Load(filename, appSecret string) (*config.Config, error) {
 		}
 	}
 
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, err
+	}
+
+	if err := secret.New().ResolveNode(context.Background(), &root); err != nil {
+		return nil, fmt.Errorf("failed to resolve config secrets: %w", err)
+	}
+
 	var cfg config.Config
-	if err := yaml.Unmarshal(data, &cfg); err != nil {
+	if err := root.Decode(&cfg); err != nil {
 		return nil, err
 	}
 
@@ -55,6 +68,12 @@ func Load(filename, appSecret string) (*config.Config, error) {
 	if cfg.Settings.Shell == nil {
 		cfg.Settings.Shell = &shell.Shell{}
 	}
+	if cfg.Settings.Archive == nil {
+		cfg.Settings.Archive = &archive.Archive{}
+	}
+	if cfg.Settings.Archive.Algo == "" {
+		cfg.Settings.Archive.Algo = archive.AlgoGzip
+	}
 
 	_ = defaults.Set(cfg.Settings.SSH)
 	_ = defaults.Set(cfg.Settings.Encrypt)
@@ -63,6 +82,10 @@ func Load(filename, appSecret string) (*config.Config, error) {
 	_ = defaults.Set(cfg.Settings)
 	_ = defaults.Set(&cfg)
 
+	if err := applySSHConfig(&cfg); err != nil {
+		return nil, fmt.Errorf("failed to read ssh config: %w", err)
+	}
+
 	v := validation.New()
 	if err := v.Handler(&cfg); err != nil {
 		return nil, validation.HumanError(err)
@@ -0,0 +1,338 @@
+package local_config_test
+
+import (
+	local_config "dumper/internal/config/local"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, dir, name, data string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, []byte(data), 0600))
+	return path
+}
+
+func TestLoad_SSHConfig(t *testing.T) {
+	dir := t.TempDir()
+	key := writeFile(t, dir, "id_prod", "key")
+
+	sshConfig := writeFile(t, dir, "ssh_config", `
+Host db-prod
+    HostName 10.0.0.12
+    User postgres
+    Port 2222
+    IdentityFile `+key+`
+    ProxyJump bastion
+    ServerAliveInterval 15
+
+Host bastion
+    HostName bastion.example.com
+    User ops
+`)
+
+	path := writeFile(t, dir, "config.yaml", `
+settings:
+  ssh:
+    config_file: "`+sshConfig+`"
+  storages: [local]
+storages:
+  local:
+    type: local
+    dir: ./dumps
+servers:
+  prod:
+    ssh_alias: db-prod
+  legacy:
+    host: db-prod
+    user: root
+    port: "22"
+    password: secret
+databases:
+  app:
+    name: app
+    user: app
+    driver: psql
+    server: prod
+`)
+
+	cfg, err := local_config.Load(path, "")
+	require.NoError(t, err)
+
+	prod := cfg.Servers["prod"]
+	assert.Equal(t, "10.0.0.12", prod.Host)
+	assert.Equal(t, "db-prod", prod.GetName())
+	assert.Equal(t, "postgres", prod.User)
+	assert.Equal(t, "2222", prod.Port)
+	assert.Equal(t, key, prod.PrivateKey)
+	assert.Equal(t, 15*time.Second, prod.KeepAlive)
+	require.Len(t, prod.JumpHosts, 1)
+	assert.Equal(t, "bastion.example.com", prod.JumpHosts[0].Host)
+	assert.Equal(t, "ops", prod.JumpHosts[0].User)
+
+	legacy := cfg.Servers["legacy"]
+	assert.Equal(t, "10.0.0.12", legacy.Host)
+	assert.Equal(t, "root", legacy.User)
+	assert.Equal(t, "22", legacy.Port)
+	assert.Equal(t, "", legacy.PrivateKey)
+}
+
+func TestLoad_Archive(t *testing.T) {
+	dir := t.TempDir()
+
+	config := func(settings, database string) string {
+		return writeFile(t, dir, "config.yaml", `
+settings:
+  ssh:
+    config_file: none
+  storages: [local]
+`+settings+`
+storages:
+  local:
+    type: local
+    dir: ./dumps
+servers:
+  prod:
+    host: 10.0.0.12
+    user: root
+    password: secret
+databases:
+  app:
+    name: app
+    user: app
+    driver: psql
+    format: plain
+    server: prod
+`+database+`
+  legacy:
+    name: legacy
+    user: app
+    driver: mysql
+    format: sql
+    server: prod
+    archive: false
+`)
+	}
+
+	t.Run("global mapping", func(t *testing.T) {
+		cfg, err := local_config.Load(config("  archive: {algo: zstd, level: 19, threads: 4}", "    archive: {level: 3}"), "")
+		require.NoError(t, err)
+
+		app := cfg.Databases["app"]
+		assert.Equal(t, "zstd", app.Archive.Algo)
+		assert.Equal(t, 3, app.Archive.Level)
+		assert.Equal(t, 4, app.Archive.Threads)
+		assert.False(t, cfg.Databases["legacy"].Archive.IsEnabled())
+	})
+
+	t.Run("database overrides the algorithm", func(t *testing.T) {
+		cfg, err := local_config.Load(config("  archive: {algo: zstd, level: 19}", "    archive: {algo: xz, threads: 2}"), "")
+		require.NoError(t, err)
+
+		app := cfg.Databases["app"]
+		assert.Equal(t, "xz", app.Archive.Algo)
+		assert.Equal(t, 0, app.Archive.Level)
+		assert.Equal(t, 2, app.Archive.Threads)
+	})
+
+	t.Run("bool keeps gzip", func(t *testing.T) {
+		cfg, err := local_config.Load(config("  archive: true", ""), "")
+		require.NoError(t, err)
+		assert.Equal(t, "gzip", cfg.Databases["app"].Archive.Algo)
+	})
+
+	t.Run("unset defaults to gzip", func(t *testing.T) {
+		cfg, err := local_config.Load(config("", ""), "")
+		require.NoError(t, err)
+		assert.Equal(t, "gzip", cfg.Databases["app"].Archive.Algo)
+	})
+
+	t.Run("level out of range", func(t *testing.T) {
+		_, err := local_config.Load(config("  archive: {algo: gzip, level: 12}", ""), "")
+		assert.ErrorContains(t, err, "level of gzip must be between 1 and 9")
+	})
+
+	t.Run("unknown algorithm", func(t *testing.T) {
+		_, err := local_config.Load(config("  archive: brotli", ""), "")
+		assert.ErrorContains(t, err, "algo must be")
+	})
+}
+
+func TestLoad_Discover(t *testing.T) {
+	dir := t.TempDir()
+
+	config := func(driver, format, discover string) string {
+		return writeFile(t, dir, "config.yaml", `
+settings:
+  ssh:
+    config_file: none
+  storages: [local]
+storages:
+  local:
+    type: local
+    dir: ./dumps
+servers:
+  prod:
+    host: 10.0.0.12
+    user: root
+    password: secret
+databases:
+  tenants:
+    user: app
+    driver: `+driver+`
+    format: `+format+`
+    server: prod
+    discover: `+discover+`
+`)
+	}
+
+	t.Run("bool shorthand", func(t *testing.T) {
+		cfg, err := local_config.Load(config("psql", "plain", "true"), "")
+		require.NoError(t, err)
+
+		tenants := cfg.Databases["tenants"]
+		assert.True(t, tenants.IsDiscover())
+		assert.Empty(t, tenants.Discover.Include)
+	})
+
+	t.Run("patterns", func(t *testing.T) {
+		cfg, err := local_config.Load(config("mysql", "sql", "{enabled: true, include: ['^tenant_'], exclude: ['_test$']}"), "")
+		require.NoError(t, err)
+
+		tenants := cfg.Databases["tenants"]
+		assert.True(t, tenants.Discover.Match("tenant_acme"))
+		assert.False(t, tenants.Discover.Match("tenant_acme_test"))
+		assert.False(t, tenants.Discover.Match("billing"))
+	})
+
+	t.Run("invalid pattern", func(t *testing.T) {
+		_, err := local_config.Load(config("psql", "plain", "{enabled: true, include: ['tenant_(']}"), "")
+		assert.ErrorContains(t, err, "invalid pattern 'tenant_('")
+	})
+
+	t.Run("driver without discover", func(t *testing.T) {
+		_, err := local_config.Load(config("redis", "rdb", "true"), "")
+		assert.ErrorContains(t, err, "driver 'redis' can't list its databases")
+	})
+}
+
+func TestLoad_Basebackup(t *testing.T) {
+	dir := t.TempDir()
+
+	config := func(options string) string {
+		return writeFile(t, dir, "config.yaml", `
+settings:
+  ssh:
+    config_file: none
+  storages: [local]
+storages:
+  local:
+    type: local
+    dir: ./dumps
+servers:
+  prod:
+    host: 10.0.0.12
+    user: root
+    password: secret
+databases:
+  cluster:
+    user: replicator
+    driver: psql
+    format: basebackup
+    server: prod
+    options:
+`+options+`
+`)
+	}
+
+	t.Run("wal shipping", func(t *testing.T) {
+		cfg, err := local_config.Load(config("      wal_dir: /var/lib/postgresql/wal_archive\n      wal_schedule: '*/5 * * * *'"), "")
+		require.NoError(t, err)
+
+		cluster := cfg.Databases["cluster"]
+		assert.Equal(t, "pg_basebackup", cluster.Options.Source)
+		assert.Equal(t, "/var/lib/postgresql/wal_archive", cluster.Options.WalDir)
+	})
+
+	t.Run("wal schedule without dir", func(t *testing.T) {
+		_, err := local_config.Load(config("      wal_schedule: '*/5 * * * *'"), "")
+		assert.ErrorContains(t, err, "wal_schedule needs wal_dir")
+	})
+
+	t.Run("filters", func(t *testing.T) {
+		_, err := local_config.Load(config("      inc_schemas: [sales]"), "")
+		assert.ErrorContains(t, err, "format 'basebackup' copies the whole cluster")
+	})
+}
+
+func TestLoad_MySQLOptions(t *testing.T) {
+	dir := t.TempDir()
+
+	config := func(driver, format, options string) string {
+		return writeFile(t, dir, "config.yaml", `
+settings:
+  ssh:
+    config_file: none
+  storages: [local]
+storages:
+  local:
+    type: local
+    dir: ./dumps
+servers:
+  prod:
+    host: 10.0.0.12
+    user: root
+    password: secret
+databases:
+  shop:
+    name: shop
+    user: backup
+    driver: `+driver+`
+    format: `+format+`
+    server: prod
+    options:
+`+options+`
+`)
+	}
+
+	t.Run("safe defaults", func(t *testing.T) {
+		cfg, err := local_config.Load(config("mysql", "sql", ""), "")
+		require.NoError(t, err)
+
+		options := cfg.Databases["shop"].Options
+		assert.True(t, *options.SingleTransaction)
+		assert.True(t, *options.Triggers)
+		assert.False(t, options.Routines)
+	})
+
+	t.Run("incremental xtrabackup", func(t *testing.T) {
+		cfg, err := local_config.Load(config("mysql", "xtrabackup", "      incremental: true\n      jobs: 4"), "")
+		require.NoError(t, err)
+		assert.Equal(t, "xtrabackup", cfg.Databases["shop"].Options.Source)
+	})
+
+	t.Run("incremental needs a physical format", func(t *testing.T) {
+		_, err := local_config.Load(config("mariadb", "mydumper", "      incremental: true"), "")
+		assert.ErrorContains(t, err, "incremental needs format 'xtrabackup' or 'mariabackup'")
+	})
+
+	t.Run("jobs with mysqldump", func(t *testing.T) {
+		_, err := local_config.Load(config("mysql", "sql", "      jobs: 4"), "")
+		assert.ErrorContains(t, err, "jobs needs format 'mydumper'")
+	})
+
+	t.Run("gtid with mariadb", func(t *testing.T) {
+		_, err := local_config.Load(config("mariadb", "sql", "      set_gtid_purged: OFF"), "")
+		assert.ErrorContains(t, err, "set_gtid_purged is an option of mysqldump")
+	})
+
+	t.Run("invalid gtid mode", func(t *testing.T) {
+		_, err := local_config.Load(config("mysql", "sql", "      set_gtid_purged: maybe"), "")
+		assert.ErrorContains(t, err, "set_gtid_purged must be OFF, ON, AUTO or COMMENTED")
+	})
+}
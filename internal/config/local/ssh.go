@@ -0,0 +1,115 @@
+package local_config
+
+import (
+	"dumper/internal/domain/config"
+	"dumper/internal/domain/config/server"
+	"dumper/pkg/utils/sshconfig"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// applySSHConfig completes servers from the OpenSSH client config. A server
+// is looked up by ssh_alias or else by its host, and only takes what its own
+// fields leave unset.
+func applySSHConfig(cfg *config.Config) error {
+	files := sshconfig.DefaultFiles()
+
+	switch path := cfg.Settings.SSH.ConfigFile; path {
+	case "none":
+		return nil
+	case "":
+	default:
+		if strings.HasPrefix(path, "~/") {
+			if home, err := os.UserHomeDir(); err == nil {
+				path = filepath.Join(home, path[2:])
+			}
+		}
+		files = []string{path}
+	}
+
+	sshCfg, err := sshconfig.Load(files...)
+	if err != nil {
+		return err
+	}
+
+	for name, srv := range cfg.Servers {
+		cfg.Servers[name] = resolveServer(sshCfg, srv)
+	}
+
+	return nil
+}
+
+func resolveServer(sshCfg *sshconfig.Config, srv server.Server) server.Server {
+	alias := srv.SSHAlias
+	if alias == "" {
+		alias = srv.Host
+	}
+	if alias == "" {
+		return srv
+	}
+
+	h := sshCfg.Lookup(alias)
+
+	if srv.Host == "" || (srv.SSHAlias == "" && h.HostName != "") {
+		if srv.Name == "" {
+			srv.Name = alias
+		}
+		srv.Host = alias
+		if h.HostName != "" {
+			srv.Host = h.HostName
+		}
+	}
+
+	if srv.User == "" {
+		srv.User = h.User
+	}
+	if srv.Port == "" {
+		srv.Port = h.Port
+	}
+	if srv.PrivateKey == "" && srv.Password == "" {
+		srv.PrivateKey = firstExisting(h.IdentityFiles)
+	}
+	if srv.KeepAlive == 0 {
+		srv.KeepAlive = h.ServerAliveInterval
+	}
+
+	if len(srv.JumpHosts) == 0 && h.ProxyJump != "" {
+		for _, hop := range strings.Split(h.ProxyJump, ",") {
+			jump, err := server.ParseJumpHost(hop)
+			if err != nil {
+				continue
+			}
+			srv.JumpHosts = append(srv.JumpHosts, resolveJumpHost(sshCfg, jump))
+		}
+	}
+
+	return srv
+}
+
+// resolveJumpHost looks a ProxyJump hop up in the config too, as ssh does.
+func resolveJumpHost(sshCfg *sshconfig.Config, jump server.JumpHost) server.JumpHost {
+	h := sshCfg.Lookup(jump.Host)
+
+	if h.HostName != "" {
+		jump.Host = h.HostName
+	}
+	if jump.User == "" {
+		jump.User = h.User
+	}
+	if jump.Port == "" {
+		jump.Port = h.Port
+	}
+	jump.PrivateKey = firstExisting(h.IdentityFiles)
+
+	return jump
+}
+
+func firstExisting(paths []string) string {
+	for _, path := range paths {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
@@ -135,6 +135,7 @@ func (r *RCfg) loadFromString(strYml string) error {
 	data := make(map[string]dbConnect.DBConnect, len(cfg.Databases))
 
 	for idx, db := range cfg.Databases {
+		db.Key = idx
 		data[idx] = dbConnect.DBConnect{
 			Database: db,
 			Server:   cfg.Servers[db.Server],
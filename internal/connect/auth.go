@@ -0,0 +1,161 @@
+package connect
+
+import (
+	connectDomain "dumper/internal/domain/connect"
+	"dumper/pkg/logging"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/term"
+)
+
+// authMethods lists every way conn can log in. The client tries each SSH
+// method once, so all keys (the configured key with its certificate first,
+// then the agent's) go into a single publickey method, followed by the
+// password.
+func (c *Connect) authMethods(conn *connectDomain.Connect) ([]ssh.AuthMethod, error) {
+	var signers []ssh.Signer
+
+	if conn.PrivateKey != "" {
+		keySigners, err := c.keySigners(conn)
+		if err != nil {
+			return nil, err
+		}
+		signers = append(signers, keySigners...)
+	}
+
+	if conn.Agent {
+		agentSigners, err := c.agentSigners()
+		if err != nil {
+			logging.L(c.ctx).Warn("SSH agent is not available", logging.ErrAttr(err))
+		}
+		signers = append(signers, agentSigners...)
+	}
+
+	var authMethods []ssh.AuthMethod
+
+	if len(signers) > 0 {
+		authMethods = append(authMethods, ssh.PublicKeys(signers...))
+	}
+
+	if conn.Password != "" {
+		password := conn.Password
+		authMethods = append(authMethods,
+			ssh.Password(password),
+			ssh.KeyboardInteractive(func(_, _ string, questions []string, _ []bool) ([]string, error) {
+				answers := make([]string, len(questions))
+				for i := range answers {
+					answers[i] = password
+				}
+				return answers, nil
+			}),
+		)
+	}
+
+	if len(authMethods) == 0 {
+		return nil, fmt.Errorf("error the authentication method is not specified")
+	}
+
+	return authMethods, nil
+}
+
+func (c *Connect) keySigners(conn *connectDomain.Connect) ([]ssh.Signer, error) {
+	key, err := os.ReadFile(conn.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("error couldn't read SSH key: %v", err)
+	}
+
+	if conn.IsPassphrase && conn.Passphrase == "" {
+		fmt.Printf("Enter the passphrase : ")
+		passphrase, err := term.ReadPassword(int(os.Stdin.Fd()))
+		fmt.Printf("\r")
+		if err != nil {
+			return nil, fmt.Errorf("input error: %v", err)
+		}
+		conn.Passphrase = strings.TrimSpace(string(passphrase))
+	}
+
+	var signer ssh.Signer
+	if conn.Passphrase != "" {
+		signer, err = ssh.ParsePrivateKeyWithPassphrase(key, []byte(conn.Passphrase))
+	} else {
+		signer, err = ssh.ParsePrivateKey(key)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("error couldn't parse SSH key: %v", err)
+	}
+
+	certSigner, err := certificateSigner(conn, signer)
+	if err != nil {
+		return nil, err
+	}
+
+	if certSigner != nil {
+		return []ssh.Signer{certSigner, signer}, nil
+	}
+
+	return []ssh.Signer{signer}, nil
+}
+
+// certificateSigner loads the OpenSSH user certificate of the key, from
+// certificate or else from <private_key>-cert.pub when that file exists.
+func certificateSigner(conn *connectDomain.Connect, signer ssh.Signer) (ssh.Signer, error) {
+	path := conn.Certificate
+	if path == "" {
+		path = conn.PrivateKey + "-cert.pub"
+		if _, err := os.Stat(path); err != nil {
+			return nil, nil
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error couldn't read SSH certificate: %v", err)
+	}
+
+	pub, _, _, _, err := ssh.ParseAuthorizedKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("error couldn't parse SSH certificate: %v", err)
+	}
+
+	cert, ok := pub.(*ssh.Certificate)
+	if !ok {
+		return nil, fmt.Errorf("error %s is not an SSH certificate", path)
+	}
+
+	certSigner, err := ssh.NewCertSigner(cert, signer)
+	if err != nil {
+		return nil, fmt.Errorf("error SSH certificate does not match the key: %v", err)
+	}
+
+	return certSigner, nil
+}
+
+// agentSigners returns the keys and certificates of the agent on
+// SSH_AUTH_SOCK. The socket stays open until Close, hardware keys sign
+// during every handshake.
+func (c *Connect) agentSigners() ([]ssh.Signer, error) {
+	if c.agent != nil {
+		return c.agent.Signers()
+	}
+
+	socket := os.Getenv("SSH_AUTH_SOCK")
+	if socket == "" {
+		return nil, nil
+	}
+
+	conn, err := net.Dial("unix", socket)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to SSH agent: %w", err)
+	}
+
+	c.agentConn = conn
+	c.agent = agent.NewClient(conn)
+
+	return c.agent.Signers()
+}
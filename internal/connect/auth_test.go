@@ -0,0 +1,395 @@
+package connect_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+
+	"dumper/internal/connect"
+	"dumper/internal/domain/config/server"
+	connectDomain "dumper/internal/domain/connect"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"gopkg.in/yaml.v3"
+)
+
+type testServer struct {
+	host     string
+	port     string
+	mu       sync.Mutex
+	forwards []string
+	commands []string
+	conns    []net.Conn
+}
+
+// newTestServer runs an SSH server that forwards direct-tcpip channels and
+// answers exec requests with exit status 0, enough to act as a target and
+// as a bastion.
+func newTestServer(t *testing.T, config *ssh.ServerConfig) *testServer {
+	t.Helper()
+
+	_, hostPriv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	hostSigner, err := ssh.NewSignerFromKey(hostPriv)
+	require.NoError(t, err)
+	config.AddHostKey(hostSigner)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	host, port, _ := net.SplitHostPort(listener.Addr().String())
+	srv := &testServer{host: host, port: port}
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go srv.serve(conn, config)
+		}
+	}()
+
+	return srv
+}
+
+func (s *testServer) serve(conn net.Conn, config *ssh.ServerConfig) {
+	_, chans, reqs, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		_ = conn.Close()
+		return
+	}
+	go ssh.DiscardRequests(reqs)
+
+	s.mu.Lock()
+	s.conns = append(s.conns, conn)
+	s.mu.Unlock()
+
+	for newChan := range chans {
+		if newChan.ChannelType() == "session" {
+			go s.session(newChan)
+			continue
+		}
+
+		if newChan.ChannelType() != "direct-tcpip" {
+			_ = newChan.Reject(ssh.UnknownChannelType, "not supported")
+			continue
+		}
+
+		var target struct {
+			Host       string
+			Port       uint32
+			OriginHost string
+			OriginPort uint32
+		}
+		if err := ssh.Unmarshal(newChan.ExtraData(), &target); err != nil {
+			_ = newChan.Reject(ssh.ConnectionFailed, err.Error())
+			continue
+		}
+
+		addr := net.JoinHostPort(target.Host, strconv.Itoa(int(target.Port)))
+		s.mu.Lock()
+		s.forwards = append(s.forwards, addr)
+		s.mu.Unlock()
+
+		upstream, err := net.Dial("tcp", addr)
+		if err != nil {
+			_ = newChan.Reject(ssh.ConnectionFailed, err.Error())
+			continue
+		}
+
+		channel, chReqs, err := newChan.Accept()
+		if err != nil {
+			_ = upstream.Close()
+			continue
+		}
+		go ssh.DiscardRequests(chReqs)
+
+		go func() {
+			_, _ = io.Copy(channel, upstream)
+			_ = channel.CloseWrite()
+		}()
+		go func() {
+			_, _ = io.Copy(upstream, channel)
+			_ = upstream.Close()
+		}()
+	}
+}
+
+func (s *testServer) session(newChan ssh.NewChannel) {
+	channel, reqs, err := newChan.Accept()
+	if err != nil {
+		return
+	}
+	defer channel.Close()
+
+	for req := range reqs {
+		if req.Type != "exec" {
+			_ = req.Reply(false, nil)
+			continue
+		}
+
+		var exec struct{ Command string }
+		_ = ssh.Unmarshal(req.Payload, &exec)
+		_ = req.Reply(true, nil)
+
+		s.mu.Lock()
+		s.commands = append(s.commands, exec.Command)
+		s.mu.Unlock()
+
+		if exec.Command == "command -v bash >/dev/null 2>&1 && echo OK" {
+			_, _ = channel.Write([]byte("OK\n"))
+		}
+		_, _ = channel.SendRequest("exit-status", false, ssh.Marshal(struct{ Status uint32 }{0}))
+		return
+	}
+}
+
+// Drop cuts every connection, like a server restart.
+func (s *testServer) Drop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, conn := range s.conns {
+		_ = conn.Close()
+	}
+}
+
+func (s *testServer) Conns() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.conns)
+}
+
+func (s *testServer) Commands() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.commands...)
+}
+
+func (s *testServer) Forwards() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.forwards...)
+}
+
+func newSigner(t *testing.T) (ssh.Signer, ed25519.PrivateKey) {
+	t.Helper()
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	signer, err := ssh.NewSignerFromKey(priv)
+	require.NoError(t, err)
+	return signer, priv
+}
+
+func writeKey(t *testing.T, priv ed25519.PrivateKey) string {
+	t.Helper()
+	block, err := ssh.MarshalPrivateKey(priv, "")
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "id_ed25519")
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(block), 0600))
+	return path
+}
+
+func passwordConfig(password string) *ssh.ServerConfig {
+	return &ssh.ServerConfig{
+		PasswordCallback: func(_ ssh.ConnMetadata, pass []byte) (*ssh.Permissions, error) {
+			if string(pass) == password {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("wrong password")
+		},
+	}
+}
+
+func keyConfig(allowed ssh.PublicKey) *ssh.ServerConfig {
+	return &ssh.ServerConfig{
+		PublicKeyCallback: func(_ ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if bytes.Equal(key.Marshal(), allowed.Marshal()) {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("unknown key")
+		},
+	}
+}
+
+func dial(t *testing.T, conn *connectDomain.Connect) error {
+	t.Helper()
+	conn.HostKeyChecking = connectDomain.HostKeyOff
+
+	c := connect.NewApp(context.Background(), conn)
+	err := c.Connect()
+	if err == nil {
+		assert.NotNil(t, c.Client())
+		assert.NoError(t, c.Close())
+	}
+	return err
+}
+
+func TestConnect_PrivateKey(t *testing.T) {
+	signer, priv := newSigner(t)
+	target := newTestServer(t, keyConfig(signer.PublicKey()))
+
+	err := dial(t, &connectDomain.Connect{
+		Server:     target.host,
+		Port:       target.port,
+		Username:   "root",
+		PrivateKey: writeKey(t, priv),
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestConnect_FallsBackToPassword(t *testing.T) {
+	_, priv := newSigner(t)
+	config := passwordConfig("s3cr3t")
+	config.PublicKeyCallback = func(ssh.ConnMetadata, ssh.PublicKey) (*ssh.Permissions, error) {
+		return nil, fmt.Errorf("unknown key")
+	}
+	target := newTestServer(t, config)
+
+	err := dial(t, &connectDomain.Connect{
+		Server:     target.host,
+		Port:       target.port,
+		Username:   "root",
+		PrivateKey: writeKey(t, priv),
+		Password:   "s3cr3t",
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestConnect_Certificate(t *testing.T) {
+	caSigner, _ := newSigner(t)
+	userSigner, userPriv := newSigner(t)
+
+	cert := &ssh.Certificate{
+		Key:             userSigner.PublicKey(),
+		CertType:        ssh.UserCert,
+		KeyId:           "backup",
+		ValidPrincipals: []string{"root"},
+		ValidBefore:     ssh.CertTimeInfinity,
+	}
+	require.NoError(t, cert.SignCert(rand.Reader, caSigner))
+
+	checker := &ssh.CertChecker{
+		IsUserAuthority: func(auth ssh.PublicKey) bool {
+			return bytes.Equal(auth.Marshal(), caSigner.PublicKey().Marshal())
+		},
+	}
+	target := newTestServer(t, &ssh.ServerConfig{PublicKeyCallback: checker.Authenticate})
+
+	keyPath := writeKey(t, userPriv)
+	require.NoError(t, os.WriteFile(keyPath+"-cert.pub", ssh.MarshalAuthorizedKey(cert), 0600))
+
+	err := dial(t, &connectDomain.Connect{
+		Server:     target.host,
+		Port:       target.port,
+		Username:   "root",
+		PrivateKey: keyPath,
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestConnect_Agent(t *testing.T) {
+	signer, priv := newSigner(t)
+	target := newTestServer(t, keyConfig(signer.PublicKey()))
+
+	keyring := agent.NewKeyring()
+	require.NoError(t, keyring.Add(agent.AddedKey{PrivateKey: priv}))
+
+	socket := filepath.Join(t.TempDir(), "agent.sock")
+	listener, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() { _ = agent.ServeAgent(keyring, conn) }()
+		}
+	}()
+
+	t.Setenv("SSH_AUTH_SOCK", socket)
+
+	err = dial(t, &connectDomain.Connect{
+		Server:   target.host,
+		Port:     target.port,
+		Username: "root",
+		Agent:    true,
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestConnect_JumpHosts(t *testing.T) {
+	signer, priv := newSigner(t)
+	target := newTestServer(t, keyConfig(signer.PublicKey()))
+	inner := newTestServer(t, keyConfig(signer.PublicKey()))
+	bastion := newTestServer(t, passwordConfig("bastion"))
+
+	err := dial(t, &connectDomain.Connect{
+		Server:     target.host,
+		Port:       target.port,
+		Username:   "root",
+		PrivateKey: writeKey(t, priv),
+		JumpHosts: []connectDomain.Connect{
+			{Server: bastion.host, Port: bastion.port, Password: "bastion"},
+			{Server: inner.host, Port: inner.port},
+		},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{net.JoinHostPort(inner.host, inner.port)}, bastion.Forwards())
+	assert.Equal(t, []string{net.JoinHostPort(target.host, target.port)}, inner.Forwards())
+}
+
+func TestConnect_NoAuthMethod(t *testing.T) {
+	t.Setenv("SSH_AUTH_SOCK", "")
+
+	err := dial(t, &connectDomain.Connect{Server: "127.0.0.1", Port: "22", Agent: true})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "authentication method is not specified")
+}
+
+func TestJumpHosts(t *testing.T) {
+	var srv server.Server
+	require.NoError(t, yaml.Unmarshal([]byte(`
+host: db.internal
+user: root
+jump_hosts:
+  - ops@bastion.example.com:2222
+  - "[fd00::1]"
+  - host: inner.internal
+    user: backup
+    private_key: /keys/inner
+`), &srv))
+
+	hops := connect.JumpHosts(srv.JumpHosts)
+
+	require.Len(t, hops, 3)
+	assert.Equal(t, connectDomain.Connect{Server: "bastion.example.com", Port: "2222", Username: "ops"}, hops[0])
+	assert.Equal(t, connectDomain.Connect{Server: "fd00::1"}, hops[1])
+	assert.Equal(t, connectDomain.Connect{Server: "inner.internal", Username: "backup", PrivateKey: "/keys/inner"}, hops[2])
+
+	_, err := server.ParseJumpHost("root@")
+	assert.Error(t, err)
+}
@@ -8,18 +8,30 @@ import (
 	"dumper/pkg/utils/console"
 	"dumper/pkg/utils/mask"
 	"fmt"
-	"os"
+	"io"
+	"net"
 	"strings"
+	"sync"
 	"time"
 
 	"golang.org/x/crypto/ssh"
-	"golang.org/x/term"
+	"golang.org/x/crypto/ssh/agent"
 )
 
+// defaultKeepAlive is used when neither the server nor its ssh config set
+// an interval.
+const defaultKeepAlive = 30 * time.Second
+
 type Connect struct {
-	ctx     context.Context
-	connect *connectDomain.Connect
-	client  *ssh.Client
+	ctx       context.Context
+	connect   *connectDomain.Connect
+	mu        sync.Mutex
+	client    *ssh.Client
+	jumps     []*ssh.Client
+	agent     agent.ExtendedAgent
+	agentConn net.Conn
+	shellMu   sync.Mutex
+	hasBash   *bool
 }
 
 func NewApp(
@@ -32,59 +44,41 @@ func NewApp(
 	}
 }
 
-func (c *Connect) buildSSHConfig() (*ssh.ClientConfig, error) {
-	var authMethods []ssh.AuthMethod
-
-	if c.connect.PrivateKey != "" {
-		key, err := os.ReadFile(c.connect.PrivateKey)
-		if err != nil {
-			return nil, fmt.Errorf("error couldn't read SSH key: %v", err)
-		}
-
-		if c.connect.IsPassphrase && c.connect.Passphrase == "" {
-			fmt.Printf("Enter the passphrase : ")
-			passphrase, err := term.ReadPassword(int(os.Stdin.Fd()))
-			fmt.Printf("\r")
-			if err != nil {
-				return nil, fmt.Errorf("input error: %v", err)
-			}
-			c.connect.Passphrase = strings.TrimSpace(string(passphrase))
-		}
-
-		var signer ssh.Signer
-		if c.connect.Passphrase != "" {
-			signer, err = ssh.ParsePrivateKeyWithPassphrase(key, []byte(c.connect.Passphrase))
-		} else {
-			signer, err = ssh.ParsePrivateKey(key)
-		}
-
-		if err != nil {
-			return nil, fmt.Errorf("error couldn't parse SSH key: %v", err)
-		}
-
-		authMethods = append(authMethods, ssh.PublicKeys(signer))
-	} else if c.connect.Password != "" {
-		authMethods = append(authMethods, ssh.Password(c.connect.Password))
+func (c *Connect) buildSSHConfig(conn *connectDomain.Connect) (*ssh.ClientConfig, error) {
+	authMethods, err := c.authMethods(conn)
+	if err != nil {
+		return nil, err
 	}
 
-	if len(authMethods) == 0 {
-		return nil, fmt.Errorf("error the authentication method is not specified")
+	hostKey, err := NewHostKey(c.ctx, conn, address(conn))
+	if err != nil {
+		return nil, err
 	}
 
 	return &ssh.ClientConfig{
-		User:            c.connect.Username,
-		Auth:            authMethods,
-		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
-		Timeout:         10 * time.Second,
+		User:              conn.Username,
+		Auth:              authMethods,
+		HostKeyCallback:   hostKey.Callback,
+		HostKeyAlgorithms: hostKey.Algorithms,
+		Timeout:           10 * time.Second,
 	}, nil
 }
 
+// Connect dials the server, or keeps the current client when it is still
+// alive, so one Connect serves every backup of a server.
 func (c *Connect) Connect() error {
-	config, err := c.buildSSHConfig()
-	if err != nil {
-		return err
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.alive() {
+		logging.L(c.ctx).Info("Reusing SSH connection", logging.StringAttr("server", c.connect.Server))
+		return nil
 	}
 
+	return c.connectLocked()
+}
+
+func (c *Connect) connectLocked() error {
 	console.SafePrintln("Trying to connect to: %s", c.connect.Server)
 
 	logging.L(c.ctx).Info(
@@ -92,23 +86,82 @@ func (c *Connect) Connect() error {
 		logging.StringAttr("server", c.connect.Server),
 	)
 
-	client, err := ssh.Dial("tcp", c.connect.Server+":"+c.connect.Port, config)
+	c.closeClient()
+
+	client, err := c.dial()
 	if err != nil {
 		return fmt.Errorf("error couldn't connect via SSH: %v", err)
 	}
 
 	c.client = client
+
+	interval := c.connect.KeepAlive
+	if interval <= 0 {
+		interval = defaultKeepAlive
+	}
+	go c.keepAlive(client, interval)
+
 	return nil
 }
 
+// keepAlive pings the server every interval like ServerAliveInterval, so
+// idle connections are not dropped by firewalls during long dumps. A failed
+// ping ends it, the next session then reconnects.
+func (c *Connect) keepAlive(client *ssh.Client, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-ticker.C:
+			if !isAlive(client) {
+				logging.L(c.ctx).Warn("SSH keepalive failed", logging.StringAttr("server", c.connect.Server))
+				return
+			}
+		}
+	}
+}
+
+// NewSession opens a session on the shared client. A dropped connection is
+// reconnected once, transparently to the caller.
 func (c *Connect) NewSession() (*ssh.Session, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	if c.client == nil {
 		return nil, fmt.Errorf("SSH client is not connected")
 	}
+
+	session, err := c.client.NewSession()
+	if err == nil || c.alive() {
+		return session, err
+	}
+
+	logging.L(c.ctx).Warn(
+		"SSH connection lost, reconnecting",
+		logging.StringAttr("server", c.connect.Server),
+		logging.ErrAttr(err),
+	)
+
+	if err := c.connectLocked(); err != nil {
+		return nil, err
+	}
+
 	return c.client.NewSession()
 }
 
 func (c *Connect) RunCommand(cmd string) (string, error) {
+	return c.RunCommandWithInput(cmd, nil, nil)
+}
+
+// RunCommandWithEnv runs cmd with env set, see applyEnv.
+func (c *Connect) RunCommandWithEnv(cmd string, env map[string]string) (string, error) {
+	return c.RunCommandWithInput(cmd, env, nil)
+}
+
+func (c *Connect) RunCommandWithInput(cmd string, env map[string]string, stdin io.Reader) (string, error) {
 	session, err := c.NewSession()
 	if err != nil {
 		return "", err
@@ -119,25 +172,19 @@ func (c *Connect) RunCommand(cmd string) (string, error) {
 	session.Stdout = &stdout
 	session.Stderr = &stderr
 
-	checkBashCmd := "command -v bash >/dev/null 2>&1 && echo OK"
-	checkSession, err := c.NewSession()
-	if err != nil {
-		return "", fmt.Errorf("failed to check bash availability: %w", err)
+	if stdin != nil {
+		session.Stdin = stdin
 	}
-	var checkOut bytes.Buffer
-	checkSession.Stdout = &checkOut
-	if err := checkSession.Run(checkBashCmd); err != nil {
-		_ = checkSession.Close()
-		return "", fmt.Errorf("failed to run bash check: %w", err)
+
+	cmd, cleanup, err := c.applyEnv(session, cmd, env)
+	if err != nil {
+		return "", err
 	}
-	_ = checkSession.Close()
 
-	var fullCmd string
-	escapedCmd := escapeForBash(cmd)
-	if strings.Contains(checkOut.String(), "OK") {
-		fullCmd = fmt.Sprintf(`bash -c 'set -o pipefail; %s'`, escapedCmd)
-	} else {
-		fullCmd = fmt.Sprintf(`sh -c '%s; exit ${PIPESTATUS[0]:-0}'`, escapedCmd)
+	fullCmd, err := c.wrapCommand(cmd)
+	if err != nil {
+		cleanup()
+		return "", err
 	}
 
 	err = session.Run(fullCmd)
@@ -154,24 +201,117 @@ func (c *Connect) RunCommand(cmd string) (string, error) {
 	return output, nil
 }
 
+func (c *Connect) StreamCommand(cmd string, env map[string]string) (*Stream, error) {
+	session, err := c.NewSession()
+	if err != nil {
+		return nil, err
+	}
+
+	stream := &Stream{session: session}
+	session.Stderr = &stream.stderr
+
+	stdout, err := session.StdoutPipe()
+	if err != nil {
+		_ = session.Close()
+		return nil, fmt.Errorf("failed to get stdout pipe: %w", err)
+	}
+	stream.Stdout = stdout
+
+	cmd, cleanup, err := c.applyEnv(session, cmd, env)
+	if err != nil {
+		_ = session.Close()
+		return nil, err
+	}
+
+	fullCmd, err := c.wrapCommand(cmd)
+	if err != nil {
+		cleanup()
+		_ = session.Close()
+		return nil, err
+	}
+
+	if err := session.Start(fullCmd); err != nil {
+		cleanup()
+		_ = session.Close()
+		return nil, fmt.Errorf("failed to start remote command: %w", err)
+	}
+
+	return stream, nil
+}
+
+func (c *Connect) wrapCommand(cmd string) (string, error) {
+	hasBash, err := c.detectBash()
+	if err != nil {
+		return "", err
+	}
+
+	escapedCmd := escapeForBash(cmd)
+	if hasBash {
+		return fmt.Sprintf(`bash -c 'set -o pipefail; %s'`, escapedCmd), nil
+	}
+	return fmt.Sprintf(`sh -c '%s; exit ${PIPESTATUS[0]:-0}'`, escapedCmd), nil
+}
+
+// detectBash checks once per server whether bash is available.
+func (c *Connect) detectBash() (bool, error) {
+	c.shellMu.Lock()
+	defer c.shellMu.Unlock()
+
+	if c.hasBash != nil {
+		return *c.hasBash, nil
+	}
+
+	checkBashCmd := "command -v bash >/dev/null 2>&1 && echo OK"
+	checkSession, err := c.NewSession()
+	if err != nil {
+		return false, fmt.Errorf("failed to check bash availability: %w", err)
+	}
+	var checkOut bytes.Buffer
+	checkSession.Stdout = &checkOut
+	if err := checkSession.Run(checkBashCmd); err != nil {
+		_ = checkSession.Close()
+		return false, fmt.Errorf("failed to run bash check: %w", err)
+	}
+	_ = checkSession.Close()
+
+	hasBash := strings.Contains(checkOut.String(), "OK")
+	c.hasBash = &hasBash
+
+	return hasBash, nil
+}
+
 func (c *Connect) Client() *ssh.Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	return c.client
 }
 
 func (c *Connect) IsConnected() bool {
-	if c.client == nil {
-		return false
-	}
-	_, _, err := c.client.SendRequest("keepalive@openssh.com", true, nil)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.alive()
+}
+
+func (c *Connect) alive() bool {
+	return c.client != nil && isAlive(c.client)
+}
+
+func isAlive(client *ssh.Client) bool {
+	_, _, err := client.SendRequest("keepalive@openssh.com", true, nil)
 	return err == nil
 }
 
 func (c *Connect) Reconnect() error {
-	fmt.Println("[SSH] Attempting reconnect...")
+	logging.L(c.ctx).Info("Attempting SSH reconnect", logging.StringAttr("server", c.connect.Server))
 
-	_ = c.Close()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.closeClient()
 	time.Sleep(2 * time.Second)
-	return c.Connect()
+	return c.connectLocked()
 }
 
 func (c *Connect) TestConnection() error {
@@ -180,12 +320,30 @@ func (c *Connect) TestConnection() error {
 }
 
 func (c *Connect) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	err := c.closeClient()
+
+	if c.agentConn != nil {
+		_ = c.agentConn.Close()
+		c.agentConn = nil
+		c.agent = nil
+	}
+
+	return err
+}
+
+func (c *Connect) closeClient() error {
+	var err error
 	if c.client != nil {
-		err := c.client.Close()
+		err = c.client.Close()
 		c.client = nil
-		return err
 	}
-	return nil
+
+	c.closeJumps()
+
+	return err
 }
 
 func escapeForBash(cmd string) string {
@@ -0,0 +1,59 @@
+package connect_test
+
+import (
+	"context"
+	"testing"
+
+	"dumper/internal/connect"
+	connectDomain "dumper/internal/domain/connect"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newConnect(t *testing.T, srv *testServer) *connect.Connect {
+	t.Helper()
+	c := connect.NewApp(context.Background(), &connectDomain.Connect{
+		Server:          srv.host,
+		Port:            srv.port,
+		Username:        "root",
+		Password:        "s3cr3t",
+		HostKeyChecking: connectDomain.HostKeyOff,
+	})
+	t.Cleanup(func() { _ = c.Close() })
+	return c
+}
+
+func TestConnect_Reuses(t *testing.T) {
+	srv := newTestServer(t, passwordConfig("s3cr3t"))
+	c := newConnect(t, srv)
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, c.Connect())
+		_, err := c.RunCommand("pg_dump app")
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, 1, srv.Conns())
+	assert.Equal(t, []string{
+		"command -v bash >/dev/null 2>&1 && echo OK",
+		"bash -c 'set -o pipefail; pg_dump app'",
+		"bash -c 'set -o pipefail; pg_dump app'",
+		"bash -c 'set -o pipefail; pg_dump app'",
+	}, srv.Commands())
+}
+
+func TestConnect_ReconnectsDroppedConnection(t *testing.T) {
+	srv := newTestServer(t, passwordConfig("s3cr3t"))
+	c := newConnect(t, srv)
+
+	require.NoError(t, c.Connect())
+	require.NoError(t, c.TestConnection())
+
+	srv.Drop()
+	assert.False(t, c.IsConnected())
+
+	require.NoError(t, c.TestConnection())
+	assert.True(t, c.IsConnected())
+	assert.Equal(t, 2, srv.Conns())
+}
@@ -0,0 +1,76 @@
+package connect
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// applyEnv hands env to cmd without putting the values on a command line.
+// Variables go through the session when the server accepts them (AcceptEnv),
+// otherwise they are written to a 0600 file that the command sources and
+// removes before anything else runs. The returned cleanup removes that file
+// when the command could not be started.
+func (c *Connect) applyEnv(session *ssh.Session, cmd string, env map[string]string) (string, func(), error) {
+	noop := func() {}
+	if len(env) == 0 {
+		return cmd, noop, nil
+	}
+
+	accepted := true
+	for _, name := range envNames(env) {
+		if err := session.Setenv(name, env[name]); err != nil {
+			accepted = false
+			break
+		}
+	}
+	if accepted {
+		return cmd, noop, nil
+	}
+
+	suffix := make([]byte, 8)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", noop, err
+	}
+	envFile := "/tmp/.dumper-env-" + hex.EncodeToString(suffix)
+
+	upload, err := c.NewSession()
+	if err != nil {
+		return "", noop, err
+	}
+	defer upload.Close()
+
+	upload.Stdin = strings.NewReader(exportScript(env))
+	if err := upload.Run(fmt.Sprintf("umask 077 && cat > %s", envFile)); err != nil {
+		return "", noop, fmt.Errorf("failed to pass command environment: %w", err)
+	}
+
+	cleanup := func() {
+		_, _ = c.RunCommand(fmt.Sprintf("rm -f %s", envFile))
+	}
+
+	return fmt.Sprintf(". %s && rm -f %s && %s", envFile, envFile, cmd), cleanup, nil
+}
+
+func envNames(env map[string]string) []string {
+	names := make([]string, 0, len(env))
+	for name := range env {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// exportScript renders env as shell export statements.
+func exportScript(env map[string]string) string {
+	var sb strings.Builder
+	for _, name := range envNames(env) {
+		value := strings.ReplaceAll(env[name], `'`, `'\''`)
+		sb.WriteString(fmt.Sprintf("export %s='%s'\n", name, value))
+	}
+	return sb.String()
+}
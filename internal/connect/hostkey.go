@@ -0,0 +1,220 @@
+package connect
+
+import (
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	connectDomain "dumper/internal/domain/connect"
+	"dumper/pkg/logging"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+const defaultKnownHosts = "~/.ssh/known_hosts"
+
+// knownHostsMu serializes accept-new writes, backups of several servers
+// connect in parallel.
+var knownHostsMu sync.Mutex
+
+// HostKey is what buildSSHConfig needs to verify a server: the callback and
+// the key algorithms to ask the server for, so a host known by its ed25519
+// key is not reported as changed when it offers an RSA key first.
+type HostKey struct {
+	Callback   ssh.HostKeyCallback
+	Algorithms []string
+}
+
+// NewHostKey builds the host key check for conn. Pinned fingerprints take
+// precedence over known_hosts, the "off" policy accepts any key.
+func NewHostKey(ctx context.Context, conn *connectDomain.Connect, address string) (*HostKey, error) {
+	if len(conn.HostKeyFingerprints) > 0 {
+		return &HostKey{Callback: pinnedCallback(conn.HostKeyFingerprints)}, nil
+	}
+
+	policy := conn.HostKeyChecking
+	if policy == "" {
+		policy = connectDomain.HostKeyAcceptNew
+	}
+
+	switch policy {
+	case connectDomain.HostKeyOff:
+		logging.L(ctx).Warn("Host key checking is disabled", logging.StringAttr("server", address))
+		return &HostKey{Callback: ssh.InsecureIgnoreHostKey()}, nil
+	case connectDomain.HostKeyStrict, connectDomain.HostKeyAcceptNew:
+	default:
+		return nil, fmt.Errorf("unsupported host key checking policy '%s'", policy)
+	}
+
+	path, err := knownHostsPath(conn.KnownHosts)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ensureKnownHosts(path, policy); err != nil {
+		return nil, err
+	}
+
+	check, err := knownhosts.New(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read known_hosts %s: %w", path, err)
+	}
+
+	callback := func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+		err := check(hostname, remote, key)
+
+		var keyErr *knownhosts.KeyError
+		switch {
+		case err == nil:
+			return nil
+		case errors.As(err, &keyErr) && len(keyErr.Want) > 0:
+			return fmt.Errorf(
+				"host key for %s has changed to %s, possible man-in-the-middle attack, check %s:%d",
+				hostname, ssh.FingerprintSHA256(key), keyErr.Want[0].Filename, keyErr.Want[0].Line,
+			)
+		case errors.As(err, &keyErr) && policy == connectDomain.HostKeyAcceptNew:
+			if err := appendKnownHost(path, hostname, key); err != nil {
+				return err
+			}
+			logging.L(ctx).Warn(
+				"Permanently added host key to known_hosts",
+				logging.StringAttr("server", hostname),
+				logging.StringAttr("fingerprint", ssh.FingerprintSHA256(key)),
+			)
+			return nil
+		case errors.As(err, &keyErr):
+			return fmt.Errorf(
+				"host %s is not in %s, its key fingerprint is %s",
+				hostname, path, ssh.FingerprintSHA256(key),
+			)
+		default:
+			return fmt.Errorf("host key verification failed for %s: %w", hostname, err)
+		}
+	}
+
+	return &HostKey{
+		Callback:   callback,
+		Algorithms: knownAlgorithms(check, address),
+	}, nil
+}
+
+// ValidFingerprint reports whether s looks like an ssh-keygen -l fingerprint.
+func ValidFingerprint(s string) bool {
+	switch {
+	case strings.HasPrefix(s, "SHA256:"):
+		return len(s) == len("SHA256:")+43
+	case strings.HasPrefix(s, "MD5:"):
+		return len(s) == len("MD5:")+47
+	default:
+		return false
+	}
+}
+
+func pinnedCallback(fingerprints []string) ssh.HostKeyCallback {
+	return func(hostname string, _ net.Addr, key ssh.PublicKey) error {
+		sha := ssh.FingerprintSHA256(key)
+		md5 := "MD5:" + ssh.FingerprintLegacyMD5(key)
+
+		for _, fp := range fingerprints {
+			if fp == sha || strings.EqualFold(fp, md5) {
+				return nil
+			}
+		}
+
+		return fmt.Errorf("host key %s of %s does not match the pinned fingerprints", sha, hostname)
+	}
+}
+
+// knownAlgorithms asks known_hosts for the key types it holds for address,
+// by checking a throwaway key against it.
+func knownAlgorithms(check ssh.HostKeyCallback, address string) []string {
+	probe, err := ssh.NewPublicKey(ed25519.PublicKey(make([]byte, ed25519.PublicKeySize)))
+	if err != nil {
+		return nil
+	}
+
+	var keyErr *knownhosts.KeyError
+	if err := check(address, &net.TCPAddr{}, probe); !errors.As(err, &keyErr) {
+		return nil
+	}
+
+	var algorithms []string
+	seen := make(map[string]bool)
+	for _, known := range keyErr.Want {
+		types := []string{known.Key.Type()}
+		if known.Key.Type() == ssh.KeyAlgoRSA {
+			types = []string{ssh.KeyAlgoRSASHA512, ssh.KeyAlgoRSASHA256, ssh.KeyAlgoRSA}
+		}
+		for _, t := range types {
+			if !seen[t] {
+				seen[t] = true
+				algorithms = append(algorithms, t)
+			}
+		}
+	}
+
+	return algorithms
+}
+
+func knownHostsPath(path string) (string, error) {
+	if path == "" {
+		path = defaultKnownHosts
+	}
+
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve known_hosts path: %w", err)
+		}
+		path = filepath.Join(home, path[1:])
+	}
+
+	return path, nil
+}
+
+func ensureKnownHosts(path, policy string) error {
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read known_hosts %s: %w", path, err)
+	}
+
+	if policy == connectDomain.HostKeyStrict {
+		return fmt.Errorf("known_hosts %s does not exist, strict host key checking needs it", path)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create known_hosts dir: %w", err)
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to create known_hosts %s: %w", path, err)
+	}
+	return f.Close()
+}
+
+func appendKnownHost(path, hostname string, key ssh.PublicKey) error {
+	knownHostsMu.Lock()
+	defer knownHostsMu.Unlock()
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open known_hosts %s: %w", path, err)
+	}
+
+	line := knownhosts.Line([]string{knownhosts.Normalize(hostname)}, key)
+	if _, err := f.WriteString(line + "\n"); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write known_hosts %s: %w", path, err)
+	}
+
+	return f.Close()
+}
@@ -0,0 +1,113 @@
+package connect_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"dumper/internal/connect"
+	connectDomain "dumper/internal/domain/connect"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+const testAddress = "db.example.com:2222"
+
+var testRemote = &net.TCPAddr{IP: net.IPv4(10, 0, 0, 5), Port: 2222}
+
+func newKey(t *testing.T) ssh.PublicKey {
+	t.Helper()
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	key, err := ssh.NewPublicKey(pub)
+	require.NoError(t, err)
+	return key
+}
+
+func hostKey(t *testing.T, conn *connectDomain.Connect) *connect.HostKey {
+	t.Helper()
+	hk, err := connect.NewHostKey(context.Background(), conn, testAddress)
+	require.NoError(t, err)
+	return hk
+}
+
+func TestHostKey_AcceptNew(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ssh", "known_hosts")
+	conn := &connectDomain.Connect{KnownHosts: path, HostKeyChecking: connectDomain.HostKeyAcceptNew}
+	key := newKey(t)
+
+	require.NoError(t, hostKey(t, conn).Callback(testAddress, testRemote, key))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "[db.example.com]:2222 ssh-ed25519 ")
+
+	hk := hostKey(t, conn)
+	assert.Equal(t, []string{ssh.KeyAlgoED25519}, hk.Algorithms)
+	assert.NoError(t, hk.Callback(testAddress, testRemote, key))
+
+	err = hk.Callback(testAddress, testRemote, newKey(t))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "has changed")
+}
+
+func TestHostKey_Strict(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "known_hosts")
+	conn := &connectDomain.Connect{KnownHosts: path, HostKeyChecking: connectDomain.HostKeyStrict}
+
+	_, err := connect.NewHostKey(context.Background(), conn, testAddress)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "does not exist")
+
+	require.NoError(t, os.WriteFile(path, nil, 0600))
+	key := newKey(t)
+
+	err = hostKey(t, conn).Callback(testAddress, testRemote, key)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), ssh.FingerprintSHA256(key))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Empty(t, data)
+}
+
+func TestHostKey_Pinned(t *testing.T) {
+	key := newKey(t)
+	conn := &connectDomain.Connect{
+		HostKeyChecking:     connectDomain.HostKeyOff,
+		HostKeyFingerprints: []string{ssh.FingerprintSHA256(key)},
+	}
+
+	hk := hostKey(t, conn)
+
+	assert.NoError(t, hk.Callback(testAddress, testRemote, key))
+	assert.Error(t, hk.Callback(testAddress, testRemote, newKey(t)))
+}
+
+func TestHostKey_Off(t *testing.T) {
+	conn := &connectDomain.Connect{HostKeyChecking: connectDomain.HostKeyOff}
+
+	assert.NoError(t, hostKey(t, conn).Callback(testAddress, testRemote, newKey(t)))
+}
+
+func TestHostKey_UnknownPolicy(t *testing.T) {
+	conn := &connectDomain.Connect{HostKeyChecking: "ask"}
+
+	_, err := connect.NewHostKey(context.Background(), conn, testAddress)
+	assert.Error(t, err)
+}
+
+func TestValidFingerprint(t *testing.T) {
+	key := newKey(t)
+
+	assert.True(t, connect.ValidFingerprint(ssh.FingerprintSHA256(key)))
+	assert.True(t, connect.ValidFingerprint("MD5:"+ssh.FingerprintLegacyMD5(key)))
+	assert.False(t, connect.ValidFingerprint("SHA256:short"))
+	assert.False(t, connect.ValidFingerprint(ssh.FingerprintLegacyMD5(key)))
+}
@@ -0,0 +1,117 @@
+package connect
+
+import (
+	"dumper/internal/domain/config/server"
+	connectDomain "dumper/internal/domain/connect"
+	"fmt"
+	"net"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// JumpHosts turns a jump_hosts chain into the hops of a connection.
+func JumpHosts(hosts []server.JumpHost) []connectDomain.Connect {
+	if len(hosts) == 0 {
+		return nil
+	}
+
+	hops := make([]connectDomain.Connect, 0, len(hosts))
+	for _, h := range hosts {
+		hops = append(hops, connectDomain.Connect{
+			Server:              h.Host,
+			Port:                h.Port,
+			Username:            h.User,
+			PrivateKey:          h.PrivateKey,
+			Passphrase:          h.Passphrase,
+			Password:            h.Password,
+			Certificate:         h.Certificate,
+			HostKeyFingerprints: h.HostKeyFingerprints,
+		})
+	}
+
+	return hops
+}
+
+// dial connects through every jump host in turn, each hop tunnelling the
+// next TCP connection like ssh -J.
+func (c *Connect) dial() (*ssh.Client, error) {
+	var via *ssh.Client
+
+	for i := range c.connect.JumpHosts {
+		hop := &c.connect.JumpHosts[i]
+		c.inheritHop(hop)
+
+		client, err := c.dialVia(via, hop)
+		if err != nil {
+			c.closeJumps()
+			return nil, fmt.Errorf("jump host %s: %w", address(hop), err)
+		}
+
+		c.jumps = append(c.jumps, client)
+		via = client
+	}
+
+	client, err := c.dialVia(via, c.connect)
+	if err != nil {
+		c.closeJumps()
+		return nil, err
+	}
+
+	return client, nil
+}
+
+func (c *Connect) dialVia(via *ssh.Client, conn *connectDomain.Connect) (*ssh.Client, error) {
+	config, err := c.buildSSHConfig(conn)
+	if err != nil {
+		return nil, err
+	}
+
+	addr := address(conn)
+	if via == nil {
+		return ssh.Dial("tcp", addr, config)
+	}
+
+	netConn, err := via.Dial("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	sshConn, chans, reqs, err := ssh.NewClientConn(netConn, addr, config)
+	if err != nil {
+		_ = netConn.Close()
+		return nil, err
+	}
+
+	return ssh.NewClient(sshConn, chans, reqs), nil
+}
+
+// inheritHop fills what a jump host leaves unset from the target server.
+func (c *Connect) inheritHop(hop *connectDomain.Connect) {
+	if hop.Port == "" {
+		hop.Port = "22"
+	}
+	if hop.Username == "" {
+		hop.Username = c.connect.Username
+	}
+	if hop.PrivateKey == "" && hop.Password == "" {
+		hop.PrivateKey = c.connect.PrivateKey
+		hop.Passphrase = c.connect.Passphrase
+		hop.IsPassphrase = c.connect.IsPassphrase
+		hop.Password = c.connect.Password
+		hop.Certificate = c.connect.Certificate
+	}
+	hop.Agent = c.connect.Agent
+	hop.KnownHosts = c.connect.KnownHosts
+	hop.HostKeyChecking = c.connect.HostKeyChecking
+}
+
+func (c *Connect) closeJumps() {
+	for i := len(c.jumps) - 1; i >= 0; i-- {
+		_ = c.jumps[i].Close()
+	}
+	c.jumps = nil
+}
+
+func address(conn *connectDomain.Connect) string {
+	return net.JoinHostPort(conn.Server, conn.Port)
+}
@@ -0,0 +1,30 @@
+package connect
+
+import (
+	"dumper/internal/domain/config/server"
+	"dumper/internal/domain/config/setting"
+	connectDomain "dumper/internal/domain/connect"
+)
+
+// FromServer builds the connection to srv, taking what the server leaves
+// unset from the global settings.
+func FromServer(srv server.Server, settings *setting.Settings) *connectDomain.Connect {
+	isPassphrase := settings.SSH.IsPassphrase != nil && *settings.SSH.IsPassphrase
+
+	return &connectDomain.Connect{
+		Server:              srv.Host,
+		Port:                srv.GetPort(&settings.SrvPost),
+		Username:            srv.User,
+		Password:            srv.GetPassword(&settings.SSH.Password),
+		PrivateKey:          srv.GetPrivateKey(&settings.SSH.PrivateKey),
+		Passphrase:          srv.GetPassphrase(&settings.SSH.Passphrase),
+		IsPassphrase:        srv.GetIsPassphrase(isPassphrase),
+		KnownHosts:          srv.GetKnownHosts(&settings.SSH.KnownHosts),
+		HostKeyChecking:     srv.GetHostKeyChecking(&settings.SSH.HostKeyChecking),
+		HostKeyFingerprints: srv.HostKeyFingerprints,
+		Agent:               srv.GetAgent(settings.SSH.Agent),
+		Certificate:         srv.Certificate,
+		JumpHosts:           JumpHosts(srv.JumpHosts),
+		KeepAlive:           srv.KeepAlive,
+	}
+}
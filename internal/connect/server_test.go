@@ -0,0 +1,57 @@
+package connect_test
+
+import (
+	"testing"
+
+	"dumper/internal/connect"
+	"dumper/internal/domain/config/server"
+	"dumper/internal/domain/config/setting"
+	sshConfig "dumper/internal/domain/config/ssh-config"
+	connectDomain "dumper/internal/domain/connect"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromServer(t *testing.T) {
+	agent := true
+	isPassphrase := true
+	settings := &setting.Settings{
+		SrvPost: "22",
+		SSH: &sshConfig.SSHConfig{
+			PrivateKey:      "/keys/global",
+			Passphrase:      "global-phrase",
+			IsPassphrase:    &isPassphrase,
+			Password:        "global-password",
+			KnownHosts:      "/known_hosts",
+			HostKeyChecking: connectDomain.HostKeyStrict,
+			Agent:           &agent,
+		},
+	}
+
+	conn := connect.FromServer(server.Server{
+		Host:      "db.internal",
+		User:      "backup",
+		Port:      "2222",
+		Password:  "server-password",
+		JumpHosts: []server.JumpHost{{Host: "bastion"}},
+	}, settings)
+
+	assert.Equal(t, &connectDomain.Connect{
+		Server:          "db.internal",
+		Username:        "backup",
+		Port:            "2222",
+		Password:        "server-password",
+		PrivateKey:      "/keys/global",
+		Passphrase:      "global-phrase",
+		IsPassphrase:    true,
+		KnownHosts:      "/known_hosts",
+		HostKeyChecking: connectDomain.HostKeyStrict,
+		Agent:           true,
+		JumpHosts:       []connectDomain.Connect{{Server: "bastion"}},
+	}, conn)
+
+	conn = connect.FromServer(server.Server{Host: "db.internal", User: "backup"}, settings)
+
+	assert.Equal(t, "22", conn.Port)
+	assert.Equal(t, "global-password", conn.Password)
+}
@@ -0,0 +1,37 @@
+package connect
+
+import (
+	"fmt"
+
+	"dumper/pkg/logging"
+
+	"github.com/pkg/sftp"
+)
+
+// NewSFTP starts an SFTP session on the shared client, reconnecting once
+// when the connection has dropped like NewSession does.
+func (c *Connect) NewSFTP(opts ...sftp.ClientOption) (*sftp.Client, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.client == nil {
+		return nil, fmt.Errorf("SSH client is not connected")
+	}
+
+	client, err := sftp.NewClient(c.client, opts...)
+	if err == nil || c.alive() {
+		return client, err
+	}
+
+	logging.L(c.ctx).Warn(
+		"SSH connection lost, reconnecting",
+		logging.StringAttr("server", c.connect.Server),
+		logging.ErrAttr(err),
+	)
+
+	if err := c.connectLocked(); err != nil {
+		return nil, err
+	}
+
+	return sftp.NewClient(c.client, opts...)
+}
@@ -0,0 +1,33 @@
+package connect
+
+import (
+	"bytes"
+	"dumper/pkg/utils/mask"
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/ssh"
+)
+
+type Stream struct {
+	Stdout  io.Reader
+	session *ssh.Session
+	stderr  bytes.Buffer
+}
+
+func (s *Stream) Wait() error {
+	defer s.session.Close()
+
+	if err := s.session.Wait(); err != nil {
+		return fmt.Errorf(
+			"command failed: %v\nstderr: %s",
+			err, mask.Mask(s.stderr.String()),
+		)
+	}
+
+	return nil
+}
+
+func (s *Stream) Close() error {
+	return s.session.Close()
+}
@@ -1,13 +1,14 @@
 package crypt_backup
 
 import (
-	"bytes"
-	"dumper/internal/command/encrypt"
-	encOpts "dumper/internal/domain/encrypt"
+	"dumper/internal/domain/config/encrypt"
+	"dumper/pkg/utils/aesstream"
+	"dumper/pkg/utils/age"
+	"dumper/pkg/utils/suffix"
 	"errors"
 	"fmt"
+	"io"
 	"os"
-	"os/exec"
 	"strings"
 
 	"golang.org/x/term"
@@ -16,6 +17,7 @@ import (
 type CryptBackup struct {
 	FilePath string
 	Password string
+	Identity string
 	Crypt    string
 	Type     string
 }
@@ -23,19 +25,20 @@ type CryptBackup struct {
 func NewApp(
 	filePath string,
 	password string,
+	identity string,
 	crypt string,
 	typeCrypt string,
 ) *CryptBackup {
 	return &CryptBackup{
 		FilePath: filePath,
 		Password: password,
+		Identity: identity,
 		Crypt:    crypt,
 		Type:     typeCrypt,
 	}
 }
 
 func (e *CryptBackup) Run() error {
-
 	switch e.Type {
 	case "decrypt":
 		return e.Decrypt()
@@ -44,56 +47,52 @@ func (e *CryptBackup) Run() error {
 	default:
 		return errors.New("unknown crypt type: " + e.Type)
 	}
-
-	return nil
 }
 
+// Decrypt writes the plaintext of FilePath next to it without the .enc
+// suffix. Age files are opened with the Identity file, anything else with the
+// password, including dumps encrypted by older versions with openssl.
 func (e *CryptBackup) Decrypt() error {
 	if e.FilePath == "" {
 		return fmt.Errorf("file path is empty")
 	}
 
-	if e.Password == "" {
-		fmt.Println("Enter the password :")
-		password, err := term.ReadPassword(int(os.Stdin.Fd()))
-		if err != nil {
-			return fmt.Errorf("input error: %v", err)
-		}
-
-		e.Password = strings.TrimSpace(string(password))
+	outPath := suffix.RemoveSuffix(e.FilePath, ".enc")
+	if outPath == e.FilePath {
+		return fmt.Errorf("encrypted file must have the .enc suffix: %s", e.FilePath)
 	}
 
-	encOption := encOpts.Options{
-		Password: e.Password,
-		FilePath: e.FilePath,
-		Type:     e.Crypt,
-		Crypt:    "decrypt",
-	}
-
-	encApp := encrypt.NewApp(&encOption)
-	cmdData, err := encApp.Generate()
+	isAge, err := e.isAge()
 	if err != nil {
 		return err
 	}
 
-	args := strings.Fields(cmdData.CMD)
-	bin := args[0]
-	args = args[1:]
+	var decrypt func(io.Reader) (io.Reader, error)
+	if isAge {
+		if e.Identity == "" {
+			return fmt.Errorf("backup is encrypted with age, set the identity file")
+		}
 
-	binPath, err := exec.LookPath(bin)
-	if err != nil {
-		return fmt.Errorf("cannot find binary %s: %v", bin, err)
-	}
+		identities, err := age.ReadIdentityFile(e.Identity)
+		if err != nil {
+			return err
+		}
 
-	cmd := exec.Command(binPath, args...)
-	cmd.Env = os.Environ()
+		decrypt = func(src io.Reader) (io.Reader, error) {
+			return age.Decrypt(src, identities...)
+		}
+	} else {
+		if err := e.readPassword(); err != nil {
+			return err
+		}
 
-	var out bytes.Buffer
-	cmd.Stdout = &out
-	cmd.Stderr = &out
+		decrypt = func(src io.Reader) (io.Reader, error) {
+			return aesstream.Decrypt(src, e.Password)
+		}
+	}
 
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("decryption failed: %v\nOutput: %s", err, out.String())
+	if err := e.transform(outPath, decrypt); err != nil {
+		return fmt.Errorf("decryption failed: %v", err)
 	}
 
 	fmt.Println("Decryption succeeded")
@@ -105,49 +104,87 @@ func (e *CryptBackup) Encrypt() error {
 		return fmt.Errorf("file path is empty")
 	}
 
-	if e.Password == "" {
-		fmt.Println("Enter the password :")
-		password, err := term.ReadPassword(int(os.Stdin.Fd()))
-		if err != nil {
-			return fmt.Errorf("input error: %v", err)
-		}
-
-		e.Password = strings.TrimSpace(string(password))
+	if e.Crypt != encrypt.TypeAES {
+		return fmt.Errorf("unsupported encryption type: %s", e.Crypt)
 	}
 
-	encOption := encOpts.Options{
-		Password: e.Password,
-		FilePath: e.FilePath,
-		Type:     e.Crypt,
-		Crypt:    "encrypt",
+	if err := e.readPassword(); err != nil {
+		return err
 	}
 
-	encApp := encrypt.NewApp(&encOption)
-	cmdData, err := encApp.Generate()
+	err := e.transform(e.FilePath+".enc", func(src io.Reader) (io.Reader, error) {
+		return aesstream.Encrypt(src, e.Password, "")
+	})
 	if err != nil {
-		return err
+		return fmt.Errorf("encryption failed: %v", err)
 	}
 
-	args := strings.Fields(cmdData.CMD)
-	bin := args[0]
-	args = args[1:]
+	fmt.Println("Encryption succeeded")
+	return nil
+}
 
-	binPath, err := exec.LookPath(bin)
+func (e *CryptBackup) isAge() (bool, error) {
+	file, err := os.Open(e.FilePath)
 	if err != nil {
-		return fmt.Errorf("cannot find binary %s: %v", bin, err)
+		return false, err
 	}
+	defer file.Close()
 
-	cmd := exec.Command(binPath, args...)
-	cmd.Env = os.Environ()
+	head := make([]byte, 64)
+	n, err := io.ReadFull(file, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return false, err
+	}
 
-	var out bytes.Buffer
-	cmd.Stdout = &out
-	cmd.Stderr = &out
+	return age.IsEncrypted(head[:n]), nil
+}
 
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("encryption failed: %v\nOutput: %s", err, out.String())
+func (e *CryptBackup) readPassword() error {
+	if e.Password != "" {
+		return nil
 	}
 
-	fmt.Println("Encryption succeeded")
+	fmt.Println("Enter the password :")
+	password, err := term.ReadPassword(int(os.Stdin.Fd()))
+	if err != nil {
+		return fmt.Errorf("input error: %v", err)
+	}
+
+	e.Password = strings.TrimSpace(string(password))
 	return nil
 }
+
+// transform streams FilePath through wrap into outPath. The output is
+// written to a temporary file first, so a failed run never leaves a partial
+// result behind.
+func (e *CryptBackup) transform(outPath string, wrap func(io.Reader) (io.Reader, error)) error {
+	in, err := os.Open(e.FilePath)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	src, err := wrap(in)
+	if err != nil {
+		return err
+	}
+
+	tmpPath := outPath + ".part"
+	out, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, src); err != nil {
+		_ = out.Close()
+		_ = os.Remove(tmpPath)
+		return err
+	}
+
+	if err := out.Close(); err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+
+	return os.Rename(tmpPath, outPath)
+}
@@ -31,6 +31,7 @@ func (c *Crypt) Run() error {
 		cryptBackupApp := cryptBackup.NewApp(
 			c.flags.Input,
 			c.flags.Password,
+			c.flags.Identity,
 			"aes",
 			c.flags.Mode,
 		)
@@ -0,0 +1,147 @@
+package discover
+
+import (
+	"context"
+	command "dumper/internal/command/database"
+	"dumper/internal/connect"
+	connecterror "dumper/internal/connect/connect-error"
+	commandDomain "dumper/internal/domain/command"
+	commandConfig "dumper/internal/domain/command-config"
+	"dumper/internal/domain/config"
+	dbConnect "dumper/internal/domain/config/db-connect"
+	discoverDomain "dumper/internal/domain/config/discover"
+	"dumper/pkg/logging"
+	"dumper/pkg/utils/runner"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+type Discover struct {
+	ctx  context.Context
+	cfg  *config.Config
+	conn *connect.Connect
+}
+
+func NewApp(
+	ctx context.Context,
+	cfg *config.Config,
+	conn *connect.Connect,
+) *Discover {
+	return &Discover{
+		ctx:  ctx,
+		cfg:  cfg,
+		conn: conn,
+	}
+}
+
+// Databases lists the databases on the server of a discover entry and
+// returns one entry per database kept by its include and exclude patterns.
+// The entries are keyed <key>_<database> and inherit every setting of the
+// discover entry.
+func (d *Discover) Databases(entry dbConnect.DBConnect) ([]dbConnect.DBConnect, error) {
+	logging.L(d.ctx).Info("Discovering databases", logging.StringAttr("db", entry.Database.Key))
+
+	cmdDB, err := command.NewApp(d.ctx, d.commandConfig(entry)).GetDiscoverCommand()
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := d.run(cmdDB, entry.Server.Host)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list databases of %s: %w", entry.Database.Key, err)
+	}
+
+	names, rejected := parse(out, entry.Database.Discover)
+	for _, name := range rejected {
+		logging.L(d.ctx).Warn(
+			"Skipping database with unsafe name",
+			logging.StringAttr("db", entry.Database.Key),
+			logging.StringAttr("name", name),
+		)
+	}
+
+	logging.L(d.ctx).Info(
+		"Databases discovered",
+		logging.StringAttr("db", entry.Database.Key),
+		logging.StringAttr("names", strings.Join(names, ",")),
+	)
+
+	list := make([]dbConnect.DBConnect, 0, len(names))
+	for _, name := range names {
+		db := entry.Database
+		db.Key = entry.Database.Key + "_" + name
+		db.Name = name
+		db.Title = name
+		db.Discover = nil
+
+		if entry.Database.Title != "" {
+			db.Title = fmt.Sprintf("%s (%s)", entry.Database.Title, name)
+		}
+
+		list = append(list, dbConnect.DBConnect{
+			Database: db,
+			Server:   entry.Server,
+			Storages: entry.Storages,
+		})
+	}
+
+	return list, nil
+}
+
+func (d *Discover) run(cmdDB *commandDomain.DBCommand, host string) (string, error) {
+	if d.cfg.Settings.DumpLocation == "local-direct" {
+		return runner.RunLocalCommandWithInput(d.ctx, "", cmdDB.Command, cmdDB.Env, nil)
+	}
+
+	if err := runner.RunWithCtx(d.ctx, d.conn.Connect); err != nil {
+		return "", &connecterror.ConnectError{Addr: host, Err: err}
+	}
+
+	return d.conn.RunCommandWithEnv(cmdDB.Command, cmdDB.Env)
+}
+
+func (d *Discover) commandConfig(entry dbConnect.DBConnect) *commandConfig.Config {
+	dbHost := entry.Database.Host
+	if d.cfg.Settings.DumpLocation == "local-direct" {
+		dbHost = entry.Database.GetHost(entry.Server.Host)
+	}
+
+	return &commandConfig.Config{
+		Database: commandConfig.Database{
+			Key:      entry.Database.Key,
+			User:     entry.Database.User,
+			Password: entry.Database.Password,
+			Host:     dbHost,
+			Port:     entry.Database.GetPort(&d.cfg.Settings.DBPort),
+			Format:   entry.Database.GetFormat(&d.cfg.Settings.DumpFormat),
+			Driver:   entry.Database.GetDriver(&d.cfg.Settings.Driver),
+			Options:  entry.Database.GetOptions(),
+			Docker:   entry.Database.GetDocker(d.cfg.Settings.Docker),
+		},
+		DumpLocation: d.cfg.Settings.DumpLocation,
+	}
+}
+
+// safeName matches the database names that may go into the dump commands
+// and file paths unquoted. Anything else, like a name holding a quote, a
+// space or a $, is rejected rather than run through a shell.
+var safeName = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9_.-]*$`)
+
+// parse reads one database per line and keeps the ones the rule matches.
+// Matching names that are not safe to use are returned as rejected.
+func parse(out string, rule *discoverDomain.Discover) (names, rejected []string) {
+	for _, line := range strings.Split(out, "\n") {
+		name := strings.TrimSpace(line)
+		if name == "" || !rule.Match(name) {
+			continue
+		}
+		if !safeName.MatchString(name) {
+			rejected = append(rejected, name)
+			continue
+		}
+		names = append(names, name)
+	}
+
+	return names, rejected
+}
@@ -0,0 +1,69 @@
+package discover
+
+import (
+	discoverDomain "dumper/internal/domain/config/discover"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	out := "tenant_acme\ntenant_globex\n\ntenant_demo_test\r\nbilling\n"
+
+	tests := []struct {
+		name string
+		rule discoverDomain.Discover
+		want []string
+	}{
+		{
+			name: "no filters",
+			rule: discoverDomain.Discover{Enabled: true},
+			want: []string{"tenant_acme", "tenant_globex", "tenant_demo_test", "billing"},
+		},
+		{
+			name: "include",
+			rule: discoverDomain.Discover{Enabled: true, Include: []string{"^tenant_"}},
+			want: []string{"tenant_acme", "tenant_globex", "tenant_demo_test"},
+		},
+		{
+			name: "exclude wins over include",
+			rule: discoverDomain.Discover{Enabled: true, Include: []string{"^tenant_"}, Exclude: []string{"_test$", "globex"}},
+			want: []string{"tenant_acme"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			names, rejected := parse(out, &tt.rule)
+			assert.Equal(t, tt.want, names)
+			assert.Empty(t, rejected)
+		})
+	}
+}
+
+func TestParse_Empty(t *testing.T) {
+	names, _ := parse("\n", &discoverDomain.Discover{Enabled: true})
+	assert.Empty(t, names)
+}
+
+func TestParse_RejectsHostileNames(t *testing.T) {
+	hostile := []string{
+		"x; rm -rf /",
+		"$(id)",
+		"`id`",
+		"a'b",
+		`a"b`,
+		"a b",
+		"app|nc",
+		"-oProxyCommand=id",
+		"../etc",
+		".hidden",
+	}
+	out := "app\n" + strings.Join(hostile, "\n") + "\ntenant-1.v2\n"
+
+	names, rejected := parse(out, &discoverDomain.Discover{Enabled: true})
+
+	assert.Equal(t, []string{"app", "tenant-1.v2"}, names)
+	assert.Equal(t, hostile, rejected)
+}
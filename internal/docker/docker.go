@@ -6,6 +6,7 @@ import (
 	commandConfig "dumper/internal/domain/command-config"
 	"dumper/pkg/logging"
 	"fmt"
+	"sort"
 	"strings"
 )
 
@@ -30,15 +31,49 @@ func NewApp(
 func (d *Docker) Prepare() {
 	logging.L(d.ctx).Info("Prepare docker command")
 
-	dockerCommand := d.config.Database.Docker.Command
+	d.cmdData.Command = wrap(d.withEnv(d.config.Database.Docker.Command), d.cmdData.Command)
+
+	if d.cmdData.Commit != "" {
+		d.cmdData.Commit = wrap(d.config.Database.Docker.Command, d.cmdData.Commit)
+	}
+}
+
+// wrap runs cmd through dockerCommand, in place of its {%cmd%} placeholder
+// or after it.
+func wrap(dockerCommand, cmd string) string {
 	placeholder := "{%cmd%}"
-	var result string
 
 	if strings.Contains(dockerCommand, placeholder) {
-		result = strings.ReplaceAll(dockerCommand, placeholder, d.cmdData.Command)
-	} else {
-		result = fmt.Sprintf("%s %s", d.config.Database.Docker.Command, d.cmdData.Command)
+		return strings.ReplaceAll(dockerCommand, placeholder, cmd)
+	}
+	return fmt.Sprintf("%s %s", dockerCommand, cmd)
+}
+
+// withEnv forwards the command env into the container. `exec -e NAME` takes
+// the value from the environment of the docker client, so credentials stay
+// off the command line there too.
+func (d *Docker) withEnv(dockerCommand string) string {
+	if len(d.cmdData.Env) == 0 {
+		return dockerCommand
+	}
+
+	idx := strings.Index(" "+dockerCommand+" ", " exec ")
+	if idx < 0 {
+		logging.L(d.ctx).Warn("Docker command has no exec, credentials are not forwarded into the container")
+		return dockerCommand
+	}
+
+	names := make([]string, 0, len(d.cmdData.Env))
+	for name := range d.cmdData.Env {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var flags strings.Builder
+	for _, name := range names {
+		flags.WriteString(" -e " + name)
 	}
 
-	d.cmdData.Command = result
+	end := idx + len("exec")
+	return dockerCommand[:end] + flags.String() + dockerCommand[end:]
 }
@@ -8,9 +8,17 @@ type Flags struct {
 	Input          string
 	Crypt          string
 	Password       string
+	Identity       string
 	Mode           string
 	Recovery       string
 	AppSecret      string
 	OpenOnlyEncEnv bool
 	Scope          string
+	From           string
+	List           bool
+	Show           string
+	Verify         string
+	Daemon         bool
+	Report         string
+	ReportFile     string
 }
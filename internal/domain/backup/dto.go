@@ -4,3 +4,22 @@ type FileRemoveList struct {
 	Name     string
 	IsRemove bool
 }
+
+// ExtGlobals marks the pg_dumpall --globals-only companion of a dump.
+const ExtGlobals = "globals.sql"
+
+// CompanionExts are the extensions of the companion dumps, a companion lives
+// and expires with the dump it was taken with.
+var CompanionExts = []string{ExtGlobals}
+
+// Phases of a backup run, a failure is reported with the phase it happened in.
+const (
+	PhasePrepare     = "prepare"
+	PhaseConnect     = "connect"
+	PhaseShellBefore = "shell-before"
+	PhaseDump        = "dump"
+	PhaseShellAfter  = "shell-after"
+	PhaseUpload      = "upload"
+	PhaseRetention   = "retention"
+	PhaseArchive     = "archive"
+)
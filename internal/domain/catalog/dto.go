@@ -0,0 +1,37 @@
+package catalog
+
+import "time"
+
+type Entry struct {
+	ID         string    `json:"id"`
+	Database   string    `json:"db"`
+	Server     string    `json:"server"`
+	Driver     string    `json:"driver"`
+	Format     string    `json:"format"`
+	DumpName   string    `json:"dump_name"`
+	Path       string    `json:"path,omitempty"`
+	Size       int64     `json:"size"`
+	Sha256     string    `json:"sha256,omitempty"`
+	Encryption string    `json:"encryption,omitempty"`
+	Storages   []string  `json:"storages"`
+	Uploads    []Upload  `json:"uploads,omitempty"`
+	Companions []string  `json:"companions,omitempty"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	Status     string    `json:"status"`
+	Phase      string    `json:"phase,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	// Warnings are failures after the dump was stored, such as retention,
+	// which leave the backup itself good.
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// Upload is the outcome of sending a dump to one storage.
+type Upload struct {
+	Storage  string  `json:"storage"`
+	Type     string  `json:"type"`
+	Status   string  `json:"status"`
+	Duration float64 `json:"duration_sec"`
+	Sha256   string  `json:"sha256,omitempty"`
+	Error    string  `json:"error,omitempty"`
+}
@@ -2,14 +2,18 @@ package command_config
 
 import (
 	"dumper/internal/domain/backup"
+	"dumper/internal/domain/catalog"
+	"dumper/internal/domain/config/archive"
 	"dumper/internal/domain/config/docker"
 	"dumper/internal/domain/config/encrypt"
 	"dumper/internal/domain/config/option"
+	"dumper/internal/domain/config/retention"
 	"dumper/internal/domain/config/shell"
 	"dumper/internal/domain/config/storage"
 )
 
 type Database struct {
+	Key      string
 	User     string
 	Password string
 	Name     string
@@ -17,6 +21,7 @@ type Database struct {
 	Format   string
 	Driver   string
 	Options  option.Options
+	Host     string
 	Archive  bool
 	Docker   docker.Docker
 
@@ -31,20 +36,49 @@ type Server struct {
 }
 
 type Config struct {
-	Database            Database
-	Server              Server
-	Storages            map[string]storage.Storage
-	DumpLocation        string
-	Archive             bool
+	Database     Database
+	Server       Server
+	Storages     map[string]storage.Storage
+	DumpLocation string
+	Archive      archive.Archive
+	// StreamCompress gzips the dump while it streams, set on local-ssh
+	// when the server has no compressor for Archive.
+	StreamCompress      bool
 	RemoveBackup        bool
 	Command             string
+	Env                 map[string]string
 	DumpName            string
 	DumpDirRemote       string
 	DumpDirLocal        string
 	DumpNameTemplate    string
 	Encrypt             encrypt.Encrypt
 	MaxParallelDownload int
+	RetryConnect        int
 	Shell               shell.Shell
 	FileRemoveList      []backup.FileRemoveList
 	FileSize            int64
+	Checksum            string
+	Phase               string
+	Uploaded            []string
+	Uploads             []catalog.Upload
+	Companions          []string
+	Retention           retention.Retention
+	// Catalog is the path of the backup catalog, empty when disabled.
+	Catalog string
+}
+
+func (d Database) GetHost(defaultHost string) string {
+	if d.Host != "" {
+		return d.Host
+	}
+	return defaultHost
+}
+
+// StoredName is the dump name as it lands on the storages: encrypted dumps
+// get the .enc suffix.
+func (c *Config) StoredName() string {
+	if c.Encrypt.IsEnabled() {
+		return c.DumpName + ".enc"
+	}
+	return c.DumpName
 }
@@ -1,6 +1,50 @@
 package command
 
+import (
+	"fmt"
+	"strings"
+)
+
 type DBCommand struct {
 	Command  string
 	DumpPath string
+
+	// Env holds the credentials of the command. They are handed over
+	// through the environment so they never show up in the process list.
+	Env map[string]string
+
+	// Commit runs where the dump was taken once every storage holds the
+	// dump, to move on the state the next dump starts from.
+	Commit string
+}
+
+// SecretEnv returns vars without the empty values, or nil when nothing is
+// left.
+func SecretEnv(vars map[string]string) map[string]string {
+	env := make(map[string]string, len(vars))
+	for name, value := range vars {
+		if value != "" {
+			env[name] = value
+		}
+	}
+
+	if len(env) == 0 {
+		return nil
+	}
+
+	return env
+}
+
+// CurlConfigLine renders one option of a curl config file (curl -K), with
+// the value quoted so it may hold any character.
+func CurlConfigLine(name, value string) string {
+	value = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`).Replace(value)
+	return fmt.Sprintf("%s = \"%s\"\n", name, value)
+}
+
+// Shell runs cmd through sh -c, so a pipeline or a list of commands stays
+// a single command to whatever wraps it. With docker enabled the whole of
+// cmd runs in the container, not only its first word.
+func Shell(cmd string) string {
+	return "sh -c '" + strings.ReplaceAll(cmd, `'`, `'\''`) + "'"
 }
@@ -0,0 +1,151 @@
+package archive
+
+import (
+	"fmt"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	AlgoGzip = "gzip"
+	AlgoPigz = "pigz"
+	AlgoZstd = "zstd"
+	AlgoXz   = "xz"
+	AlgoLz4  = "lz4"
+	AlgoNone = "none"
+)
+
+// maxLevels holds the highest compression level every algorithm accepts.
+var maxLevels = map[string]int{
+	AlgoGzip: 9,
+	AlgoPigz: 11,
+	AlgoZstd: 22,
+	AlgoXz:   9,
+	AlgoLz4:  12,
+}
+
+var extensions = map[string]string{
+	AlgoGzip: "gz",
+	AlgoPigz: "gz",
+	AlgoZstd: "zst",
+	AlgoXz:   "xz",
+	AlgoLz4:  "lz4",
+}
+
+type Archive struct {
+	Algo    string `yaml:"algo" json:"algo,omitempty"`
+	Level   int    `yaml:"level" json:"level,omitempty"`
+	Threads int    `yaml:"threads" json:"threads,omitempty"`
+}
+
+// UnmarshalYAML keeps the older `archive: true|false` form working next to
+// the mapping, a bare algorithm name (`archive: zstd`) is accepted as well.
+func (a *Archive) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		if enabled, err := strconv.ParseBool(node.Value); err == nil {
+			*a = Archive{Algo: AlgoGzip}
+			if !enabled {
+				a.Algo = AlgoNone
+			}
+			return nil
+		}
+
+		*a = Archive{Algo: node.Value}
+		return nil
+	}
+
+	type plain Archive
+	return node.Decode((*plain)(a))
+}
+
+// IsEnabled reports whether dumps are compressed at all.
+func (a Archive) IsEnabled() bool {
+	return a.Algo != "" && a.Algo != AlgoNone
+}
+
+// Ext is the file extension of the compressed dump, without the dot.
+func (a Archive) Ext() string {
+	return extensions[a.Algo]
+}
+
+// TarExt is the extension of a tarball compressed with the algorithm.
+func (a Archive) TarExt() string {
+	if !a.IsEnabled() {
+		return "tar"
+	}
+	return "tar." + a.Ext()
+}
+
+// Command is the compressor reading stdin and writing to stdout.
+func (a Archive) Command() string {
+	cmd := a.Algo
+
+	switch a.Algo {
+	case AlgoZstd, AlgoLz4:
+		cmd += " -q"
+	}
+
+	if a.Level > 0 {
+		if a.Algo == AlgoZstd && a.Level > 19 {
+			cmd += " --ultra"
+		}
+		cmd += fmt.Sprintf(" -%d", a.Level)
+	}
+
+	if a.Threads > 0 {
+		switch a.Algo {
+		case AlgoPigz:
+			cmd += fmt.Sprintf(" -p %d", a.Threads)
+		case AlgoZstd, AlgoXz:
+			cmd += fmt.Sprintf(" -T%d", a.Threads)
+		}
+	}
+
+	return cmd
+}
+
+// Pipe appends the compressor to a command writing the dump to stdout.
+func (a Archive) Pipe() string {
+	return " | " + a.Command()
+}
+
+// File compresses the dump at src into dst, src stays in place.
+func (a Archive) File(src, dst string) string {
+	return fmt.Sprintf("%s -c %s > %s", a.Command(), src, dst)
+}
+
+// Tar returns the tar invocation creating the archive, the file name and
+// the members are appended by the caller.
+func (a Archive) Tar() string {
+	switch {
+	case !a.IsEnabled():
+		return "tar -cf"
+	case a.Algo == AlgoGzip && a.Level == 0:
+		return "tar -czf"
+	default:
+		return fmt.Sprintf("tar -I '%s' -cf", a.Command())
+	}
+}
+
+// MaxLevel is the highest level the algorithm accepts, 0 when it has none.
+func (a Archive) MaxLevel() int {
+	return maxLevels[a.Algo]
+}
+
+// Merge fills the fields left empty with the global setting, so a database
+// can raise the level without repeating the algorithm.
+func (a Archive) Merge(global Archive) Archive {
+	if a.Algo == "" {
+		a.Algo = global.Algo
+		if a.Level == 0 {
+			a.Level = global.Level
+		}
+	}
+
+	if a.Threads == 0 {
+		a.Threads = global.Threads
+	}
+
+	return a
+}
@@ -1,31 +1,38 @@
 package database
 
 import (
+	"dumper/internal/domain/config/archive"
+	"dumper/internal/domain/config/discover"
 	"dumper/internal/domain/config/docker"
 	"dumper/internal/domain/config/encrypt"
 	"dumper/internal/domain/config/option"
+	"dumper/internal/domain/config/retention"
 	"dumper/internal/domain/config/shell"
 )
 
 type Database struct {
-	Title      string           `yaml:"title,omitempty" json:"title,omitempty"`
-	User       string           `yaml:"user" json:"user,omitempty"`
-	Password   string           `yaml:"password" json:"password,omitempty"`
-	Name       string           `yaml:"name" json:"name,omitempty"`
-	Server     string           `yaml:"server" validate:"required" json:"server,omitempty"`
-	Key        string           `yaml:"key" json:"key,omitempty"`
-	Port       string           `yaml:"port" json:"port,omitempty"`
-	Driver     string           `yaml:"driver" validate:"required" json:"driver,omitempty"`
-	Format     string           `yaml:"format" validate:"required" json:"format,omitempty"`
-	Options    *option.Options  `yaml:"options" json:"options,omitempty"`
-	RemoveDump *bool            `yaml:"remove_dump" json:"removeDump,omitempty"`
-	Encrypt    *encrypt.Encrypt `yaml:"encrypt" json:"encrypt,omitempty"`
-	Storages   []string         `yaml:"storages" validate:"required" json:"storages,omitempty"`
-	Archive    *bool            `yaml:"archive" json:"archive,omitempty"`
-	Docker     *docker.Docker   `yaml:"docker" json:"docker,omitempty"`
-	Shell      *shell.Shell     `yaml:"shell" json:"shell,omitempty"`
-	DirRemote  string           `yaml:"dir_remote" json:"dirRemote,omitempty"`
-	Token      string           `yaml:"token" json:"token,omitempty"`
+	Title      string               `yaml:"title,omitempty" json:"title,omitempty"`
+	User       string               `yaml:"user" json:"user,omitempty"`
+	Password   string               `yaml:"password" json:"password,omitempty"`
+	Name       string               `yaml:"name" json:"name,omitempty"`
+	Server     string               `yaml:"server" validate:"required" json:"server,omitempty"`
+	Key        string               `yaml:"key" json:"key,omitempty"`
+	Host       string               `yaml:"host" json:"host,omitempty"`
+	Port       string               `yaml:"port" json:"port,omitempty"`
+	Driver     string               `yaml:"driver" validate:"required" json:"driver,omitempty"`
+	Format     string               `yaml:"format" validate:"required" json:"format,omitempty"`
+	Options    *option.Options      `yaml:"options" json:"options,omitempty"`
+	RemoveDump *bool                `yaml:"remove_dump" json:"removeDump,omitempty"`
+	Encrypt    *encrypt.Encrypt     `yaml:"encrypt" json:"encrypt,omitempty"`
+	Storages   []string             `yaml:"storages" validate:"required" json:"storages,omitempty"`
+	Archive    *archive.Archive     `yaml:"archive" json:"archive,omitempty"`
+	Docker     *docker.Docker       `yaml:"docker" json:"docker,omitempty"`
+	Shell      *shell.Shell         `yaml:"shell" json:"shell,omitempty"`
+	DirRemote  string               `yaml:"dir_remote" json:"dirRemote,omitempty"`
+	Token      string               `yaml:"token" json:"token,omitempty"`
+	Retention  *retention.Retention `yaml:"retention" json:"retention,omitempty"`
+	Schedule   string               `yaml:"schedule" json:"schedule,omitempty"`
+	Discover   *discover.Discover   `yaml:"discover" json:"discover,omitempty"`
 }
 
 func (d *Database) GetName() string {
@@ -35,6 +42,19 @@ func (d *Database) GetName() string {
 	return d.User
 }
 
+// IsDiscover reports whether the entry stands for every database found on
+// its server rather than for a single one.
+func (d *Database) IsDiscover() bool {
+	return d.Discover != nil && d.Discover.Enabled
+}
+
+func (d *Database) GetHost(defaultHost string) string {
+	if d.Host != "" {
+		return d.Host
+	}
+	return defaultHost
+}
+
 func (d *Database) GetPort(port *string) string {
 	if d.Port != "" {
 		return d.Port
@@ -76,13 +96,32 @@ func (d *Database) GetEncrypt(encryptGlobal *encrypt.Encrypt) encrypt.Encrypt {
 		return *encryptGlobal
 	}
 
-	if *d.Encrypt.Enabled && d.Encrypt.Password == "" && d.Encrypt.Type == "" {
+	if *d.Encrypt.Enabled && d.Encrypt.Password == "" && len(d.Encrypt.Recipients) == 0 && d.Encrypt.Type == "" {
 		return *encryptGlobal
 	}
 
 	return *d.Encrypt
 }
 
+func (d *Database) GetRetention(retentionGlobal *retention.Retention) retention.Retention {
+	if d.Retention != nil {
+		return *d.Retention
+	}
+
+	if retentionGlobal != nil {
+		return *retentionGlobal
+	}
+
+	return retention.Retention{}
+}
+
+func (d *Database) GetSchedule(globalSchedule string) string {
+	if d.Schedule != "" {
+		return d.Schedule
+	}
+	return globalSchedule
+}
+
 func (d *Database) GetTitle() string {
 	if d.Title != "" {
 		return d.Title
@@ -95,11 +134,11 @@ func (d *Database) GetTitle() string {
 	return d.User
 }
 
-func (d *Database) IsArchive(isGlobalArchive bool) bool {
-	if d.Archive != nil {
-		return *d.Archive
+func (d *Database) GetArchive(globalArchive *archive.Archive) archive.Archive {
+	if d.Archive == nil {
+		return *globalArchive
 	}
-	return isGlobalArchive
+	return d.Archive.Merge(*globalArchive)
 }
 
 func (d *Database) GetDocker(globalDocker *docker.Docker) docker.Docker {
@@ -0,0 +1,53 @@
+package discover
+
+import (
+	"regexp"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+type Discover struct {
+	Enabled bool     `yaml:"enabled" json:"enabled,omitempty"`
+	Include []string `yaml:"include" json:"include,omitempty"`
+	Exclude []string `yaml:"exclude" json:"exclude,omitempty"`
+}
+
+// UnmarshalYAML accepts `discover: true` as a shorthand for a discovery
+// without filters.
+func (d *Discover) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		enabled, err := strconv.ParseBool(node.Value)
+		if err != nil {
+			return err
+		}
+		*d = Discover{Enabled: enabled}
+		return nil
+	}
+
+	type plain Discover
+	return node.Decode((*plain)(d))
+}
+
+// Match reports whether a discovered database is backed up: it has to match
+// one of the include patterns, when there are any, and none of the exclude
+// patterns. Invalid patterns are rejected by validation beforehand.
+func (d *Discover) Match(name string) bool {
+	for _, pattern := range d.Exclude {
+		if ok, _ := regexp.MatchString(pattern, name); ok {
+			return false
+		}
+	}
+
+	if len(d.Include) == 0 {
+		return true
+	}
+
+	for _, pattern := range d.Include {
+		if ok, _ := regexp.MatchString(pattern, name); ok {
+			return true
+		}
+	}
+
+	return false
+}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"dumper/internal/domain/config/database"
+	"dumper/internal/domain/config/notification"
 	"dumper/internal/domain/config/server"
 	"dumper/internal/domain/config/setting"
 	"dumper/internal/domain/config/storage"
@@ -12,4 +13,6 @@ type Config struct {
 	Databases map[string]database.Database `yaml:"databases" validate:"required" json:"databases,omitempty"`
 	Servers   map[string]server.Server     `yaml:"servers" validate:"required" json:"servers,omitempty"`
 	Storages  map[string]storage.Storage   `yaml:"storages" validate:"required" json:"storages,omitempty"`
+
+	Notifications map[string]notification.Notification `yaml:"notifications" json:"notifications,omitempty"`
 }
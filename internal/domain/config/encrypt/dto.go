@@ -1,7 +1,32 @@
 package encrypt
 
+const (
+	TypeAES = "aes"
+	TypeAge = "age"
+)
+
 type Encrypt struct {
-	Type     string `yaml:"type"`
-	Password string `yaml:"password"`
-	Enabled  *bool  `yaml:"enabled" default:"true"`
+	Type       string   `yaml:"type"`
+	Password   string   `yaml:"password"`
+	KDF        string   `yaml:"kdf"`
+	Recipients []string `yaml:"recipients"`
+	Identity   string   `yaml:"identity"`
+	Enabled    *bool    `yaml:"enabled" default:"true"`
+}
+
+// IsEnabled reports whether dumps have to be encrypted before they reach the
+// storages.
+func (e Encrypt) IsEnabled() bool {
+	if e.Enabled == nil || !*e.Enabled {
+		return false
+	}
+
+	switch e.Type {
+	case TypeAge:
+		return len(e.Recipients) > 0
+	case "":
+		return false
+	default:
+		return e.Password != ""
+	}
 }
@@ -0,0 +1,6 @@
+package metrics
+
+type Metrics struct {
+	Listen   string `yaml:"listen"`
+	Textfile string `yaml:"textfile"`
+}
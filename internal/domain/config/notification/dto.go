@@ -0,0 +1,48 @@
+package notification
+
+import "slices"
+
+const (
+	OnSuccess = "success"
+	OnFailure = "failure"
+)
+
+type Notification struct {
+	// Common
+	Type string   `yaml:"type" validate:"required"`
+	On   []string `yaml:"on"`
+
+	// Webhook / Slack / Mattermost, Telegram API base url
+	URL string `yaml:"url"`
+
+	// Telegram
+	Token  string `yaml:"token"`
+	ChatID string `yaml:"chat_id"`
+
+	// Email
+	Host     string   `yaml:"host"`
+	Port     string   `yaml:"port"`
+	Username string   `yaml:"username"`
+	Password string   `yaml:"password"`
+	From     string   `yaml:"from"`
+	To       []string `yaml:"to"`
+}
+
+// Accepts reports whether a backup result should be sent, an empty filter
+// sends both successes and failures.
+func (n Notification) Accepts(failed bool) bool {
+	if len(n.On) == 0 {
+		return true
+	}
+	if failed {
+		return slices.Contains(n.On, OnFailure)
+	}
+	return slices.Contains(n.On, OnSuccess)
+}
+
+func (n Notification) GetPort() string {
+	if n.Port != "" {
+		return n.Port
+	}
+	return "587"
+}
@@ -36,6 +36,28 @@ type Options struct {
 	FastAndStable bool `yaml:"fast_and_stable" default:"false"`
 	SkipGarbage   bool `yaml:"skip_garbage" default:"false"`
 
+	// PostgreSQL
+	IncSchemas   []string `yaml:"inc_schemas"`
+	ExcSchemas   []string `yaml:"exc_schemas"`
+	ExcTableData []string `yaml:"exc_table_data"`
+	Sections     []string `yaml:"sections"`
+	Jobs         int      `yaml:"jobs"` // also the threads of mydumper, xtrabackup and mariabackup
+	Globals      bool     `yaml:"globals" default:"false"`
+	WalDir       string   `yaml:"wal_dir"`
+	WalKeep      bool     `yaml:"wal_keep" default:"false"`
+	WalSchedule  string   `yaml:"wal_schedule"`
+	WalStorages  []string `yaml:"wal_storages"`
+	WalSegSize   int      `yaml:"wal_segment_size" default:"16"` // MB, like initdb --wal-segsize
+
+	// MySQL and MariaDB
+	SingleTransaction *bool  `yaml:"single_transaction" default:"true"`
+	Routines          bool   `yaml:"routines" default:"false"`
+	Triggers          *bool  `yaml:"triggers" default:"true"`
+	Events            bool   `yaml:"events" default:"false"`
+	SetGtidPurged     string `yaml:"set_gtid_purged"`
+	Incremental       bool   `yaml:"incremental" default:"false"`
+	LsnDir            string `yaml:"lsn_dir"`
+
 	// OpenSearch and ElasticSearch
 	CACertPath         string   `yaml:"ca_crt_path"`
 	KeyPath            string   `yaml:"key_path"`
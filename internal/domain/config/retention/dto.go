@@ -0,0 +1,55 @@
+package retention
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Retention struct {
+	KeepLast    int    `yaml:"keep_last" json:"keepLast,omitempty"`
+	KeepDaily   int    `yaml:"keep_daily" json:"keepDaily,omitempty"`
+	KeepWeekly  int    `yaml:"keep_weekly" json:"keepWeekly,omitempty"`
+	KeepMonthly int    `yaml:"keep_monthly" json:"keepMonthly,omitempty"`
+	MaxAge      string `yaml:"max_age" json:"maxAge,omitempty"`
+	DryRun      bool   `yaml:"dry_run" json:"dryRun,omitempty"`
+}
+
+func (r Retention) IsEnabled() bool {
+	return r.KeepLast > 0 || r.KeepDaily > 0 || r.KeepWeekly > 0 || r.KeepMonthly > 0 || r.MaxAge != ""
+}
+
+func (r Retention) HasKeepRules() bool {
+	return r.KeepLast > 0 || r.KeepDaily > 0 || r.KeepWeekly > 0 || r.KeepMonthly > 0
+}
+
+// GetMaxAge parses max_age, which accepts Go durations plus day ("30d") and
+// week ("4w") units.
+func (r Retention) GetMaxAge() (time.Duration, error) {
+	if r.MaxAge == "" {
+		return 0, nil
+	}
+
+	units := map[string]time.Duration{
+		"d": 24 * time.Hour,
+		"w": 7 * 24 * time.Hour,
+	}
+
+	for unit, size := range units {
+		if value, ok := strings.CutSuffix(r.MaxAge, unit); ok {
+			n, err := strconv.Atoi(value)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid max_age: %s", r.MaxAge)
+			}
+			return time.Duration(n) * size, nil
+		}
+	}
+
+	age, err := time.ParseDuration(r.MaxAge)
+	if err != nil || age <= 0 {
+		return 0, fmt.Errorf("invalid max_age: %s", r.MaxAge)
+	}
+
+	return age, nil
+}
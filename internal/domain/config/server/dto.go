@@ -1,18 +1,29 @@
 package server
 
-import "dumper/internal/domain/config/shell"
+import (
+	"dumper/internal/domain/config/shell"
+	"time"
+)
 
 type Server struct {
-	Title      string       `yaml:"title,omitempty"`
-	Host       string       `yaml:"host" validate:"required"`
-	User       string       `yaml:"user" validate:"required"`
-	Name       string       `yaml:"name,omitempty"`
-	Port       string       `yaml:"port,omitempty"`
-	PrivateKey string       `yaml:"private_key,omitempty" validate:"xor=Password"`
-	Passphrase string       `yaml:"passphrase,omitempty"`
-	Password   string       `yaml:"password,omitempty" validate:"xor=PrivateKey"`
-	ConfigPath string       `yaml:"conf_path,omitempty"`
-	Shell      *shell.Shell `yaml:"shell,omitempty"`
+	Title               string        `yaml:"title,omitempty"`
+	Host                string        `yaml:"host" validate:"required"`
+	User                string        `yaml:"user" validate:"required"`
+	Name                string        `yaml:"name,omitempty"`
+	Port                string        `yaml:"port,omitempty"`
+	PrivateKey          string        `yaml:"private_key,omitempty" validate:"xor=Password"`
+	Passphrase          string        `yaml:"passphrase,omitempty"`
+	Password            string        `yaml:"password,omitempty" validate:"xor=PrivateKey"`
+	ConfigPath          string        `yaml:"conf_path,omitempty"`
+	Shell               *shell.Shell  `yaml:"shell,omitempty"`
+	KnownHosts          string        `yaml:"known_hosts,omitempty"`
+	HostKeyChecking     string        `yaml:"host_key_checking,omitempty"`
+	HostKeyFingerprints []string      `yaml:"host_key_fingerprints,omitempty"`
+	Agent               *bool         `yaml:"agent,omitempty"`
+	Certificate         string        `yaml:"certificate,omitempty"`
+	JumpHosts           []JumpHost    `yaml:"jump_hosts,omitempty"`
+	SSHAlias            string        `yaml:"ssh_alias,omitempty"`
+	KeepAlive           time.Duration `yaml:"keepalive,omitempty"`
 }
 
 func (s *Server) GetName() string {
@@ -70,6 +81,27 @@ func (s *Server) GetPassword(password *string) string {
 	return *password
 }
 
+func (s *Server) GetKnownHosts(knownHosts *string) string {
+	if s.KnownHosts != "" {
+		return s.KnownHosts
+	}
+	return *knownHosts
+}
+
+func (s *Server) GetHostKeyChecking(checking *string) string {
+	if s.HostKeyChecking != "" {
+		return s.HostKeyChecking
+	}
+	return *checking
+}
+
+func (s *Server) GetAgent(agent *bool) bool {
+	if s.Agent != nil {
+		return *s.Agent
+	}
+	return agent != nil && *agent
+}
+
 func (s *Server) GetShell(globalShell *shell.Shell) shell.Shell {
 	if s.Shell == nil && globalShell == nil {
 		val := false
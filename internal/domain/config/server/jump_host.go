@@ -0,0 +1,61 @@
+package server
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// JumpHost is one bastion of a jump_hosts chain. It is written either as
+// "user@host:port", like ProxyJump, or as a mapping. Unset credentials are
+// taken from the server behind it.
+type JumpHost struct {
+	Host                string   `yaml:"host" validate:"required"`
+	Port                string   `yaml:"port,omitempty"`
+	User                string   `yaml:"user,omitempty"`
+	PrivateKey          string   `yaml:"private_key,omitempty"`
+	Passphrase          string   `yaml:"passphrase,omitempty"`
+	Password            string   `yaml:"password,omitempty"`
+	Certificate         string   `yaml:"certificate,omitempty"`
+	HostKeyFingerprints []string `yaml:"host_key_fingerprints,omitempty"`
+}
+
+func (j *JumpHost) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.ScalarNode {
+		type plain JumpHost
+		return node.Decode((*plain)(j))
+	}
+
+	parsed, err := ParseJumpHost(node.Value)
+	if err != nil {
+		return fmt.Errorf("line %d: %w", node.Line, err)
+	}
+
+	*j = parsed
+	return nil
+}
+
+// ParseJumpHost reads the [user@]host[:port] form.
+func ParseJumpHost(s string) (JumpHost, error) {
+	var j JumpHost
+
+	hostPort := strings.TrimSpace(s)
+	if idx := strings.LastIndex(hostPort, "@"); idx >= 0 {
+		j.User = hostPort[:idx]
+		hostPort = hostPort[idx+1:]
+	}
+
+	if host, port, err := net.SplitHostPort(hostPort); err == nil {
+		j.Host, j.Port = host, port
+	} else {
+		j.Host = strings.Trim(hostPort, "[]")
+	}
+
+	if j.Host == "" {
+		return JumpHost{}, fmt.Errorf("invalid jump host '%s'", s)
+	}
+
+	return j, nil
+}
@@ -1,8 +1,11 @@
 package setting
 
 import (
+	"dumper/internal/domain/config/archive"
 	"dumper/internal/domain/config/docker"
 	"dumper/internal/domain/config/encrypt"
+	"dumper/internal/domain/config/metrics"
+	"dumper/internal/domain/config/retention"
 	"dumper/internal/domain/config/shell"
 	sshConfig "dumper/internal/domain/config/ssh-config"
 )
@@ -11,7 +14,7 @@ type Settings struct {
 	SSH                 *sshConfig.SSHConfig `yaml:"ssh"`
 	DirRemote           string               `yaml:"dir_remote" default:"./"`
 	Template            string               `yaml:"template" default:"{%srv%}_{%db%}_{%time%}"`
-	Archive             *bool                `yaml:"archive" default:"true"`
+	Archive             *archive.Archive     `yaml:"archive"`
 	Driver              string               `yaml:"driver"`
 	DBPort              string               `yaml:"db_port"`
 	SrvPost             string               `yaml:"server_port,omitempty"`
@@ -27,4 +30,8 @@ type Settings struct {
 	MaxParallelDownload int                  `yaml:"parallel_download" default:"2"`
 	Docker              *docker.Docker       `yaml:"docker"`
 	Shell               *shell.Shell         `yaml:"shell"`
+	Retention           *retention.Retention `yaml:"retention"`
+	Catalog             string               `yaml:"catalog" default:"./catalog.jsonl"`
+	Schedule            string               `yaml:"schedule"`
+	Metrics             *metrics.Metrics     `yaml:"metrics"`
 }
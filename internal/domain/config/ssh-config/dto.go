@@ -1,8 +1,12 @@
 package ssh_config
 
 type SSHConfig struct {
-	PrivateKey   string `yaml:"private_key"`
-	Passphrase   string `yaml:"passphrase"`
-	IsPassphrase *bool  `yaml:"is_passphrase" default:"false"`
-	Password     string `yaml:"password"`
+	PrivateKey      string `yaml:"private_key"`
+	Passphrase      string `yaml:"passphrase"`
+	IsPassphrase    *bool  `yaml:"is_passphrase" default:"false"`
+	Password        string `yaml:"password"`
+	KnownHosts      string `yaml:"known_hosts" default:"~/.ssh/known_hosts"`
+	HostKeyChecking string `yaml:"host_key_checking" default:"accept-new"`
+	Agent           *bool  `yaml:"agent" default:"true"`
+	ConfigFile      string `yaml:"config_file"`
 }
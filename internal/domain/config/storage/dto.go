@@ -1,5 +1,10 @@
 package storage
 
+import (
+	"dumper/internal/domain/config/retention"
+	"dumper/internal/domain/config/server"
+)
+
 type Storage struct {
 	// Common
 	Type string `yaml:"type" validate:"required"`
@@ -14,8 +19,14 @@ type Storage struct {
 	Password string `yaml:"password"`
 
 	// SFTP only
-	PrivateKey string `yaml:"private_key"`
-	Passphrase string `yaml:"passphrase"`
+	PrivateKey          string            `yaml:"private_key"`
+	Passphrase          string            `yaml:"passphrase"`
+	KnownHosts          string            `yaml:"known_hosts"`
+	HostKeyChecking     string            `yaml:"host_key_checking"`
+	HostKeyFingerprints []string          `yaml:"host_key_fingerprints"`
+	Agent               *bool             `yaml:"agent"`
+	Certificate         string            `yaml:"certificate"`
+	JumpHosts           []server.JumpHost `yaml:"jump_hosts"`
 
 	// Azure Common
 	Endpoint  string `yaml:"endpoint"`
@@ -43,6 +54,9 @@ type Storage struct {
 	// Google Cloud
 	Credential     string `yaml:"credential"`
 	CredentialFile string `yaml:"credential_file"`
+
+	// Retention
+	Retention *retention.Retention `yaml:"retention"`
 }
 
 type ListStorages struct {
@@ -50,9 +64,22 @@ type ListStorages struct {
 	Configs Storage
 }
 
+func (s Storage) GetRetention(retentionDatabase retention.Retention) retention.Retention {
+	if s.Retention != nil {
+		return *s.Retention
+	}
+	return retentionDatabase
+}
+
 func (s Storage) GetPrivateKey(pathKey string) string {
 	if s.PrivateKey != "" {
 		return s.PrivateKey
 	}
 	return pathKey
 }
+
+// IsAgent reports whether SFTP may authenticate with ssh-agent, it does
+// unless agent: false is set.
+func (s Storage) IsAgent() bool {
+	return s.Agent == nil || *s.Agent
+}
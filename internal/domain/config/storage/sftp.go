@@ -5,6 +5,8 @@ type SFTP struct {
 	Host       string `yaml:"host" validate:"required"`
 	Port       string `yaml:"port" validate:"required"`
 	Username   string `yaml:"username" validate:"required"`
-	PrivateKey string `yaml:"private_key" validate:"required"`
+	PrivateKey string `yaml:"private_key" validate:"required_without_all=Password Agent"`
 	Passphrase string `yaml:"passphrase"`
+	Password   string `yaml:"password"`
+	Agent      bool   `yaml:"agent"`
 }
@@ -1,11 +1,27 @@
 package connect
 
+import "time"
+
+// Host key checking policies, like StrictHostKeyChecking of OpenSSH.
+const (
+	HostKeyStrict    = "strict"
+	HostKeyAcceptNew = "accept-new"
+	HostKeyOff       = "off"
+)
+
 type Connect struct {
-	Server       string
-	Username     string
-	Port         string
-	PrivateKey   string
-	Passphrase   string
-	IsPassphrase bool
-	Password     string
+	Server              string
+	Username            string
+	Port                string
+	PrivateKey          string
+	Passphrase          string
+	IsPassphrase        bool
+	Password            string
+	KnownHosts          string
+	HostKeyChecking     string
+	HostKeyFingerprints []string
+	Agent               bool
+	Certificate         string
+	JumpHosts           []Connect
+	KeepAlive           time.Duration
 }
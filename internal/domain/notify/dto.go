@@ -0,0 +1,64 @@
+package notify
+
+import (
+	"dumper/internal/domain/config/notification"
+	"dumper/pkg/utils/format"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type Config struct {
+	Target  notification.Notification
+	Message Message
+}
+
+type Message struct {
+	Status     string    `json:"status"`
+	Database   string    `json:"db"`
+	Server     string    `json:"server"`
+	DumpName   string    `json:"dump_name,omitempty"`
+	Size       int64     `json:"size"`
+	Duration   float64   `json:"duration_sec"`
+	Storages   []string  `json:"storages"`
+	Phase      string    `json:"phase,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+}
+
+func (m Message) Title() string {
+	return fmt.Sprintf("[dumper] Backup %s: %s on %s", m.Status, m.Database, m.Server)
+}
+
+func (m Message) Text() string {
+	var b strings.Builder
+
+	b.WriteString(m.Title())
+	b.WriteString("\n")
+	if m.DumpName != "" {
+		b.WriteString(fmt.Sprintf("Dump: %s\n", m.DumpName))
+	}
+	b.WriteString(fmt.Sprintf("Size: %s\n", format.FormatBytes(m.Size)))
+	b.WriteString(fmt.Sprintf("Duration: %.2f sec\n", m.Duration))
+	if len(m.Storages) > 0 {
+		b.WriteString(fmt.Sprintf("Storages: %s\n", strings.Join(m.Storages, ", ")))
+	}
+	if m.Phase != "" {
+		b.WriteString(fmt.Sprintf("Failed phase: %s\n", m.Phase))
+	}
+	if m.Error != "" {
+		b.WriteString(fmt.Sprintf("Error: %s\n", m.Error))
+	}
+
+	return b.String()
+}
+
+type SendError struct {
+	Backend string
+	Err     error
+}
+
+func (e *SendError) Error() string {
+	return fmt.Sprintf("[%s] notification failed: %v", e.Backend, e.Err)
+}
@@ -0,0 +1,21 @@
+package report
+
+import (
+	catalogDomain "dumper/internal/domain/catalog"
+	"time"
+)
+
+const (
+	FormatJSON  = "json"
+	FormatJUnit = "junit"
+)
+
+type Report struct {
+	Status     string                `json:"status"`
+	StartedAt  time.Time             `json:"started_at"`
+	FinishedAt time.Time             `json:"finished_at"`
+	Duration   float64               `json:"duration_sec"`
+	Total      int                   `json:"total"`
+	Failed     int                   `json:"failed"`
+	Databases  []catalogDomain.Entry `json:"databases"`
+}
@@ -2,22 +2,70 @@ package storage
 
 import (
 	"dumper/internal/connect"
+	"dumper/internal/domain/config/encrypt"
 	"dumper/internal/domain/config/storage"
 	"fmt"
+	"io"
+	"time"
 )
 
 type Config struct {
 	Type     string
 	DumpName string
+	Source   string
 	FileSize int64
+	Checksum string
+	Encrypt  encrypt.Encrypt
 	Conn     *connect.Connect
 	Config   storage.Storage
+	Reader   io.Reader
+	Retries  int
+
+	// StoredChecksum is the sha256 of the bytes handed to the storage, set
+	// once the stream has been read to the end.
+	StoredChecksum string
+}
+
+// GetSource returns the path the dump is read from on the server.
+func (c *Config) GetSource() string {
+	if c.Source != "" {
+		return c.Source
+	}
+	return c.DumpName
+}
+
+// defaultRetries bounds resumes of an interrupted transfer when no
+// retry_connect is configured.
+const defaultRetries = 3
+
+// GetRetries returns how often a dropped transfer is resumed without
+// progress before the upload fails.
+func (c *Config) GetRetries() int {
+	if c.Retries > 0 {
+		return c.Retries
+	}
+	return defaultRetries
 }
 
 type Uploader interface {
 	Save() error
 }
 
+type Loader interface {
+	Load() (io.ReadCloser, error)
+}
+
+type Lister interface {
+	List() ([]File, error)
+	Delete(name string) error
+}
+
+type File struct {
+	Name    string
+	Size    int64
+	ModTime time.Time
+}
+
 type UploadError struct {
 	Backend string
 	Err     error
@@ -26,3 +74,21 @@ type UploadError struct {
 func (e *UploadError) Error() string {
 	return fmt.Sprintf("[%s] upload failed: %v", e.Backend, e.Err)
 }
+
+type LoadError struct {
+	Backend string
+	Err     error
+}
+
+func (e *LoadError) Error() string {
+	return fmt.Sprintf("[%s] load failed: %v", e.Backend, e.Err)
+}
+
+type DeleteError struct {
+	Backend string
+	Err     error
+}
+
+func (e *DeleteError) Error() string {
+	return fmt.Sprintf("[%s] delete failed: %v", e.Backend, e.Err)
+}
@@ -0,0 +1,126 @@
+package metrics
+
+import (
+	"context"
+	"dumper/internal/catalog"
+	catalogDomain "dumper/internal/domain/catalog"
+	"dumper/pkg/logging"
+	"dumper/pkg/utils/metrics"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+)
+
+var registry = metrics.NewRegistry()
+
+var (
+	backupDuration = registry.Gauge(
+		"dumper_backup_duration_seconds",
+		"Duration of the last backup run.",
+		"db", "server",
+	)
+	backupRuns = registry.Counter(
+		"dumper_backup_runs_total",
+		"Backup runs by result.",
+		"db", "status",
+	)
+	backupFailures = registry.Counter(
+		"dumper_backup_failures_total",
+		"Failed backups by the phase they failed in.",
+		"db", "phase",
+	)
+	lastSuccess = registry.Gauge(
+		"dumper_backup_last_success_timestamp_seconds",
+		"Unix time of the last successful backup.",
+		"db",
+	)
+	dumpSize = registry.Gauge(
+		"dumper_dump_size_bytes",
+		"Size of the last dump.",
+		"db",
+	)
+	dumpDuration = registry.Gauge(
+		"dumper_dump_duration_seconds",
+		"Time spent creating the last dump.",
+		"db",
+	)
+	uploadDuration = registry.Gauge(
+		"dumper_upload_duration_seconds",
+		"Time spent uploading the last dump by storage type.",
+		"db", "storage_type",
+	)
+)
+
+func ObserveDump(db string, elapsed time.Duration) {
+	dumpDuration.Set(elapsed.Seconds(), db)
+}
+
+func ObserveUpload(db, storageType string, elapsed time.Duration) {
+	uploadDuration.Set(elapsed.Seconds(), db, storageType)
+}
+
+// ObserveBackup records the outcome of a finished backup run.
+func ObserveBackup(entry catalogDomain.Entry) {
+	if entry.Status == "" {
+		return
+	}
+
+	backupDuration.Set(entry.FinishedAt.Sub(entry.StartedAt).Seconds(), entry.Database, entry.Server)
+	backupRuns.Inc(entry.Database, entry.Status)
+
+	if entry.Status == catalog.StatusFailed {
+		backupFailures.Inc(entry.Database, entry.Phase)
+		return
+	}
+
+	dumpSize.Set(float64(entry.Size), entry.Database)
+	lastSuccess.Set(float64(entry.FinishedAt.Unix()), entry.Database)
+}
+
+func Handler() http.Handler {
+	return registry.Handler()
+}
+
+func WriteTextfile(path string) error {
+	return registry.WriteTextfile(path)
+}
+
+// ReadTextfile seeds the metrics with the textfile of an earlier run, so
+// writing it back keeps the other databases and their last success.
+func ReadTextfile(path string) error {
+	return registry.ReadTextfile(path)
+}
+
+// Serve exposes /metrics on addr until the context is cancelled.
+func Serve(ctx context.Context, addr string) error {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", Handler())
+
+	srv := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+
+	go func() {
+		if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logging.L(ctx).Error("Metrics server stopped", logging.ErrAttr(err))
+		}
+	}()
+
+	logging.L(ctx).Info("Metrics server started", logging.StringAttr("addr", ln.Addr().String()))
+
+	return nil
+}
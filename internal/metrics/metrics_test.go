@@ -0,0 +1,53 @@
+package metrics_test
+
+import (
+	catalogDomain "dumper/internal/domain/catalog"
+	"dumper/internal/metrics"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func scrape(t *testing.T) string {
+	rec := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body, _ := io.ReadAll(rec.Body)
+	return string(body)
+}
+
+func TestObserveBackup(t *testing.T) {
+	startedAt := time.Unix(1750000000, 0)
+
+	metrics.ObserveDump("app_db", 12*time.Second)
+	metrics.ObserveUpload("app_db", "s3", 3*time.Second)
+	metrics.ObserveBackup(catalogDomain.Entry{
+		Database:   "app_db",
+		Server:     "prod",
+		Size:       4096,
+		StartedAt:  startedAt,
+		FinishedAt: startedAt.Add(20 * time.Second),
+		Status:     "success",
+	})
+	metrics.ObserveBackup(catalogDomain.Entry{
+		Database:   "app_db",
+		Server:     "prod",
+		StartedAt:  startedAt,
+		FinishedAt: startedAt.Add(time.Second),
+		Status:     "failed",
+		Phase:      "connect",
+	})
+
+	body := scrape(t)
+
+	assert.Contains(t, body, `dumper_dump_duration_seconds{db="app_db"} 12`)
+	assert.Contains(t, body, `dumper_upload_duration_seconds{db="app_db",storage_type="s3"} 3`)
+	assert.Contains(t, body, `dumper_dump_size_bytes{db="app_db"} 4096`)
+	assert.Contains(t, body, `dumper_backup_last_success_timestamp_seconds{db="app_db"} 1750000020`)
+	assert.Contains(t, body, `dumper_backup_runs_total{db="app_db",status="success"} 1`)
+	assert.Contains(t, body, `dumper_backup_runs_total{db="app_db",status="failed"} 1`)
+	assert.Contains(t, body, `dumper_backup_failures_total{db="app_db",phase="connect"} 1`)
+	assert.Contains(t, body, `dumper_backup_duration_seconds{db="app_db",server="prod"} 1`)
+}
@@ -0,0 +1,118 @@
+package notify
+
+import (
+	"context"
+	"dumper/internal/catalog"
+	catalogDomain "dumper/internal/domain/catalog"
+	"dumper/internal/domain/config/notification"
+	notifyDomain "dumper/internal/domain/notify"
+	"dumper/internal/notify/type/email"
+	"dumper/internal/notify/type/slack"
+	"dumper/internal/notify/type/telegram"
+	"dumper/internal/notify/type/webhook"
+	"dumper/pkg/logging"
+	"errors"
+	"sort"
+)
+
+type Handler interface {
+	Send() error
+}
+
+type Notify struct {
+	ctx     context.Context
+	targets map[string]notification.Notification
+}
+
+func NewApp(
+	ctx context.Context,
+	targets map[string]notification.Notification,
+) *Notify {
+	return &Notify{
+		ctx:     ctx,
+		targets: targets,
+	}
+}
+
+// Send delivers the backup result to every target whose `on` filter accepts
+// it. Failures are logged and returned joined, they never fail a backup.
+func (n *Notify) Send(entry catalogDomain.Entry) error {
+	if len(n.targets) == 0 || entry.Status == "" {
+		return nil
+	}
+
+	message := NewMessage(entry)
+
+	names := make([]string, 0, len(n.targets))
+	for name := range n.targets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var errs []error
+	for _, name := range names {
+		target := n.targets[name]
+		if !target.Accepts(message.Status == catalog.StatusFailed) {
+			continue
+		}
+
+		config := notifyDomain.Config{
+			Target:  target,
+			Message: message,
+		}
+
+		handler, err := n.handler(&config)
+		if err == nil {
+			err = handler.Send()
+		}
+
+		if err != nil {
+			logging.L(n.ctx).Error(
+				"Failed to send notification",
+				logging.StringAttr("notification", name),
+				logging.ErrAttr(err),
+			)
+			errs = append(errs, err)
+			continue
+		}
+
+		logging.L(n.ctx).Info(
+			"Notification sent",
+			logging.StringAttr("notification", name),
+			logging.StringAttr("status", message.Status),
+		)
+	}
+
+	return errors.Join(errs...)
+}
+
+func NewMessage(entry catalogDomain.Entry) notifyDomain.Message {
+	return notifyDomain.Message{
+		Status:     entry.Status,
+		Database:   entry.Database,
+		Server:     entry.Server,
+		DumpName:   entry.DumpName,
+		Size:       entry.Size,
+		Duration:   entry.FinishedAt.Sub(entry.StartedAt).Seconds(),
+		Storages:   entry.Storages,
+		Phase:      entry.Phase,
+		Error:      entry.Error,
+		StartedAt:  entry.StartedAt,
+		FinishedAt: entry.FinishedAt,
+	}
+}
+
+func (n *Notify) handler(config *notifyDomain.Config) (Handler, error) {
+	switch config.Target.Type {
+	case "webhook":
+		return webhook.NewApp(n.ctx, config), nil
+	case "slack", "mattermost":
+		return slack.NewApp(n.ctx, config), nil
+	case "telegram":
+		return telegram.NewApp(n.ctx, config), nil
+	case "email":
+		return email.NewApp(n.ctx, config), nil
+	default:
+		return nil, errors.New("unsupported notification type: " + config.Target.Type)
+	}
+}
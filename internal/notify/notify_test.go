@@ -0,0 +1,270 @@
+package notify_test
+
+import (
+	"bufio"
+	"context"
+	catalogDomain "dumper/internal/domain/catalog"
+	"dumper/internal/domain/config/notification"
+	notifyDomain "dumper/internal/domain/notify"
+	"dumper/internal/notify"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var startedAt = time.Date(2025, 6, 30, 3, 0, 0, 0, time.UTC)
+
+func entry(status, errMsg string) catalogDomain.Entry {
+	return catalogDomain.Entry{
+		Database:   "app_db",
+		Server:     "prod",
+		DumpName:   "prod_app_db.sql.gz",
+		Size:       2048,
+		Storages:   []string{"local", "s3"},
+		StartedAt:  startedAt,
+		FinishedAt: startedAt.Add(90 * time.Second),
+		Status:     status,
+		Error:      errMsg,
+	}
+}
+
+type recorder struct {
+	mu     sync.Mutex
+	paths  []string
+	bodies [][]byte
+}
+
+func (r *recorder) server(t *testing.T, status int) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		r.mu.Lock()
+		r.paths = append(r.paths, req.URL.Path)
+		r.bodies = append(r.bodies, body)
+		r.mu.Unlock()
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestSend_Webhook(t *testing.T) {
+	rec := &recorder{}
+	srv := rec.server(t, http.StatusOK)
+
+	err := notify.NewApp(context.Background(), map[string]notification.Notification{
+		"hook": {Type: "webhook", URL: srv.URL + "/hook"},
+	}).Send(entry("failed", "pg_dump: connection refused"))
+	require.NoError(t, err)
+
+	require.Len(t, rec.bodies, 1)
+	var msg notifyDomain.Message
+	require.NoError(t, json.Unmarshal(rec.bodies[0], &msg))
+
+	assert.Equal(t, "failed", msg.Status)
+	assert.Equal(t, "app_db", msg.Database)
+	assert.Equal(t, "prod", msg.Server)
+	assert.Equal(t, int64(2048), msg.Size)
+	assert.Equal(t, 90.0, msg.Duration)
+	assert.Equal(t, []string{"local", "s3"}, msg.Storages)
+	assert.Equal(t, "pg_dump: connection refused", msg.Error)
+}
+
+func TestSend_SlackAndTelegram(t *testing.T) {
+	rec := &recorder{}
+	srv := rec.server(t, http.StatusOK)
+
+	err := notify.NewApp(context.Background(), map[string]notification.Notification{
+		"mm": {Type: "mattermost", URL: srv.URL + "/hooks/abc"},
+		"tg": {Type: "telegram", URL: srv.URL, Token: "123:secret", ChatID: "-100"},
+	}).Send(entry("success", ""))
+	require.NoError(t, err)
+
+	require.Len(t, rec.bodies, 2)
+	assert.Equal(t, []string{"/hooks/abc", "/bot123:secret/sendMessage"}, rec.paths)
+
+	var slack map[string]string
+	require.NoError(t, json.Unmarshal(rec.bodies[0], &slack))
+	assert.Contains(t, slack["text"], "Backup success: app_db on prod")
+	assert.Contains(t, slack["text"], "Storages: local, s3")
+
+	var telegram map[string]string
+	require.NoError(t, json.Unmarshal(rec.bodies[1], &telegram))
+	assert.Equal(t, "-100", telegram["chat_id"])
+	assert.Contains(t, telegram["text"], "Duration: 90.00 sec")
+}
+
+func TestSend_OnFilter(t *testing.T) {
+	rec := &recorder{}
+	srv := rec.server(t, http.StatusOK)
+
+	n := notify.NewApp(context.Background(), map[string]notification.Notification{
+		"hook": {Type: "webhook", URL: srv.URL, On: []string{"failure"}},
+	})
+
+	require.NoError(t, n.Send(entry("success", "")))
+	assert.Empty(t, rec.bodies)
+
+	require.NoError(t, n.Send(entry("failed", "boom")))
+	assert.Len(t, rec.bodies, 1)
+}
+
+func TestSend_ReportsErrors(t *testing.T) {
+	rec := &recorder{}
+	srv := rec.server(t, http.StatusInternalServerError)
+
+	err := notify.NewApp(context.Background(), map[string]notification.Notification{
+		"tg":   {Type: "telegram", URL: "http://127.0.0.1:1", Token: "123:secret", ChatID: "1"},
+		"hook": {Type: "webhook", URL: srv.URL},
+	}).Send(entry("failed", "boom"))
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "[Webhook] notification failed")
+	assert.Contains(t, err.Error(), "500")
+	assert.Contains(t, err.Error(), "[Telegram] notification failed")
+	assert.NotContains(t, err.Error(), "secret")
+}
+
+func TestSend_Email(t *testing.T) {
+	addr, received := fakeSMTP(t)
+	host, port, err := net.SplitHostPort(addr)
+	require.NoError(t, err)
+
+	err = notify.NewApp(context.Background(), map[string]notification.Notification{
+		"mail": {
+			Type: "email",
+			Host: host,
+			Port: port,
+			From: "dumper@example.com",
+			To:   []string{"ops@example.com", "dba@example.com"},
+		},
+	}).Send(entry("failed", "disk full"))
+	require.NoError(t, err)
+
+	select {
+	case msg := <-received:
+		assert.Equal(t, "dumper@example.com", msg.from)
+		assert.Equal(t, []string{"ops@example.com", "dba@example.com"}, msg.rcpt)
+		assert.Contains(t, msg.data, "Subject: [dumper] Backup failed: app_db on prod")
+		assert.Contains(t, msg.data, "Error: disk full")
+	case <-time.After(5 * time.Second):
+		t.Fatal("no message received")
+	}
+}
+
+func TestSend_EmailStalledServer(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = ln.Close() })
+
+	// accepts the connection and never greets
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		_, _ = io.Copy(io.Discard, conn)
+	}()
+
+	host, port, err := net.SplitHostPort(ln.Addr().String())
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- notify.NewApp(ctx, map[string]notification.Notification{
+			"mail": {Type: "email", Host: host, Port: port, From: "dumper@example.com", To: []string{"ops@example.com"}},
+		}).Send(entry("failed", "disk full"))
+	}()
+
+	select {
+	case err := <-done:
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "[Email] notification failed")
+	case <-time.After(5 * time.Second):
+		t.Fatal("email sender hung on a stalled server")
+	}
+}
+
+type smtpMessage struct {
+	from string
+	rcpt []string
+	data string
+}
+
+// fakeSMTP accepts a single plain SMTP session and reports what it received.
+func fakeSMTP(t *testing.T) (string, <-chan smtpMessage) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = ln.Close() })
+
+	received := make(chan smtpMessage, 1)
+
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		r := bufio.NewReader(conn)
+		reply := func(s string) { _, _ = conn.Write([]byte(s + "\r\n")) }
+
+		var msg smtpMessage
+		reply("220 localhost ESMTP")
+
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			cmd := strings.ToUpper(strings.TrimSpace(line))
+
+			switch {
+			case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+				reply("250-localhost")
+				reply("250 HELP")
+			case strings.HasPrefix(cmd, "MAIL FROM:"):
+				msg.from = strings.Trim(strings.TrimSpace(line)[10:], "<>")
+				reply("250 OK")
+			case strings.HasPrefix(cmd, "RCPT TO:"):
+				msg.rcpt = append(msg.rcpt, strings.Trim(strings.TrimSpace(line)[8:], "<>"))
+				reply("250 OK")
+			case cmd == "DATA":
+				reply("354 End data with <CR><LF>.<CR><LF>")
+				var data strings.Builder
+				for {
+					l, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if l == ".\r\n" {
+						break
+					}
+					data.WriteString(l)
+				}
+				msg.data = data.String()
+				reply("250 OK")
+			case cmd == "QUIT":
+				reply("221 Bye")
+				received <- msg
+				return
+			default:
+				reply("250 OK")
+			}
+		}
+	}()
+
+	return ln.Addr().String(), received
+}
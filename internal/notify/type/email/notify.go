@@ -0,0 +1,147 @@
+package email
+
+import (
+	"context"
+	"crypto/tls"
+	notifyDomain "dumper/internal/domain/notify"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// timeout bounds the dial and then the whole SMTP session, like the HTTP
+// client of the other senders, so a stalled server can't hold a run. An
+// earlier deadline of the context wins.
+const timeout = 15 * time.Second
+
+type Email struct {
+	ctx     context.Context
+	config  *notifyDomain.Config
+	backend string
+}
+
+func NewApp(
+	ctx context.Context,
+	config *notifyDomain.Config,
+) *Email {
+	return &Email{
+		ctx:     ctx,
+		config:  config,
+		backend: "Email",
+	}
+}
+
+func (e *Email) Send() error {
+	if err := e.send(); err != nil {
+		return &notifyDomain.SendError{
+			Backend: e.backend,
+			Err:     err,
+		}
+	}
+
+	return nil
+}
+
+func (e *Email) send() error {
+	target := e.config.Target
+	addr := net.JoinHostPort(target.Host, target.GetPort())
+
+	client, err := e.client(addr)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: target.Host}); err != nil {
+			return fmt.Errorf("failed to start TLS: %v", err)
+		}
+	}
+
+	if target.Username != "" {
+		auth := smtp.PlainAuth("", target.Username, target.Password, target.Host)
+		if err := client.Auth(auth); err != nil {
+			return fmt.Errorf("failed to authenticate: %v", err)
+		}
+	}
+
+	if err := client.Mail(target.From); err != nil {
+		return fmt.Errorf("failed to set sender: %v", err)
+	}
+
+	for _, rcpt := range target.To {
+		if err := client.Rcpt(rcpt); err != nil {
+			return fmt.Errorf("failed to add recipient %s: %v", rcpt, err)
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("failed to start message: %v", err)
+	}
+
+	if _, err := w.Write(e.message()); err != nil {
+		return fmt.Errorf("failed to write message: %v", err)
+	}
+
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("failed to send message: %v", err)
+	}
+
+	return client.Quit()
+}
+
+// client dials the server, port 465 uses implicit TLS while other ports
+// are upgraded with STARTTLS when the server offers it.
+func (e *Email) client(addr string) (*smtp.Client, error) {
+	target := e.config.Target
+	dialer := &net.Dialer{Timeout: timeout}
+
+	var conn net.Conn
+	var err error
+
+	if target.GetPort() == "465" {
+		tlsDialer := &tls.Dialer{NetDialer: dialer, Config: &tls.Config{ServerName: target.Host}}
+		conn, err = tlsDialer.DialContext(e.ctx, "tcp", addr)
+	} else {
+		conn, err = dialer.DialContext(e.ctx, "tcp", addr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %v", addr, err)
+	}
+
+	deadline := time.Now().Add(timeout)
+	if ctxDeadline, ok := e.ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+
+	if err := conn.SetDeadline(deadline); err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("failed to set SMTP deadline: %v", err)
+	}
+
+	client, err := smtp.NewClient(conn, target.Host)
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("failed to create SMTP client: %v", err)
+	}
+
+	return client, nil
+}
+
+func (e *Email) message() []byte {
+	var b strings.Builder
+
+	b.WriteString(fmt.Sprintf("From: %s\r\n", e.config.Target.From))
+	b.WriteString(fmt.Sprintf("To: %s\r\n", strings.Join(e.config.Target.To, ", ")))
+	b.WriteString(fmt.Sprintf("Subject: %s\r\n", e.config.Message.Title()))
+	b.WriteString(fmt.Sprintf("Date: %s\r\n", time.Now().Format(time.RFC1123Z)))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(e.config.Message.Text(), "\n", "\r\n"))
+
+	return []byte(b.String())
+}
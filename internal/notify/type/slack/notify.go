@@ -0,0 +1,42 @@
+package slack
+
+import (
+	"context"
+	notifyDomain "dumper/internal/domain/notify"
+	"dumper/internal/notify/client/http"
+)
+
+// Slack posts to an incoming webhook, Mattermost accepts the same payload.
+type Slack struct {
+	ctx     context.Context
+	config  *notifyDomain.Config
+	backend string
+}
+
+type payload struct {
+	Text string `json:"text"`
+}
+
+func NewApp(
+	ctx context.Context,
+	config *notifyDomain.Config,
+) *Slack {
+	return &Slack{
+		ctx:     ctx,
+		config:  config,
+		backend: "Slack",
+	}
+}
+
+func (s *Slack) Send() error {
+	body := payload{Text: s.config.Message.Text()}
+
+	if err := http.PostJSON(s.ctx, s.config.Target.URL, body); err != nil {
+		return &notifyDomain.SendError{
+			Backend: s.backend,
+			Err:     err,
+		}
+	}
+
+	return nil
+}
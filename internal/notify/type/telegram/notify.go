@@ -0,0 +1,56 @@
+package telegram
+
+import (
+	"context"
+	notifyDomain "dumper/internal/domain/notify"
+	"dumper/internal/notify/client/http"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const apiURL = "https://api.telegram.org"
+
+type Telegram struct {
+	ctx     context.Context
+	config  *notifyDomain.Config
+	backend string
+}
+
+type payload struct {
+	ChatID string `json:"chat_id"`
+	Text   string `json:"text"`
+}
+
+func NewApp(
+	ctx context.Context,
+	config *notifyDomain.Config,
+) *Telegram {
+	return &Telegram{
+		ctx:     ctx,
+		config:  config,
+		backend: "Telegram",
+	}
+}
+
+func (t *Telegram) Send() error {
+	base := apiURL
+	if t.config.Target.URL != "" {
+		base = strings.TrimSuffix(t.config.Target.URL, "/")
+	}
+
+	url := fmt.Sprintf("%s/bot%s/sendMessage", base, t.config.Target.Token)
+	body := payload{
+		ChatID: t.config.Target.ChatID,
+		Text:   t.config.Message.Text(),
+	}
+
+	if err := http.PostJSON(t.ctx, url, body); err != nil {
+		return &notifyDomain.SendError{
+			Backend: t.backend,
+			Err:     errors.New(strings.ReplaceAll(err.Error(), t.config.Target.Token, "********")),
+		}
+	}
+
+	return nil
+}
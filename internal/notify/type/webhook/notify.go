@@ -0,0 +1,35 @@
+package webhook
+
+import (
+	"context"
+	notifyDomain "dumper/internal/domain/notify"
+	"dumper/internal/notify/client/http"
+)
+
+type Webhook struct {
+	ctx     context.Context
+	config  *notifyDomain.Config
+	backend string
+}
+
+func NewApp(
+	ctx context.Context,
+	config *notifyDomain.Config,
+) *Webhook {
+	return &Webhook{
+		ctx:     ctx,
+		config:  config,
+		backend: "Webhook",
+	}
+}
+
+func (w *Webhook) Send() error {
+	if err := http.PostJSON(w.ctx, w.config.Target.URL, w.config.Message); err != nil {
+		return &notifyDomain.SendError{
+			Backend: w.backend,
+			Err:     err,
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,194 @@
+package report
+
+import (
+	"dumper/internal/catalog"
+	catalogDomain "dumper/internal/domain/catalog"
+	reportDomain "dumper/internal/domain/report"
+	"dumper/pkg/utils/format"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+func IsFormat(name string) bool {
+	return name == reportDomain.FormatJSON || name == reportDomain.FormatJUnit
+}
+
+// New summarizes the results of one run, every database counts once.
+func New(startedAt time.Time, entries []catalogDomain.Entry) reportDomain.Report {
+	finishedAt := time.Now()
+
+	entries = append([]catalogDomain.Entry(nil), entries...)
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Database < entries[j].Database
+	})
+
+	r := reportDomain.Report{
+		Status:     catalog.StatusSuccess,
+		StartedAt:  startedAt,
+		FinishedAt: finishedAt,
+		Duration:   finishedAt.Sub(startedAt).Seconds(),
+		Total:      len(entries),
+		Databases:  entries,
+	}
+
+	for _, entry := range entries {
+		if entry.Status != catalog.StatusSuccess {
+			r.Failed++
+		}
+	}
+
+	if r.Failed > 0 {
+		r.Status = catalog.StatusFailed
+	}
+
+	return r
+}
+
+// Write renders the report to path, or to stdout when path is empty.
+func Write(r reportDomain.Report, reportFormat, path string) error {
+	if path == "" {
+		return Encode(os.Stdout, r, reportFormat)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create report directory: %v", err)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create report file: %v", err)
+	}
+	defer file.Close()
+
+	return Encode(file, r, reportFormat)
+}
+
+func Encode(w io.Writer, r reportDomain.Report, reportFormat string) error {
+	switch reportFormat {
+	case reportDomain.FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(r)
+	case reportDomain.FormatJUnit:
+		return encodeJUnit(w, r)
+	default:
+		return fmt.Errorf("unsupported report format: %s", reportFormat)
+	}
+}
+
+type junitSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Name     string       `xml:"name,attr"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Time     string       `xml:"time,attr"`
+	Suites   []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name      string      `xml:"name,attr"`
+	Tests     int         `xml:"tests,attr"`
+	Failures  int         `xml:"failures,attr"`
+	Time      string      `xml:"time,attr"`
+	Timestamp string      `xml:"timestamp,attr"`
+	Cases     []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	ClassName string        `xml:"classname,attr"`
+	Name      string        `xml:"name,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+func encodeJUnit(w io.Writer, r reportDomain.Report) error {
+	suite := junitSuite{
+		Name:      "dumper",
+		Tests:     r.Total,
+		Failures:  r.Failed,
+		Time:      seconds(r.Duration),
+		Timestamp: r.StartedAt.Format(time.RFC3339),
+	}
+
+	for _, entry := range r.Databases {
+		tc := junitCase{
+			ClassName: "dumper." + entry.Server,
+			Name:      entry.Database,
+			Time:      seconds(entry.FinishedAt.Sub(entry.StartedAt).Seconds()),
+			SystemOut: details(entry),
+		}
+
+		if entry.Status != catalog.StatusSuccess {
+			tc.Failure = &junitFailure{
+				Message: entry.Error,
+				Type:    entry.Phase,
+				Text:    entry.Error,
+			}
+		}
+
+		suite.Cases = append(suite.Cases, tc)
+	}
+
+	suites := junitSuites{
+		Name:     "dumper",
+		Tests:    r.Total,
+		Failures: r.Failed,
+		Time:     seconds(r.Duration),
+		Suites:   []junitSuite{suite},
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(suites); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func details(entry catalogDomain.Entry) string {
+	var b strings.Builder
+
+	if entry.Path != "" {
+		b.WriteString(fmt.Sprintf("Dump: %s\n", entry.Path))
+	}
+	b.WriteString(fmt.Sprintf("Size: %s [%d bytes]\n", format.FormatBytes(entry.Size), entry.Size))
+	if entry.Sha256 != "" {
+		b.WriteString(fmt.Sprintf("SHA256: %s\n", entry.Sha256))
+	}
+	for _, upload := range entry.Uploads {
+		b.WriteString(fmt.Sprintf("Storage %s (%s): %s in %.2f sec", upload.Storage, upload.Type, upload.Status, upload.Duration))
+		if upload.Error != "" {
+			b.WriteString(": " + upload.Error)
+		}
+		b.WriteString("\n")
+	}
+	for _, warning := range entry.Warnings {
+		b.WriteString(fmt.Sprintf("Warning: %s\n", warning))
+	}
+
+	return b.String()
+}
+
+func seconds(value float64) string {
+	return fmt.Sprintf("%.3f", value)
+}
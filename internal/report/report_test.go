@@ -0,0 +1,136 @@
+package report_test
+
+import (
+	"bytes"
+	catalogDomain "dumper/internal/domain/catalog"
+	reportDomain "dumper/internal/domain/report"
+	"dumper/internal/report"
+	"encoding/json"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var startedAt = time.Date(2025, 6, 30, 3, 0, 0, 0, time.UTC)
+
+func entries() []catalogDomain.Entry {
+	return []catalogDomain.Entry{
+		{
+			Database:   "orders",
+			Server:     "prod",
+			StartedAt:  startedAt,
+			FinishedAt: startedAt.Add(2 * time.Second),
+			Status:     "failed",
+			Phase:      "dump",
+			Error:      "pg_dump: permission denied",
+		},
+		{
+			Database:   "billing",
+			Server:     "prod",
+			DumpName:   "prod_billing.sql.gz",
+			Path:       "/root/dump/prod_billing.sql.gz",
+			Size:       4096,
+			Sha256:     "abc123",
+			Storages:   []string{"local"},
+			StartedAt:  startedAt,
+			FinishedAt: startedAt.Add(5 * time.Second),
+			Status:     "success",
+			Uploads: []catalogDomain.Upload{
+				{Storage: "local", Type: "local", Status: "success", Duration: 1.5},
+				{Storage: "s3", Type: "s3", Status: "failed", Duration: 0.5, Error: "access denied"},
+			},
+			Warnings: []string{"retention for storage s3: list failed"},
+		},
+	}
+}
+
+func TestNew(t *testing.T) {
+	r := report.New(startedAt, entries())
+
+	assert.Equal(t, "failed", r.Status)
+	assert.Equal(t, 2, r.Total)
+	assert.Equal(t, 1, r.Failed)
+	assert.Equal(t, "billing", r.Databases[0].Database)
+	assert.Equal(t, "orders", r.Databases[1].Database)
+
+	r = report.New(startedAt, entries()[1:])
+	assert.Equal(t, "success", r.Status)
+	assert.Equal(t, 0, r.Failed)
+}
+
+func TestEncode_JSON(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, report.Encode(&buf, report.New(startedAt, entries()), reportDomain.FormatJSON))
+
+	var decoded reportDomain.Report
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+
+	require.Len(t, decoded.Databases, 2)
+	assert.Equal(t, "dump", decoded.Databases[1].Phase)
+	assert.Equal(t, "pg_dump: permission denied", decoded.Databases[1].Error)
+	assert.Equal(t, "abc123", decoded.Databases[0].Sha256)
+	assert.Equal(t, "access denied", decoded.Databases[0].Uploads[1].Error)
+	assert.Equal(t, []string{"retention for storage s3: list failed"}, decoded.Databases[0].Warnings)
+}
+
+func TestEncode_JUnit(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, report.Encode(&buf, report.New(startedAt, entries()), reportDomain.FormatJUnit))
+
+	var suites struct {
+		Tests    int `xml:"tests,attr"`
+		Failures int `xml:"failures,attr"`
+		Suites   []struct {
+			Cases []struct {
+				Name      string `xml:"name,attr"`
+				ClassName string `xml:"classname,attr"`
+				Time      string `xml:"time,attr"`
+				Failure   *struct {
+					Message string `xml:"message,attr"`
+					Type    string `xml:"type,attr"`
+				} `xml:"failure"`
+				SystemOut string `xml:"system-out"`
+			} `xml:"testcase"`
+		} `xml:"testsuite"`
+	}
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &suites))
+
+	assert.Equal(t, 2, suites.Tests)
+	assert.Equal(t, 1, suites.Failures)
+	require.Len(t, suites.Suites, 1)
+	require.Len(t, suites.Suites[0].Cases, 2)
+
+	billing := suites.Suites[0].Cases[0]
+	assert.Equal(t, "billing", billing.Name)
+	assert.Equal(t, "dumper.prod", billing.ClassName)
+	assert.Equal(t, "5.000", billing.Time)
+	assert.Nil(t, billing.Failure)
+	assert.Contains(t, billing.SystemOut, "SHA256: abc123")
+	assert.Contains(t, billing.SystemOut, "Storage s3 (s3): failed in 0.50 sec: access denied")
+	assert.Contains(t, billing.SystemOut, "Warning: retention for storage s3: list failed")
+
+	orders := suites.Suites[0].Cases[1]
+	require.NotNil(t, orders.Failure)
+	assert.Equal(t, "dump", orders.Failure.Type)
+	assert.Equal(t, "pg_dump: permission denied", orders.Failure.Message)
+}
+
+func TestWrite_File(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "reports", "run.json")
+
+	require.NoError(t, report.Write(report.New(startedAt, entries()), reportDomain.FormatJSON, path))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"databases"`)
+}
+
+func TestEncode_UnknownFormat(t *testing.T) {
+	assert.False(t, report.IsFormat("yaml"))
+	assert.Error(t, report.Encode(&bytes.Buffer{}, report.New(startedAt, nil), "yaml"))
+}
@@ -0,0 +1,323 @@
+package restore
+
+import (
+	"bufio"
+	"context"
+	command "dumper/internal/command/database"
+	"dumper/internal/connect"
+	connecterror "dumper/internal/connect/connect-error"
+	cryptBackup "dumper/internal/crypt/crypt-backup"
+	commandConfig "dumper/internal/domain/command-config"
+	"dumper/internal/domain/config"
+	dbConnect "dumper/internal/domain/config/db-connect"
+	configStorage "dumper/internal/domain/config/storage"
+	storageDomain "dumper/internal/domain/storage"
+	"dumper/internal/storage"
+	"dumper/pkg/logging"
+	"dumper/pkg/utils/compress"
+	"dumper/pkg/utils/format"
+	"dumper/pkg/utils/mapping"
+	"dumper/pkg/utils/runner"
+	"dumper/pkg/utils/spiner"
+	"dumper/pkg/utils/stream"
+	"dumper/pkg/utils/suffix"
+	"dumper/pkg/utils/template"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+type Restore struct {
+	ctx         context.Context
+	cfg         *config.Config
+	conn        *connect.Connect
+	dbConnect   dbConnect.DBConnect
+	storageName string
+	fileName    string
+	password    string
+	identity    string
+	cmdConfig   *commandConfig.Config
+}
+
+func NewApp(
+	ctx context.Context,
+	cfg *config.Config,
+	dbConnect dbConnect.DBConnect,
+	conn *connect.Connect,
+	storageName string,
+	fileName string,
+	password string,
+	identity string,
+) *Restore {
+	return &Restore{
+		ctx:         ctx,
+		cfg:         cfg,
+		dbConnect:   dbConnect,
+		conn:        conn,
+		storageName: storageName,
+		fileName:    fileName,
+		password:    password,
+		identity:    identity,
+	}
+}
+
+func (r *Restore) Run() error {
+	storageCfg, ok := r.cfg.Storages[r.storageName]
+	if !ok {
+		return fmt.Errorf("storage '%s' not found in configuration", r.storageName)
+	}
+	storageCfg.PrivateKey = storageCfg.GetPrivateKey(r.cfg.Settings.SSH.PrivateKey)
+
+	r.prepareRestoreConfig()
+
+	if r.cfg.Settings.DumpLocation != "local-direct" {
+		logging.L(r.ctx).Info("Prepare connection")
+
+		if err := runner.RunWithCtx(r.ctx, r.conn.Connect); err != nil {
+			logging.L(r.ctx).Error(
+				"Error connecting to server",
+				logging.StringAttr("server", r.dbConnect.Server.Host),
+				logging.ErrAttr(err),
+			)
+			return &connecterror.ConnectError{
+				Addr: r.dbConnect.Server.Host,
+				Err:  err,
+			}
+		}
+		defer r.conn.Close()
+
+		logging.L(r.ctx).Info("The connection is established")
+	}
+
+	tmpDir, err := os.MkdirTemp("", "dumper-restore-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	localPath, err := r.fetch(storageCfg, tmpDir)
+	if err != nil {
+		return err
+	}
+
+	if strings.HasSuffix(localPath, ".enc") {
+		if localPath, err = r.decrypt(localPath); err != nil {
+			return err
+		}
+	}
+
+	file, err := os.Open(localPath)
+	if err != nil {
+		return fmt.Errorf("failed to open backup file: %v", err)
+	}
+	defer file.Close()
+
+	reader, dumpName, err := decompress(file, filepath.Base(localPath))
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	r.cmdConfig.DumpName = template.GetFullPath(r.cmdConfig.DumpDirRemote, dumpName)
+
+	logging.L(r.ctx).Info("Prepare command for restore")
+
+	cmdDB, err := command.NewApp(r.ctx, r.cmdConfig).GetRestoreCommand()
+	if err != nil {
+		logging.L(r.ctx).Error("failed generate restore command")
+		return fmt.Errorf("failed generate restore command: %w", err)
+	}
+
+	return runner.RunWithCtx(r.ctx, func() error {
+		return r.restore(cmdDB.Command, cmdDB.DumpPath, cmdDB.Env, reader)
+	})
+}
+
+func (r *Restore) fetch(storageCfg configStorage.Storage, dir string) (string, error) {
+	logging.L(r.ctx).Info(
+		"Downloading backup",
+		logging.StringAttr("storage", r.storageName),
+		logging.StringAttr("file", r.fileName),
+	)
+	fmt.Printf("Downloading backup %s from %s\n", r.fileName, r.storageName)
+
+	storageConfig := storageDomain.Config{
+		Type:     storageCfg.Type,
+		DumpName: r.fileName,
+		Config:   storageCfg,
+	}
+
+	src, err := storage.NewApp(r.ctx, &storageConfig).Load()
+	if err != nil {
+		logging.L(r.ctx).Error("Failed to download backup", logging.ErrAttr(err))
+		return "", err
+	}
+	defer src.Close()
+
+	localPath := filepath.Join(dir, filepath.Base(r.fileName))
+	dst, err := os.Create(localPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to create local file: %v", err)
+	}
+	defer dst.Close()
+
+	downloadTimeNow := time.Now()
+	pr := stream.PipeReader(r.ctx, src, storageConfig.FileSize)
+
+	size, err := io.Copy(dst, pr)
+	if err != nil {
+		logging.L(r.ctx).Error("Failed to download backup", logging.ErrAttr(err))
+		return "", fmt.Errorf("failed to download backup %s: %v", r.fileName, err)
+	}
+
+	fmt.Printf("\rBackup downloaded in %.2f sec\n", time.Since(downloadTimeNow).Seconds())
+	fmt.Printf("\rFile size: %s [%d bytes]\n", format.FormatBytes(size), size)
+
+	return localPath, nil
+}
+
+func (r *Restore) decrypt(localPath string) (string, error) {
+	encrypt := r.dbConnect.Database.GetEncrypt(r.cfg.Settings.Encrypt)
+
+	password := r.password
+	if password == "" {
+		password = encrypt.Password
+	}
+
+	identity := r.identity
+	if identity == "" {
+		identity = encrypt.Identity
+	}
+
+	logging.L(r.ctx).Info("Decrypting backup", logging.StringAttr("file", localPath))
+
+	cryptBackupApp := cryptBackup.NewApp(localPath, password, identity, "aes", "decrypt")
+	if err := cryptBackupApp.Decrypt(); err != nil {
+		logging.L(r.ctx).Error("Failed to decrypt backup", logging.ErrAttr(err))
+		return "", err
+	}
+
+	return suffix.RemoveSuffix(localPath, ".enc"), nil
+}
+
+func (r *Restore) restore(cmd, dumpPath string, env map[string]string, reader io.Reader) error {
+	stop := make(chan struct{})
+	restoreTimeNow := time.Now()
+
+	logging.L(r.ctx).Info("Restoring database", logging.StringAttr("database", r.cmdConfig.Database.Name))
+	fmt.Println("Restoring database:", r.cmdConfig.Database.Name)
+
+	go spiner.Spinner(stop)
+	defer close(stop)
+
+	if dumpPath != "" {
+		if msg, err := r.run(fmt.Sprintf("mkdir -p %s && cat > %s", r.cmdConfig.DumpDirRemote, dumpPath), nil, reader); err != nil {
+			logging.L(r.ctx).Error(
+				"Failed to upload backup",
+				logging.StringAttr("msg", msg),
+				logging.ErrAttr(err),
+			)
+			return fmt.Errorf("failed to upload backup: %v", err)
+		}
+
+		defer func() {
+			if msg, err := r.run(fmt.Sprintf("rm -f %s", dumpPath), nil, nil); err != nil {
+				logging.L(r.ctx).Warn(
+					"Failed to remove uploaded backup",
+					logging.StringAttr("file", dumpPath),
+					logging.StringAttr("msg", msg),
+					logging.ErrAttr(err),
+				)
+			}
+		}()
+
+		reader = nil
+	}
+
+	if msg, err := r.run(cmd, env, reader); err != nil {
+		logging.L(r.ctx).Error(
+			"Failed to restore database",
+			logging.StringAttr("msg", msg),
+			logging.ErrAttr(err),
+		)
+		return fmt.Errorf("failed to restore database: %v", err)
+	}
+
+	elapsed := time.Since(restoreTimeNow)
+	fmt.Printf("\rDatabase restored successfully in %.2f sec\n", elapsed.Seconds())
+	logging.L(r.ctx).Info(
+		"The database was successfully restored",
+		logging.StringAttr("time", fmt.Sprintf("%.2f sec", elapsed.Seconds())),
+	)
+
+	return nil
+}
+
+func (r *Restore) run(cmd string, env map[string]string, stdin io.Reader) (string, error) {
+	if r.cfg.Settings.DumpLocation == "local-direct" {
+		return runner.RunLocalCommandWithInput(r.ctx, r.cmdConfig.DumpDirRemote, cmd, env, stdin)
+	}
+
+	return r.conn.RunCommandWithInput(cmd, env, stdin)
+}
+
+func (r *Restore) prepareRestoreConfig() {
+	logging.L(r.ctx).Info("Prepare restore config")
+
+	dirRemote := r.dbConnect.Database.GetDirRemote(&r.cfg.Settings.DirRemote)
+	dbHost := r.dbConnect.Database.Host
+	dbFormat := r.dbConnect.Database.GetFormat(&r.cfg.Settings.DumpFormat)
+	dbDriver := r.dbConnect.Database.GetDriver(&r.cfg.Settings.Driver)
+
+	if r.cfg.Settings.DumpLocation == "local-direct" {
+		dirRemote = os.TempDir()
+		dbHost = r.dbConnect.Database.GetHost(r.dbConnect.Server.Host)
+	}
+
+	options := r.dbConnect.Database.GetOptions()
+	options.Source = mapping.GetRestoreSource(dbDriver, dbFormat)
+
+	r.cmdConfig = &commandConfig.Config{
+		Database: commandConfig.Database{
+			User:     r.dbConnect.Database.User,
+			Password: r.dbConnect.Database.Password,
+			Name:     r.dbConnect.Database.GetName(),
+			Host:     dbHost,
+			Port:     r.dbConnect.Database.GetPort(&r.cfg.Settings.DBPort),
+			Format:   dbFormat,
+			Driver:   dbDriver,
+			Options:  options,
+			Docker:   r.dbConnect.Database.GetDocker(r.cfg.Settings.Docker),
+			Token:    r.dbConnect.Database.Token,
+		},
+		Server: commandConfig.Server{
+			Host: r.dbConnect.Server.Host,
+			Port: r.dbConnect.Server.Port,
+			Key:  r.dbConnect.Server.GetPrivateKey(&r.cfg.Settings.SSH.PrivateKey),
+		},
+		DumpLocation:  r.cfg.Settings.DumpLocation,
+		DumpDirRemote: dirRemote,
+		DumpDirLocal:  r.cfg.Settings.DirDump,
+	}
+}
+
+// decompress unwraps whole-file compression so restore tools get the raw
+// dump, the returned name drops the compression suffix accordingly.
+func decompress(src io.Reader, name string) (io.ReadCloser, string, error) {
+	buffered := bufio.NewReader(src)
+
+	algo := compress.Detect(buffered)
+	if algo == "" {
+		return io.NopCloser(buffered), name, nil
+	}
+
+	reader, err := compress.NewReader(buffered, algo)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to decompress backup: %v", err)
+	}
+
+	return reader, compress.TrimExt(name, algo), nil
+}
@@ -0,0 +1,76 @@
+package restore
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func gzipData(t *testing.T, data string) []byte {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	_, err := gz.Write([]byte(data))
+	require.NoError(t, err)
+	require.NoError(t, gz.Close())
+	return buf.Bytes()
+}
+
+func TestDecompress(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []byte
+		fileName string
+		wantName string
+	}{
+		{"gzip sql", gzipData(t, "SELECT 1;"), "db.sql.gz", "db.sql"},
+		{"gzip tarball", gzipData(t, "SELECT 1;"), "db.tgz", "db.tar"},
+		{"plain sql", []byte("SELECT 1;"), "db.sql", "db.sql"},
+		{"gz name without gzip data", []byte("SELECT 1;"), "db.gz", "db.gz"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader, name, err := decompress(bytes.NewReader(tt.input), tt.fileName)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantName, name)
+
+			out, err := io.ReadAll(reader)
+			require.NoError(t, err)
+			assert.Equal(t, "SELECT 1;", string(out))
+		})
+	}
+}
+
+func TestDecompress_Empty(t *testing.T) {
+	reader, name, err := decompress(strings.NewReader(""), "empty.sql")
+	require.NoError(t, err)
+	assert.Equal(t, "empty.sql", name)
+
+	out, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Empty(t, out)
+}
+
+func TestDecompress_Zstd(t *testing.T) {
+	if _, err := exec.LookPath("zstd"); err != nil {
+		t.Skip("zstd is not installed")
+	}
+
+	packed, err := exec.Command("sh", "-c", "printf 'SELECT 1;' | zstd -q -c").Output()
+	require.NoError(t, err)
+
+	reader, name, err := decompress(bytes.NewReader(packed), "db.tar.zst")
+	require.NoError(t, err)
+	defer reader.Close()
+	assert.Equal(t, "db.tar", name)
+
+	out, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT 1;", string(out))
+}
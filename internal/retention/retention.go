@@ -0,0 +1,253 @@
+package retention
+
+import (
+	"context"
+	"dumper/internal/catalog"
+	backupDomain "dumper/internal/domain/backup"
+	commandConfig "dumper/internal/domain/command-config"
+	retentionConfig "dumper/internal/domain/config/retention"
+	configStorage "dumper/internal/domain/config/storage"
+	storageDomain "dumper/internal/domain/storage"
+	"dumper/internal/storage"
+	"dumper/pkg/logging"
+	"dumper/pkg/utils/checksum"
+	"dumper/pkg/utils/format"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+type Retention struct {
+	ctx     context.Context
+	config  *commandConfig.Config
+	pattern *regexp.Regexp
+}
+
+func NewApp(
+	ctx context.Context,
+	config *commandConfig.Config,
+	pattern *regexp.Regexp,
+) *Retention {
+	return &Retention{
+		ctx:     ctx,
+		config:  config,
+		pattern: pattern,
+	}
+}
+
+func (r *Retention) Run() error {
+	now := time.Now()
+	var errs []error
+
+	for name, storageItem := range r.config.Storages {
+		rule := storageItem.GetRetention(r.config.Retention)
+		if !rule.IsEnabled() {
+			continue
+		}
+
+		if err := r.apply(name, storageItem, rule, now); err != nil {
+			logging.L(r.ctx).Warn(
+				"Failed to apply retention",
+				logging.StringAttr("storage", name),
+				logging.ErrAttr(err),
+			)
+			errs = append(errs, fmt.Errorf("retention for storage %s: %w", name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (r *Retention) apply(
+	name string,
+	storageItem configStorage.Storage,
+	rule retentionConfig.Retention,
+	now time.Time,
+) error {
+	storageApp := storage.NewApp(r.ctx, &storageDomain.Config{
+		Type:   storageItem.Type,
+		Config: storageItem,
+	})
+
+	list, err := storageApp.List()
+	if err != nil {
+		return err
+	}
+
+	var files []storageDomain.File
+	sidecars := make(map[string]bool)
+	companions := make(map[string][]string)
+	for _, file := range list {
+		if !r.pattern.MatchString(file.Name) {
+			continue
+		}
+		if strings.HasSuffix(file.Name, checksum.Suffix) {
+			sidecars[file.Name] = true
+			continue
+		}
+		if stem, ok := r.companionOf(file.Name); ok {
+			companions[stem] = append(companions[stem], file.Name)
+			continue
+		}
+		files = append(files, file)
+	}
+
+	expired, err := Expired(files, rule, now)
+	if err != nil {
+		return err
+	}
+
+	logging.L(r.ctx).Info(
+		"Retention applied",
+		logging.StringAttr("storage", name),
+		logging.IntAttr("backups", len(files)),
+		logging.IntAttr("expired", len(expired)),
+	)
+
+	for _, file := range expired {
+		if rule.DryRun {
+			fmt.Printf("[dry-run] [%s] Would delete %s (%s, %s)\n",
+				name, file.Name, format.FormatBytes(file.Size), file.ModTime.Format(time.DateTime))
+			continue
+		}
+
+		for _, member := range append([]string{file.Name}, companions[r.stem(file.Name)]...) {
+			if err := storageApp.Delete(member); err != nil {
+				return err
+			}
+
+			if sidecar := checksum.SidecarName(member); sidecars[sidecar] {
+				if err := storageApp.Delete(sidecar); err != nil {
+					return err
+				}
+			}
+		}
+
+		logging.L(r.ctx).Info(
+			"Old backup deleted",
+			logging.StringAttr("storage", name),
+			logging.StringAttr("file", file.Name),
+		)
+		fmt.Printf("[%s] Deleted old backup %s\n", name, file.Name)
+
+		r.forget(name, file.Name)
+	}
+
+	return nil
+}
+
+// forget drops a deleted backup from the catalog. Like recording a backup, a
+// catalog failure is logged and does not fail the run.
+func (r *Retention) forget(storageName, fileName string) {
+	if r.config.Catalog == "" {
+		return
+	}
+
+	if err := catalog.New(r.config.Catalog).Forget(storageName, fileName); err != nil {
+		logging.L(r.ctx).Warn(
+			"Failed to update backup catalog",
+			logging.StringAttr("file", fileName),
+			logging.ErrAttr(err),
+		)
+	}
+}
+
+// stem is the name the template produced, without the extensions.
+func (r *Retention) stem(name string) string {
+	loc := r.pattern.FindStringSubmatchIndex(name)
+	if len(loc) < 4 || loc[2] < 0 {
+		return name
+	}
+	return name[:loc[2]]
+}
+
+// companionOf reports whether name is a companion dump, returning the stem
+// it shares with its dump.
+func (r *Retention) companionOf(name string) (string, bool) {
+	stem := r.stem(name)
+	for _, ext := range backupDomain.CompanionExts {
+		if strings.HasPrefix(name[len(stem):], "."+ext) {
+			return stem, true
+		}
+	}
+	return "", false
+}
+
+// Expired returns the files that fall outside the retention rule. The newest
+// file is always kept, keep_* rules are combined and max_age is a hard limit
+// applied on top of them.
+func Expired(
+	files []storageDomain.File,
+	rule retentionConfig.Retention,
+	now time.Time,
+) ([]storageDomain.File, error) {
+	maxAge, err := rule.GetMaxAge()
+	if err != nil {
+		return nil, err
+	}
+
+	sorted := make([]storageDomain.File, len(files))
+	copy(sorted, files)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].ModTime.After(sorted[j].ModTime)
+	})
+
+	keep := make(map[int]bool, len(sorted))
+
+	for i := 0; i < len(sorted) && i < rule.KeepLast; i++ {
+		keep[i] = true
+	}
+
+	keepPeriods(sorted, keep, rule.KeepDaily, func(t time.Time) string {
+		return t.Format("2006-01-02")
+	})
+	keepPeriods(sorted, keep, rule.KeepWeekly, func(t time.Time) string {
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("%d-%02d", year, week)
+	})
+	keepPeriods(sorted, keep, rule.KeepMonthly, func(t time.Time) string {
+		return t.Format("2006-01")
+	})
+
+	var expired []storageDomain.File
+
+	for i, file := range sorted {
+		if i == 0 {
+			continue
+		}
+
+		tooOld := maxAge > 0 && now.Sub(file.ModTime) > maxAge
+		if tooOld || (rule.HasKeepRules() && !keep[i]) {
+			expired = append(expired, file)
+		}
+	}
+
+	return expired, nil
+}
+
+// keepPeriods keeps the newest file of each of the latest n periods.
+func keepPeriods(
+	sorted []storageDomain.File,
+	keep map[int]bool,
+	n int,
+	period func(time.Time) string,
+) {
+	seen := make(map[string]struct{}, n)
+
+	for i, file := range sorted {
+		if len(seen) >= n {
+			return
+		}
+
+		key := period(file.ModTime.Local())
+		if _, ok := seen[key]; ok {
+			continue
+		}
+
+		seen[key] = struct{}{}
+		keep[i] = true
+	}
+}
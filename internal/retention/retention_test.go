@@ -0,0 +1,192 @@
+package retention_test
+
+import (
+	"context"
+	"dumper/internal/catalog"
+	catalogDomain "dumper/internal/domain/catalog"
+	commandConfig "dumper/internal/domain/command-config"
+	retentionConfig "dumper/internal/domain/config/retention"
+	configStorage "dumper/internal/domain/config/storage"
+	storageDomain "dumper/internal/domain/storage"
+	"dumper/internal/retention"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var now = time.Date(2025, 6, 30, 12, 0, 0, 0, time.Local)
+
+// dailyFiles builds one backup per day going back from now, newest first.
+func dailyFiles(days int) []storageDomain.File {
+	files := make([]storageDomain.File, 0, days)
+	for i := 0; i < days; i++ {
+		modTime := now.AddDate(0, 0, -i)
+		files = append(files, storageDomain.File{
+			Name:    "srv_db_" + modTime.Format("2006.01.02"),
+			ModTime: modTime,
+		})
+	}
+	return files
+}
+
+func names(files []storageDomain.File) []string {
+	out := make([]string, 0, len(files))
+	for _, f := range files {
+		out = append(out, f.Name)
+	}
+	return out
+}
+
+func TestExpired_KeepLast(t *testing.T) {
+	files := dailyFiles(5)
+
+	expired, err := retention.Expired(files, retentionConfig.Retention{KeepLast: 3}, now)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"srv_db_2025.06.27", "srv_db_2025.06.26"}, names(expired))
+}
+
+func TestExpired_MaxAge(t *testing.T) {
+	files := dailyFiles(10)
+
+	expired, err := retention.Expired(files, retentionConfig.Retention{MaxAge: "7d"}, now)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"srv_db_2025.06.22", "srv_db_2025.06.21"}, names(expired))
+}
+
+func TestExpired_GFS(t *testing.T) {
+	files := dailyFiles(70)
+	rule := retentionConfig.Retention{KeepDaily: 7, KeepWeekly: 4, KeepMonthly: 3}
+
+	expired, err := retention.Expired(files, rule, now)
+	require.NoError(t, err)
+
+	kept := len(files) - len(expired)
+	assert.LessOrEqual(t, kept, 7+4+3)
+	assert.GreaterOrEqual(t, kept, 7)
+
+	expiredNames := names(expired)
+	for _, f := range files[:7] {
+		assert.NotContains(t, expiredNames, f.Name)
+	}
+	// newest backup of each of the last three months is kept
+	for _, name := range []string{"srv_db_2025.06.30", "srv_db_2025.05.31", "srv_db_2025.04.30"} {
+		assert.NotContains(t, expiredNames, name)
+	}
+}
+
+func TestExpired_NewestIsAlwaysKept(t *testing.T) {
+	files := []storageDomain.File{
+		{Name: "srv_db_old", ModTime: now.AddDate(-1, 0, 0)},
+	}
+
+	expired, err := retention.Expired(files, retentionConfig.Retention{MaxAge: "24h"}, now)
+
+	require.NoError(t, err)
+	assert.Empty(t, expired)
+}
+
+func TestExpired_InvalidMaxAge(t *testing.T) {
+	_, err := retention.Expired(dailyFiles(2), retentionConfig.Retention{MaxAge: "soon"}, now)
+
+	require.Error(t, err)
+}
+
+func TestRun_DeletesChecksumSidecars(t *testing.T) {
+	dir := t.TempDir()
+
+	for i, name := range []string{"srv_db_1.sql", "srv_db_2.sql"} {
+		modTime := now.AddDate(0, 0, i-1)
+		for _, file := range []string{name, name + ".sha256"} {
+			path := filepath.Join(dir, file)
+			require.NoError(t, os.WriteFile(path, []byte(file), 0600))
+			require.NoError(t, os.Chtimes(path, modTime, modTime))
+		}
+	}
+
+	catalogPath := filepath.Join(t.TempDir(), "catalog.jsonl")
+	store := catalog.New(catalogPath)
+	require.NoError(t, store.Append(catalogDomain.Entry{
+		ID:       "old",
+		DumpName: "srv_db_1.sql",
+		Storages: []string{"local"},
+		Status:   catalog.StatusSuccess,
+	}))
+
+	config := &commandConfig.Config{
+		Storages: map[string]configStorage.Storage{
+			"local": {Type: "local", Dir: dir},
+		},
+		Retention: retentionConfig.Retention{KeepLast: 1},
+		Catalog:   catalogPath,
+	}
+
+	err := retention.NewApp(context.Background(), config, regexp.MustCompile(`^srv_db_\d(\..+)?$`)).Run()
+	require.NoError(t, err)
+
+	entry, err := store.Get("old")
+	require.NoError(t, err)
+	assert.Equal(t, catalog.StatusDeleted, entry.Status)
+	assert.Empty(t, entry.Storages)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+
+	var left []string
+	for _, entry := range entries {
+		left = append(left, entry.Name())
+	}
+	assert.ElementsMatch(t, []string{"srv_db_2.sql", "srv_db_2.sql.sha256"}, left)
+}
+
+func TestRun_DeletesCompanions(t *testing.T) {
+	dir := t.TempDir()
+
+	files := map[string]int{
+		"srv_db_2025.06.01.dump":                  -2,
+		"srv_db_2025.06.01.globals.sql.gz":        -2,
+		"srv_db_2025.06.01.globals.sql.gz.sha256": -2,
+		"srv_db_2025.06.02.dump":                  -1,
+		"srv_db_2025.06.02.globals.sql.gz":        -1,
+		"srv_db_2025.06.03.dump":                  0,
+		"srv_db_2025.06.03.globals.sql.gz":        0,
+		"srv_db_2025.06.03.globals.sql.gz.sha256": 0,
+	}
+	for name, days := range files {
+		modTime := now.AddDate(0, 0, days)
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(name), 0600))
+		require.NoError(t, os.Chtimes(path, modTime, modTime))
+	}
+
+	config := &commandConfig.Config{
+		Storages: map[string]configStorage.Storage{
+			"local": {Type: "local", Dir: dir},
+		},
+		Retention: retentionConfig.Retention{KeepLast: 2},
+	}
+
+	err := retention.NewApp(context.Background(), config, regexp.MustCompile(`^srv_db_\d{4}\.\d{2}\.\d{2}(\..+)?$`)).Run()
+	require.NoError(t, err)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+
+	var left []string
+	for _, entry := range entries {
+		left = append(left, entry.Name())
+	}
+	assert.ElementsMatch(t, []string{
+		"srv_db_2025.06.02.dump",
+		"srv_db_2025.06.02.globals.sql.gz",
+		"srv_db_2025.06.03.dump",
+		"srv_db_2025.06.03.globals.sql.gz",
+		"srv_db_2025.06.03.globals.sql.gz.sha256",
+	}, left)
+}
@@ -5,6 +5,7 @@ import (
 	"dumper/internal/connect"
 	commandConfig "dumper/internal/domain/command-config"
 	"dumper/pkg/logging"
+	"dumper/pkg/utils/runner"
 	"fmt"
 )
 
@@ -58,7 +59,14 @@ func (s *Shell) runScript(
 	script string,
 ) error {
 	scriptLog := fmt.Sprintf("Run shell script '%s' backup", shellType)
-	msg, err := s.conn.RunCommand(script)
+	var msg string
+	var err error
+
+	if s.conn == nil {
+		msg, err = runner.RunLocalCommand(s.ctx, s.config.DumpDirLocal, script)
+	} else {
+		msg, err = s.conn.RunCommand(script)
+	}
 	if err != nil {
 		logging.L(s.ctx).Error(
 			scriptLog,
@@ -2,13 +2,14 @@ package aws
 
 import (
 	"context"
-	"dumper/internal/connect"
 	domainConfigStorage "dumper/internal/domain/config/storage"
 	"dumper/internal/domain/storage"
 	"dumper/pkg/utils/console"
 	"dumper/pkg/utils/stream"
 
 	"fmt"
+	"io"
+	"path"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
@@ -18,12 +19,10 @@ import (
 )
 
 type Client struct {
-	ctx      context.Context
-	Connect  *connect.Connect
-	Storage  domainConfigStorage.Storage
-	DumpName string
-	FileSize int64
-	Backend  string
+	ctx     context.Context
+	Config  *storage.Config
+	Storage domainConfigStorage.Storage
+	Backend string
 }
 
 type DefaultAWS struct {
@@ -65,23 +64,141 @@ var defaultAWS = map[string]DefaultAWS{
 
 func NewClient(
 	ctx context.Context,
-	connect *connect.Connect,
-	storage domainConfigStorage.Storage,
-	dumpName string,
-	fileSize int64,
+	config *storage.Config,
 	backend string,
 ) *Client {
 	return &Client{
-		ctx:      ctx,
-		Connect:  connect,
-		Storage:  storage,
-		DumpName: dumpName,
-		FileSize: fileSize,
-		Backend:  backend,
+		ctx:     ctx,
+		Config:  config,
+		Storage: config.Config,
+		Backend: backend,
 	}
 }
 
 func (a *Client) Handler() error {
+	s3Client, err := a.client()
+	if err != nil {
+		return err
+	}
+
+	providerName := a.providerName()
+	uploader := manager.NewUploader(s3Client)
+
+	pr, closeSSH, err := stream.Open(a.ctx, a.Config)
+
+	if err != nil {
+		return &storage.UploadError{
+			Backend: a.Backend,
+			Err:     fmt.Errorf("failed to create SSH session: %v", err),
+		}
+	}
+
+	defer closeSSH()
+
+	targetPath := stream.TargetPath(a.Storage.Dir, a.Config.DumpName)
+
+	// a failed read aborts the multipart upload, so a broken stream never
+	// becomes an object
+	_, err = uploader.Upload(a.ctx, &s3.PutObjectInput{
+		Bucket: aws.String(a.Storage.Bucket),
+		Key:    aws.String(targetPath),
+		Body:   pr,
+	})
+
+	if err != nil {
+		return &storage.UploadError{Backend: a.Backend, Err: err}
+	}
+
+	console.SafePrintln("[%s] Upload complete: %s", providerName, targetPath)
+	return nil
+}
+
+func (a *Client) Load() (io.ReadCloser, error) {
+	s3Client, err := a.client()
+	if err != nil {
+		return nil, err
+	}
+
+	targetPath := stream.TargetPath(a.Storage.Dir, a.Config.DumpName)
+
+	out, err := s3Client.GetObject(a.ctx, &s3.GetObjectInput{
+		Bucket: aws.String(a.Storage.Bucket),
+		Key:    aws.String(targetPath),
+	})
+	if err != nil {
+		return nil, &storage.LoadError{
+			Backend: a.Backend,
+			Err:     fmt.Errorf("[%s] failed to get object %s: %w", a.providerName(), targetPath, err),
+		}
+	}
+
+	if out.ContentLength != nil {
+		a.Config.FileSize = *out.ContentLength
+	}
+
+	return out.Body, nil
+}
+
+func (a *Client) List() ([]storage.File, error) {
+	s3Client, err := a.client()
+	if err != nil {
+		return nil, err
+	}
+
+	var files []storage.File
+	paginator := s3.NewListObjectsV2Paginator(s3Client, &s3.ListObjectsV2Input{
+		Bucket:    aws.String(a.Storage.Bucket),
+		Prefix:    aws.String(stream.ListPrefix(a.Storage.Dir)),
+		Delimiter: aws.String("/"),
+	})
+
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(a.ctx)
+		if err != nil {
+			return nil, &storage.LoadError{
+				Backend: a.Backend,
+				Err:     fmt.Errorf("[%s] failed to list objects: %w", a.providerName(), err),
+			}
+		}
+
+		for _, object := range page.Contents {
+			file := storage.File{Name: path.Base(aws.ToString(object.Key))}
+			if object.Size != nil {
+				file.Size = *object.Size
+			}
+			if object.LastModified != nil {
+				file.ModTime = *object.LastModified
+			}
+			files = append(files, file)
+		}
+	}
+
+	return files, nil
+}
+
+func (a *Client) Delete(name string) error {
+	s3Client, err := a.client()
+	if err != nil {
+		return err
+	}
+
+	targetPath := stream.TargetPath(a.Storage.Dir, name)
+
+	_, err = s3Client.DeleteObject(a.ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(a.Storage.Bucket),
+		Key:    aws.String(targetPath),
+	})
+	if err != nil {
+		return &storage.DeleteError{
+			Backend: a.Backend,
+			Err:     fmt.Errorf("[%s] failed to delete object %s: %w", a.providerName(), targetPath, err),
+		}
+	}
+
+	return nil
+}
+
+func (a *Client) client() (*s3.Client, error) {
 	cred := aws.NewCredentialsCache(
 		credentials.NewStaticCredentialsProvider(
 			a.Storage.AccessKey,
@@ -98,7 +215,7 @@ func (a *Client) Handler() error {
 
 	providerName := a.providerName()
 	if err != nil {
-		return fmt.Errorf("failed to load %s config: %v", providerName, err)
+		return nil, fmt.Errorf("failed to load %s config: %v", providerName, err)
 	}
 
 	var opts []func(*s3.Options)
@@ -120,39 +237,13 @@ func (a *Client) Handler() error {
 		Bucket: aws.String(a.Storage.Bucket),
 	})
 	if err != nil {
-		return &storage.UploadError{
+		return nil, &storage.UploadError{
 			Backend: a.Backend,
 			Err:     fmt.Errorf("[%s] bucket %s is not accessible: %w", providerName, a.Storage.Bucket, err),
 		}
 	}
 
-	uploader := manager.NewUploader(s3Client)
-
-	pr, closeSSH, err := stream.SSHStreamer(a.ctx, a.Connect, a.DumpName, a.FileSize)
-
-	if err != nil {
-		return &storage.UploadError{
-			Backend: a.Backend,
-			Err:     fmt.Errorf("failed to create SSH session: %v", err),
-		}
-	}
-
-	defer closeSSH()
-
-	targetPath := stream.TargetPath(a.Storage.Dir, a.DumpName)
-
-	_, err = uploader.Upload(a.ctx, &s3.PutObjectInput{
-		Bucket: aws.String(a.Storage.Bucket),
-		Key:    aws.String(targetPath),
-		Body:   pr,
-	})
-
-	if err != nil {
-		return &storage.UploadError{Backend: a.Backend, Err: err}
-	}
-
-	console.SafePrintln("[%s] Upload complete: %s", providerName, targetPath)
-	return nil
+	return s3Client, nil
 }
 
 func (a *Client) endpoint() *string {
@@ -15,10 +15,14 @@ import (
 	"dumper/internal/storage/type/sftp"
 	"dumper/internal/storage/type/yandex"
 	"errors"
+	"io"
 )
 
 type StorageHandler interface {
 	Save() error
+	Load() (io.ReadCloser, error)
+	List() ([]storage.File, error)
+	Delete(name string) error
 }
 
 type Storage struct {
@@ -37,34 +41,66 @@ func NewApp(
 }
 
 func (s *Storage) Save() error {
-	var handler StorageHandler
+	handler, err := s.handler()
+	if err != nil {
+		return err
+	}
+
+	return handler.Save()
+}
+
+func (s *Storage) Load() (io.ReadCloser, error) {
+	handler, err := s.handler()
+	if err != nil {
+		return nil, err
+	}
+
+	return handler.Load()
+}
+
+func (s *Storage) List() ([]storage.File, error) {
+	handler, err := s.handler()
+	if err != nil {
+		return nil, err
+	}
+
+	return handler.List()
+}
+
+func (s *Storage) Delete(name string) error {
+	handler, err := s.handler()
+	if err != nil {
+		return err
+	}
 
+	return handler.Delete(name)
+}
+
+func (s *Storage) handler() (StorageHandler, error) {
 	switch s.config.Type {
 	case "local":
-		handler = local.NewApp(s.ctx, s.config)
+		return local.NewApp(s.ctx, s.config), nil
 	case "sftp":
-		handler = sftp.NewApp(s.ctx, s.config)
+		return sftp.NewApp(s.ctx, s.config), nil
 	case "ftp":
-		handler = ftp.NewApp(s.ctx, s.config)
+		return ftp.NewApp(s.ctx, s.config), nil
 	case "azure":
-		handler = azure.NewApp(s.ctx, s.config)
+		return azure.NewApp(s.ctx, s.config), nil
 	case "s3":
-		handler = s3.NewApp(s.ctx, s.config)
+		return s3.NewApp(s.ctx, s.config), nil
 	case "minio":
-		handler = minio.NewApp(s.ctx, s.config)
+		return minio.NewApp(s.ctx, s.config), nil
 	case "r2":
-		handler = cloudflare.NewApp(s.ctx, s.config)
+		return cloudflare.NewApp(s.ctx, s.config), nil
 	case "b2":
-		handler = backblaze.NewApp(s.ctx, s.config)
+		return backblaze.NewApp(s.ctx, s.config), nil
 	case "spaces":
-		handler = digitalOcean.NewApp(s.ctx, s.config)
+		return digitalOcean.NewApp(s.ctx, s.config), nil
 	case "gcs":
-		handler = google.NewApp(s.ctx, s.config)
+		return google.NewApp(s.ctx, s.config), nil
 	case "yandex":
-		handler = yandex.NewApp(s.ctx, s.config)
+		return yandex.NewApp(s.ctx, s.config), nil
 	default:
-		return errors.New("unsupported storage type: " + s.config.Type)
+		return nil, errors.New("unsupported storage type: " + s.config.Type)
 	}
-
-	return handler.Save()
 }
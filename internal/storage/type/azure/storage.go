@@ -5,6 +5,7 @@ import (
 	"dumper/internal/domain/storage"
 	"dumper/pkg/utils/stream"
 	"fmt"
+	"io"
 	"path/filepath"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
@@ -49,35 +50,101 @@ func (a *Azure) Save() error {
 	containerClient := a.client.ServiceClient().NewContainerClient(containerName)
 	blobClient := containerClient.NewBlockBlobClient(targetPath)
 
-	session, err := a.config.Conn.NewSession()
+	pr, closeSSH, err := stream.Open(a.ctx, a.config)
 	if err != nil {
 		return fmt.Errorf("failed to create SSH session: %v", err)
 	}
-	defer session.Close()
 
-	stdout, err := session.StdoutPipe()
+	// the block list is only committed once the stream ended cleanly, a
+	// failed read leaves uncommitted blocks that Azure discards
+	_, err = blobClient.UploadStream(a.ctx, pr, &azblob.UploadStreamOptions{
+		BlockSize: 32 * 1024,
+	})
 	if err != nil {
-		return fmt.Errorf("failed to get stdout pipe: %v", err)
+		_ = closeSSH()
+		return fmt.Errorf("failed to upload to azure: %v", err)
 	}
 
-	if err := session.Start(fmt.Sprintf("cat %s", a.config.DumpName)); err != nil {
-		return fmt.Errorf("failed to start remote command: %v", err)
+	if err := closeSSH(); err != nil {
+		return err
 	}
 
-	pr := stream.PipeReader(a.ctx, stdout, a.config.FileSize)
+	fmt.Println("\n[Azure] Upload complete:", targetPath)
+	return nil
+}
 
-	_, err = blobClient.UploadStream(a.ctx, pr, &azblob.UploadStreamOptions{
-		BlockSize: 32 * 1024,
-	})
+func (a *Azure) Load() (io.ReadCloser, error) {
+	blobName := filepath.Base(a.config.DumpName)
+
+	if err := a.authType(); err != nil {
+		return nil, &storage.LoadError{Backend: "Azure", Err: err}
+	}
+
+	resp, err := a.client.DownloadStream(a.ctx, a.config.Config.Container, blobName, nil)
 	if err != nil {
-		return fmt.Errorf("failed to upload to azure: %v", err)
+		return nil, &storage.LoadError{
+			Backend: "Azure",
+			Err:     fmt.Errorf("failed to download from azure: %v", err),
+		}
 	}
 
-	if err := session.Wait(); err != nil {
-		return fmt.Errorf("remote command failed: %v", err)
+	if resp.ContentLength != nil {
+		a.config.FileSize = *resp.ContentLength
+	}
+
+	return resp.Body, nil
+}
+
+func (a *Azure) List() ([]storage.File, error) {
+	if err := a.authType(); err != nil {
+		return nil, &storage.LoadError{Backend: "Azure", Err: err}
+	}
+
+	var files []storage.File
+	pager := a.client.NewListBlobsFlatPager(a.config.Config.Container, nil)
+
+	for pager.More() {
+		page, err := pager.NextPage(a.ctx)
+		if err != nil {
+			return nil, &storage.LoadError{
+				Backend: "Azure",
+				Err:     fmt.Errorf("failed to list azure blobs: %v", err),
+			}
+		}
+
+		for _, item := range page.Segment.BlobItems {
+			if item.Name == nil {
+				continue
+			}
+
+			file := storage.File{Name: *item.Name}
+			if item.Properties != nil {
+				if item.Properties.ContentLength != nil {
+					file.Size = *item.Properties.ContentLength
+				}
+				if item.Properties.LastModified != nil {
+					file.ModTime = *item.Properties.LastModified
+				}
+			}
+			files = append(files, file)
+		}
+	}
+
+	return files, nil
+}
+
+func (a *Azure) Delete(name string) error {
+	if err := a.authType(); err != nil {
+		return &storage.DeleteError{Backend: "Azure", Err: err}
+	}
+
+	if _, err := a.client.DeleteBlob(a.ctx, a.config.Config.Container, filepath.Base(name), nil); err != nil {
+		return &storage.DeleteError{
+			Backend: "Azure",
+			Err:     fmt.Errorf("failed to delete azure blob: %v", err),
+		}
 	}
 
-	fmt.Println("\n[Azure] Upload complete:", targetPath)
 	return nil
 }
 
@@ -4,6 +4,7 @@ import (
 	"context"
 	"dumper/internal/domain/storage"
 	"dumper/internal/storage/client/aws"
+	"io"
 )
 
 type Backblaze struct {
@@ -26,12 +27,39 @@ func NewApp(
 func (b *Backblaze) Save() error {
 	awsClient := aws.NewClient(
 		b.ctx,
-		b.config.Conn,
-		b.config.Config,
-		b.config.DumpName,
-		b.config.FileSize,
+		b.config,
 		b.backend,
 	)
 
 	return awsClient.Handler()
 }
+
+func (b *Backblaze) Load() (io.ReadCloser, error) {
+	awsClient := aws.NewClient(
+		b.ctx,
+		b.config,
+		b.backend,
+	)
+
+	return awsClient.Load()
+}
+
+func (b *Backblaze) List() ([]storage.File, error) {
+	awsClient := aws.NewClient(
+		b.ctx,
+		b.config,
+		b.backend,
+	)
+
+	return awsClient.List()
+}
+
+func (b *Backblaze) Delete(name string) error {
+	awsClient := aws.NewClient(
+		b.ctx,
+		b.config,
+		b.backend,
+	)
+
+	return awsClient.Delete(name)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"dumper/internal/domain/storage"
 	"dumper/internal/storage/client/aws"
+	"io"
 )
 
 type Cloudflare struct {
@@ -27,12 +28,39 @@ func (c *Cloudflare) Save() error {
 	c.config.Config.Region = "auto"
 	awsClient := aws.NewClient(
 		c.ctx,
-		c.config.Conn,
-		c.config.Config,
-		c.config.DumpName,
-		c.config.FileSize,
+		c.config,
 		c.backend,
 	)
 
 	return awsClient.Handler()
 }
+
+func (c *Cloudflare) Load() (io.ReadCloser, error) {
+	awsClient := aws.NewClient(
+		c.ctx,
+		c.config,
+		c.backend,
+	)
+
+	return awsClient.Load()
+}
+
+func (c *Cloudflare) List() ([]storage.File, error) {
+	awsClient := aws.NewClient(
+		c.ctx,
+		c.config,
+		c.backend,
+	)
+
+	return awsClient.List()
+}
+
+func (c *Cloudflare) Delete(name string) error {
+	awsClient := aws.NewClient(
+		c.ctx,
+		c.config,
+		c.backend,
+	)
+
+	return awsClient.Delete(name)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"dumper/internal/domain/storage"
 	"dumper/internal/storage/client/aws"
+	"io"
 )
 
 type DigitalOcean struct {
@@ -26,12 +27,39 @@ func NewApp(
 func (d *DigitalOcean) Save() error {
 	awsClient := aws.NewClient(
 		d.ctx,
-		d.config.Conn,
-		d.config.Config,
-		d.config.DumpName,
-		d.config.FileSize,
+		d.config,
 		d.backend,
 	)
 
 	return awsClient.Handler()
 }
+
+func (d *DigitalOcean) Load() (io.ReadCloser, error) {
+	awsClient := aws.NewClient(
+		d.ctx,
+		d.config,
+		d.backend,
+	)
+
+	return awsClient.Load()
+}
+
+func (d *DigitalOcean) List() ([]storage.File, error) {
+	awsClient := aws.NewClient(
+		d.ctx,
+		d.config,
+		d.backend,
+	)
+
+	return awsClient.List()
+}
+
+func (d *DigitalOcean) Delete(name string) error {
+	awsClient := aws.NewClient(
+		d.ctx,
+		d.config,
+		d.backend,
+	)
+
+	return awsClient.Delete(name)
+}
@@ -6,6 +6,7 @@ import (
 	"dumper/pkg/utils/console"
 	"dumper/pkg/utils/stream"
 	"fmt"
+	"io"
 	"path/filepath"
 	"strings"
 	"time"
@@ -59,7 +60,7 @@ func (f *FTP) Save() error {
 		}
 	}
 
-	pr, closeSSH, err := stream.SSHStreamer(f.ctx, f.config.Conn, f.config.DumpName, f.config.FileSize)
+	pr, closeSSH, err := stream.Open(f.ctx, f.config)
 
 	if err != nil {
 		return &storage.UploadError{
@@ -71,6 +72,8 @@ func (f *FTP) Save() error {
 	defer closeSSH()
 
 	if err := c.Stor(targetPath, pr); err != nil {
+		// a failed or unverified stream leaves a partial file behind
+		_ = f.Delete(f.config.DumpName)
 		return &storage.UploadError{
 			Backend: f.backend,
 			Err:     fmt.Errorf("failed to upload file via FTP: %v", err),
@@ -81,6 +84,104 @@ func (f *FTP) Save() error {
 	return nil
 }
 
+func (f *FTP) Load() (io.ReadCloser, error) {
+	c, err := f.client()
+	if err != nil {
+		return nil, &storage.LoadError{
+			Backend: f.backend,
+			Err:     err,
+		}
+	}
+
+	targetPath := stream.TargetPath(f.config.Config.Dir, f.config.DumpName)
+
+	if size, err := c.FileSize(targetPath); err == nil {
+		f.config.FileSize = size
+	}
+
+	resp, err := c.Retr(targetPath)
+	if err != nil {
+		_ = c.Quit()
+		return nil, &storage.LoadError{
+			Backend: f.backend,
+			Err:     fmt.Errorf("failed to download file via FTP: %v", err),
+		}
+	}
+
+	return stream.ReadCloser(resp, resp.Close, c.Quit), nil
+}
+
+func (f *FTP) List() ([]storage.File, error) {
+	c, err := f.client()
+	if err != nil {
+		return nil, &storage.LoadError{
+			Backend: f.backend,
+			Err:     err,
+		}
+	}
+	defer c.Quit()
+
+	entries, err := c.List(f.config.Config.Dir)
+	if err != nil {
+		return nil, &storage.LoadError{
+			Backend: f.backend,
+			Err:     fmt.Errorf("failed to list FTP directory: %v", err),
+		}
+	}
+
+	var files []storage.File
+	for _, entry := range entries {
+		if entry.Type != ftp.EntryTypeFile {
+			continue
+		}
+
+		files = append(files, storage.File{
+			Name:    filepath.Base(entry.Name),
+			Size:    int64(entry.Size),
+			ModTime: entry.Time,
+		})
+	}
+
+	return files, nil
+}
+
+func (f *FTP) Delete(name string) error {
+	c, err := f.client()
+	if err != nil {
+		return &storage.DeleteError{
+			Backend: f.backend,
+			Err:     err,
+		}
+	}
+	defer c.Quit()
+
+	targetPath := stream.TargetPath(f.config.Config.Dir, name)
+
+	if err := c.Delete(targetPath); err != nil {
+		return &storage.DeleteError{
+			Backend: f.backend,
+			Err:     fmt.Errorf("failed to delete file via FTP: %v", err),
+		}
+	}
+
+	return nil
+}
+
+func (f *FTP) client() (*ftp.ServerConn, error) {
+	addr := fmt.Sprintf("%s:%s", f.config.Config.Host, f.config.Config.Port)
+	c, err := ftp.Dial(addr, ftp.DialWithTimeout(10*time.Second))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to FTP server: %w", err)
+	}
+
+	if err := c.Login(f.config.Config.Username, f.config.Config.Password); err != nil {
+		_ = c.Quit()
+		return nil, fmt.Errorf("login failed: %w", err)
+	}
+
+	return c, nil
+}
+
 func isDirExistsError(err error) bool {
 	return err != nil && (err.Error() == "550 Create directory operation failed." || err.Error() == "550")
 }
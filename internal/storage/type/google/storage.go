@@ -5,10 +5,13 @@ import (
 	"dumper/internal/domain/storage"
 	"dumper/pkg/utils/console"
 	"dumper/pkg/utils/stream"
+	"errors"
 	"fmt"
 	"io"
+	"path"
 
 	googleClient "cloud.google.com/go/storage"
+	"google.golang.org/api/iterator"
 	"google.golang.org/api/option"
 )
 
@@ -30,33 +33,18 @@ func NewApp(
 }
 
 func (gc *GCS) Save() error {
-
-	var opts []option.ClientOption
-
-	if gc.config.Config.Credential != "" {
-		opts = append(
-			opts,
-			option.WithCredentialsJSON([]byte(gc.config.Config.Credential)),
-		)
-	} else if gc.config.Config.CredentialFile != "" {
-		opts = append(
-			opts,
-			option.WithCredentialsFile(gc.config.Config.CredentialFile),
-		)
-	}
-
-	client, err := googleClient.NewClient(gc.ctx, opts...)
+	client, err := gc.client()
 
 	if err != nil {
 		return &storage.UploadError{
 			Backend: gc.backend,
-			Err:     fmt.Errorf("failed to create GoogleCloud client: %w", err),
+			Err:     err,
 		}
 	}
 
 	defer client.Close()
 
-	pr, closeSSH, err := stream.SSHStreamer(gc.ctx, gc.config.Conn, gc.config.DumpName, gc.config.FileSize)
+	pr, closeSSH, err := stream.Open(gc.ctx, gc.config)
 
 	if err != nil {
 		return &storage.UploadError{
@@ -68,11 +56,17 @@ func (gc *GCS) Save() error {
 	defer closeSSH()
 	targetPath := stream.TargetPath(gc.config.Config.Dir, gc.config.DumpName)
 
-	writer := client.Bucket(gc.config.Config.Bucket).Object(targetPath).NewWriter(gc.ctx)
+	// cancelling the writer context abandons the upload, Close would commit
+	// whatever was written so far as the object
+	ctx, cancel := context.WithCancel(gc.ctx)
+	defer cancel()
+
+	writer := client.Bucket(gc.config.Config.Bucket).Object(targetPath).NewWriter(ctx)
 	writer.ContentType = "application/octet-stream"
 	writer.ChunkSize = 32 * 1024 * 1024
 
 	if _, err := io.Copy(writer, pr); err != nil {
+		cancel()
 		_ = writer.Close()
 		return &storage.UploadError{Backend: gc.backend, Err: err}
 	}
@@ -84,3 +78,117 @@ func (gc *GCS) Save() error {
 	console.SafePrintln("[GCS] Upload complete: %s", targetPath)
 	return nil
 }
+
+func (gc *GCS) Load() (io.ReadCloser, error) {
+	client, err := gc.client()
+	if err != nil {
+		return nil, &storage.LoadError{
+			Backend: gc.backend,
+			Err:     err,
+		}
+	}
+
+	targetPath := stream.TargetPath(gc.config.Config.Dir, gc.config.DumpName)
+
+	reader, err := client.Bucket(gc.config.Config.Bucket).Object(targetPath).NewReader(gc.ctx)
+	if err != nil {
+		_ = client.Close()
+		return nil, &storage.LoadError{
+			Backend: gc.backend,
+			Err:     fmt.Errorf("failed to read object %s: %w", targetPath, err),
+		}
+	}
+
+	gc.config.FileSize = reader.Attrs.Size
+
+	return stream.ReadCloser(reader, reader.Close, client.Close), nil
+}
+
+func (gc *GCS) List() ([]storage.File, error) {
+	client, err := gc.client()
+	if err != nil {
+		return nil, &storage.LoadError{
+			Backend: gc.backend,
+			Err:     err,
+		}
+	}
+	defer client.Close()
+
+	query := &googleClient.Query{
+		Prefix:    stream.ListPrefix(gc.config.Config.Dir),
+		Delimiter: "/",
+	}
+
+	var files []storage.File
+	it := client.Bucket(gc.config.Config.Bucket).Objects(gc.ctx, query)
+
+	for {
+		attrs, err := it.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return nil, &storage.LoadError{
+				Backend: gc.backend,
+				Err:     fmt.Errorf("failed to list objects: %w", err),
+			}
+		}
+
+		if attrs.Name == "" {
+			continue
+		}
+
+		files = append(files, storage.File{
+			Name:    path.Base(attrs.Name),
+			Size:    attrs.Size,
+			ModTime: attrs.Updated,
+		})
+	}
+
+	return files, nil
+}
+
+func (gc *GCS) Delete(name string) error {
+	client, err := gc.client()
+	if err != nil {
+		return &storage.DeleteError{
+			Backend: gc.backend,
+			Err:     err,
+		}
+	}
+	defer client.Close()
+
+	targetPath := stream.TargetPath(gc.config.Config.Dir, name)
+
+	if err := client.Bucket(gc.config.Config.Bucket).Object(targetPath).Delete(gc.ctx); err != nil {
+		return &storage.DeleteError{
+			Backend: gc.backend,
+			Err:     fmt.Errorf("failed to delete object %s: %w", targetPath, err),
+		}
+	}
+
+	return nil
+}
+
+func (gc *GCS) client() (*googleClient.Client, error) {
+	var opts []option.ClientOption
+
+	if gc.config.Config.Credential != "" {
+		opts = append(
+			opts,
+			option.WithCredentialsJSON([]byte(gc.config.Config.Credential)),
+		)
+	} else if gc.config.Config.CredentialFile != "" {
+		opts = append(
+			opts,
+			option.WithCredentialsFile(gc.config.Config.CredentialFile),
+		)
+	}
+
+	client, err := googleClient.NewClient(gc.ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GoogleCloud client: %w", err)
+	}
+
+	return client, nil
+}
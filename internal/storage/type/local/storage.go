@@ -40,6 +40,11 @@ func (l *Local) Save() error {
 		}
 	}
 
+	if l.isSameFile(localPath) {
+		console.SafePrintln("[Local] Dump already in place: %s", localPath)
+		return nil
+	}
+
 	outFile, err := os.Create(localPath)
 	if err != nil {
 		return &storageDomain.UploadError{
@@ -49,7 +54,7 @@ func (l *Local) Save() error {
 	}
 	defer outFile.Close()
 
-	pr, closeSSH, err := stream.SSHStreamer(l.ctx, l.config.Conn, l.config.DumpName, l.config.FileSize)
+	pr, closeSSH, err := stream.Open(l.ctx, l.config)
 
 	if err != nil {
 		return &storageDomain.UploadError{
@@ -62,6 +67,7 @@ func (l *Local) Save() error {
 
 	if _, err := io.Copy(outFile, pr); err != nil {
 		outFile.Close()
+		_ = os.Remove(localPath)
 		return &storageDomain.UploadError{
 			Backend: l.backend,
 			Err:     fmt.Errorf("failed to write to local file: %v", err),
@@ -71,3 +77,83 @@ func (l *Local) Save() error {
 	console.SafePrintln("[Local] Upload complete: %s", localPath)
 	return nil
 }
+
+func (l *Local) Load() (io.ReadCloser, error) {
+	localPath := stream.TargetPath(l.config.Config.Dir, l.config.DumpName)
+
+	file, err := os.Open(localPath)
+	if err != nil {
+		return nil, &storageDomain.LoadError{
+			Backend: l.backend,
+			Err:     fmt.Errorf("failed to open local file: %v", err),
+		}
+	}
+
+	if info, err := file.Stat(); err == nil {
+		l.config.FileSize = info.Size()
+	}
+
+	return file, nil
+}
+
+func (l *Local) List() ([]storageDomain.File, error) {
+	entries, err := os.ReadDir(l.config.Config.Dir)
+	if err != nil {
+		return nil, &storageDomain.LoadError{
+			Backend: l.backend,
+			Err:     fmt.Errorf("failed to read local directory: %v", err),
+		}
+	}
+
+	var files []storageDomain.File
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+
+		files = append(files, storageDomain.File{
+			Name:    entry.Name(),
+			Size:    info.Size(),
+			ModTime: info.ModTime(),
+		})
+	}
+
+	return files, nil
+}
+
+func (l *Local) Delete(name string) error {
+	localPath := stream.TargetPath(l.config.Config.Dir, name)
+
+	if err := os.Remove(localPath); err != nil {
+		return &storageDomain.DeleteError{
+			Backend: l.backend,
+			Err:     fmt.Errorf("failed to remove local file: %v", err),
+		}
+	}
+
+	return nil
+}
+
+func (l *Local) isSameFile(localPath string) bool {
+	file, ok := l.config.Reader.(*os.File)
+	if !ok {
+		return false
+	}
+
+	source, err := file.Stat()
+	if err != nil {
+		return false
+	}
+
+	target, err := os.Stat(localPath)
+	if err != nil {
+		return false
+	}
+
+	return os.SameFile(source, target)
+}
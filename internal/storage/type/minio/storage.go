@@ -4,6 +4,7 @@ import (
 	"context"
 	"dumper/internal/domain/storage"
 	"dumper/internal/storage/client/aws"
+	"io"
 )
 
 type Minio struct {
@@ -26,12 +27,39 @@ func NewApp(
 func (m *Minio) Save() error {
 	awsClient := aws.NewClient(
 		m.ctx,
-		m.config.Conn,
-		m.config.Config,
-		m.config.DumpName,
-		m.config.FileSize,
+		m.config,
 		m.backend,
 	)
 
 	return awsClient.Handler()
 }
+
+func (m *Minio) Load() (io.ReadCloser, error) {
+	awsClient := aws.NewClient(
+		m.ctx,
+		m.config,
+		m.backend,
+	)
+
+	return awsClient.Load()
+}
+
+func (m *Minio) List() ([]storage.File, error) {
+	awsClient := aws.NewClient(
+		m.ctx,
+		m.config,
+		m.backend,
+	)
+
+	return awsClient.List()
+}
+
+func (m *Minio) Delete(name string) error {
+	awsClient := aws.NewClient(
+		m.ctx,
+		m.config,
+		m.backend,
+	)
+
+	return awsClient.Delete(name)
+}
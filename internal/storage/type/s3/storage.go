@@ -4,6 +4,7 @@ import (
 	"context"
 	"dumper/internal/domain/storage"
 	"dumper/internal/storage/client/aws"
+	"io"
 )
 
 type S3 struct {
@@ -26,12 +27,39 @@ func NewApp(
 func (s *S3) Save() error {
 	awsClient := aws.NewClient(
 		s.ctx,
-		s.config.Conn,
-		s.config.Config,
-		s.config.DumpName,
-		s.config.FileSize,
+		s.config,
 		s.backend,
 	)
 
 	return awsClient.Handler()
 }
+
+func (s *S3) Load() (io.ReadCloser, error) {
+	awsClient := aws.NewClient(
+		s.ctx,
+		s.config,
+		s.backend,
+	)
+
+	return awsClient.Load()
+}
+
+func (s *S3) List() ([]storage.File, error) {
+	awsClient := aws.NewClient(
+		s.ctx,
+		s.config,
+		s.backend,
+	)
+
+	return awsClient.List()
+}
+
+func (s *S3) Delete(name string) error {
+	awsClient := aws.NewClient(
+		s.ctx,
+		s.config,
+		s.backend,
+	)
+
+	return awsClient.Delete(name)
+}
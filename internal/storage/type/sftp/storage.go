@@ -3,7 +3,9 @@ package sftp
 import (
 	"context"
 	"dumper/internal/connect"
-	connectDomain "dumper/internal/domain/connect"
+	"dumper/internal/domain/config/server"
+	"dumper/internal/domain/config/setting"
+	sshConfig "dumper/internal/domain/config/ssh-config"
 	"dumper/internal/domain/storage"
 	"dumper/pkg/utils/console"
 	"dumper/pkg/utils/stream"
@@ -32,30 +34,11 @@ func NewApp(
 }
 
 func (s *SFTP) Save() error {
-	connectDto := &connectDomain.Connect{
-		Server:       s.config.Config.Host,
-		Port:         s.config.Config.Port,
-		Username:     s.config.Config.Username,
-		Password:     s.config.Config.Password,
-		PrivateKey:   s.config.Config.PrivateKey,
-		Passphrase:   s.config.Config.Passphrase,
-		IsPassphrase: true,
-	}
-
-	tClient := connect.NewApp(s.ctx, connectDto)
-
-	if err := tClient.Connect(); err != nil {
-		return &storage.UploadError{
-			Backend: s.backend,
-			Err:     fmt.Errorf("failed to connect target SFTP: %v", err),
-		}
-	}
-
-	targetClient, err := sftp.NewClient(tClient.Client())
+	targetClient, err := s.client()
 	if err != nil {
 		return &storage.UploadError{
 			Backend: s.backend,
-			Err:     fmt.Errorf("failed to create target SFTP client: %v", err),
+			Err:     err,
 		}
 	}
 	defer targetClient.Close()
@@ -63,14 +46,14 @@ func (s *SFTP) Save() error {
 	targetPath := stream.TargetPath(s.config.Config.Dir, s.config.DumpName)
 	dir := filepath.Dir(targetPath)
 
-	if err := s.checkDirAccessible(targetClient, dir); err != nil {
+	if err := s.checkDirAccessible(targetClient.Client, dir); err != nil {
 		return &storage.UploadError{
 			Backend: s.backend,
 			Err:     err,
 		}
 	}
 
-	pr, closeSSH, err := stream.SSHStreamer(s.ctx, s.config.Conn, s.config.DumpName, s.config.FileSize)
+	pr, closeSSH, err := stream.Open(s.ctx, s.config)
 
 	if err != nil {
 		return &storage.UploadError{
@@ -92,6 +75,7 @@ func (s *SFTP) Save() error {
 	defer dstFile.Close()
 
 	if _, err := io.Copy(dstFile, pr); err != nil {
+		_ = targetClient.Remove(targetPath)
 		return &storage.UploadError{
 			Backend: s.backend,
 			Err:     fmt.Errorf("failed to upload to SFTP: %w", err),
@@ -102,6 +86,138 @@ func (s *SFTP) Save() error {
 	return nil
 }
 
+func (s *SFTP) Load() (io.ReadCloser, error) {
+	targetClient, err := s.client()
+	if err != nil {
+		return nil, &storage.LoadError{
+			Backend: s.backend,
+			Err:     err,
+		}
+	}
+
+	targetPath := stream.TargetPath(s.config.Config.Dir, s.config.DumpName)
+
+	srcFile, err := targetClient.Open(targetPath)
+	if err != nil {
+		_ = targetClient.Close()
+		return nil, &storage.LoadError{
+			Backend: s.backend,
+			Err:     fmt.Errorf("failed to open remote file: %w", err),
+		}
+	}
+
+	if info, err := srcFile.Stat(); err == nil {
+		s.config.FileSize = info.Size()
+	}
+
+	return stream.ReadCloser(srcFile, srcFile.Close, targetClient.Close), nil
+}
+
+func (s *SFTP) List() ([]storage.File, error) {
+	targetClient, err := s.client()
+	if err != nil {
+		return nil, &storage.LoadError{
+			Backend: s.backend,
+			Err:     err,
+		}
+	}
+	defer targetClient.Close()
+
+	entries, err := targetClient.ReadDir(s.config.Config.Dir)
+	if err != nil {
+		return nil, &storage.LoadError{
+			Backend: s.backend,
+			Err:     fmt.Errorf("failed to read remote directory: %w", err),
+		}
+	}
+
+	var files []storage.File
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		files = append(files, storage.File{
+			Name:    entry.Name(),
+			Size:    entry.Size(),
+			ModTime: entry.ModTime(),
+		})
+	}
+
+	return files, nil
+}
+
+func (s *SFTP) Delete(name string) error {
+	targetClient, err := s.client()
+	if err != nil {
+		return &storage.DeleteError{
+			Backend: s.backend,
+			Err:     err,
+		}
+	}
+	defer targetClient.Close()
+
+	targetPath := stream.TargetPath(s.config.Config.Dir, name)
+
+	if err := targetClient.Remove(targetPath); err != nil {
+		return &storage.DeleteError{
+			Backend: s.backend,
+			Err:     fmt.Errorf("failed to remove remote file: %w", err),
+		}
+	}
+
+	return nil
+}
+
+// sftpClient closes the SSH connection along with the SFTP session.
+type sftpClient struct {
+	*sftp.Client
+	conn *connect.Connect
+}
+
+func (c *sftpClient) Close() error {
+	err := c.Client.Close()
+	_ = c.conn.Close()
+	return err
+}
+
+func (s *SFTP) client() (*sftpClient, error) {
+	agent := s.config.Config.IsAgent()
+	isPassphrase := true
+
+	// the storage has no global ssh settings to fall back on
+	connectDto := connect.FromServer(server.Server{
+		Host:                s.config.Config.Host,
+		Port:                s.config.Config.Port,
+		User:                s.config.Config.Username,
+		Password:            s.config.Config.Password,
+		PrivateKey:          s.config.Config.PrivateKey,
+		Passphrase:          s.config.Config.Passphrase,
+		KnownHosts:          s.config.Config.KnownHosts,
+		HostKeyChecking:     s.config.Config.HostKeyChecking,
+		HostKeyFingerprints: s.config.Config.HostKeyFingerprints,
+		Agent:               &agent,
+		Certificate:         s.config.Config.Certificate,
+		JumpHosts:           s.config.Config.JumpHosts,
+	}, &setting.Settings{
+		SSH: &sshConfig.SSHConfig{IsPassphrase: &isPassphrase},
+	})
+
+	tClient := connect.NewApp(s.ctx, connectDto)
+
+	if err := tClient.Connect(); err != nil {
+		return nil, fmt.Errorf("failed to connect target SFTP: %v", err)
+	}
+
+	targetClient, err := tClient.NewSFTP()
+	if err != nil {
+		_ = tClient.Close()
+		return nil, fmt.Errorf("failed to create target SFTP client: %v", err)
+	}
+
+	return &sftpClient{Client: targetClient, conn: tClient}, nil
+}
+
 func (s *SFTP) checkDirAccessible(client *sftp.Client, dir string) error {
 	if err := client.MkdirAll(dir); err != nil {
 		return fmt.Errorf("SFTP directory %s is not accessible: %w", dir, err)
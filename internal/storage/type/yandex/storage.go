@@ -4,6 +4,7 @@ import (
 	"context"
 	"dumper/internal/domain/storage"
 	"dumper/internal/storage/client/aws"
+	"io"
 )
 
 type Yandex struct {
@@ -27,11 +28,38 @@ func (y *Yandex) Save() error {
 
 	awsClient := aws.NewClient(
 		y.ctx,
-		y.config.Conn,
-		y.config.Config,
-		y.config.DumpName,
-		y.config.FileSize,
+		y.config,
 		y.backend,
 	)
 	return awsClient.Handler()
 }
+
+func (y *Yandex) Load() (io.ReadCloser, error) {
+	awsClient := aws.NewClient(
+		y.ctx,
+		y.config,
+		y.backend,
+	)
+
+	return awsClient.Load()
+}
+
+func (y *Yandex) List() ([]storage.File, error) {
+	awsClient := aws.NewClient(
+		y.ctx,
+		y.config,
+		y.backend,
+	)
+
+	return awsClient.List()
+}
+
+func (y *Yandex) Delete(name string) error {
+	awsClient := aws.NewClient(
+		y.ctx,
+		y.config,
+		y.backend,
+	)
+
+	return awsClient.Delete(name)
+}
@@ -2,13 +2,25 @@ package upload
 
 import (
 	"context"
+	"crypto/sha256"
+	"dumper/internal/catalog"
 	"dumper/internal/connect"
+	backupDomain "dumper/internal/domain/backup"
+	catalogDomain "dumper/internal/domain/catalog"
 	commandConfig "dumper/internal/domain/command-config"
 	storageDomain "dumper/internal/domain/storage"
+	"dumper/internal/metrics"
 	"dumper/internal/storage"
 	"dumper/pkg/logging"
+	"dumper/pkg/utils/checksum"
+	"dumper/pkg/utils/format"
 	"dumper/pkg/utils/progress"
+	"dumper/pkg/utils/stream"
+	"encoding/hex"
 	"fmt"
+	"io"
+	"os"
+	"strings"
 	"sync"
 	"time"
 )
@@ -17,6 +29,7 @@ type Upload struct {
 	ctx    context.Context
 	conn   *connect.Connect
 	config *commandConfig.Config
+	mu     sync.Mutex
 }
 
 func New(
@@ -34,6 +47,63 @@ func New(
 func (u *Upload) Uploading() error {
 	logging.L(u.ctx).Info("Downloading dump", logging.StringAttr("name", u.config.DumpName))
 
+	upload := u.uploadEach
+	if u.config.Encrypt.IsEnabled() {
+		upload = u.uploadEncrypted
+	}
+
+	if err := upload(); err != nil {
+		return err
+	}
+
+	u.writeChecksums()
+
+	for _, file := range u.config.FileRemoveList {
+		if !file.IsRemove {
+			continue
+		}
+
+		if u.config.DumpLocation == "local-direct" {
+			if u.isStoredInPlace(file.Name) {
+				continue
+			}
+
+			logging.L(u.ctx).Info("Removing local dump", logging.StringAttr("file", file.Name))
+			fmt.Println("Removing local dump:", file.Name)
+			if err := os.Remove(file.Name); err != nil && !os.IsNotExist(err) {
+				logging.L(u.ctx).Error(
+					"Failed to remove local dump",
+					logging.StringAttr("file", file.Name),
+					logging.ErrAttr(err),
+				)
+				return fmt.Errorf("failed to delete local dump: %v", err)
+			}
+			continue
+		}
+
+		logging.L(u.ctx).Info(
+			"Removing dump on server",
+			logging.StringAttr("file", file.Name),
+		)
+		fmt.Println("Removing dump from server:", file.Name)
+		if msg, err := u.conn.RunCommand(fmt.Sprintf("rm -f %s", file.Name)); err != nil {
+			logging.L(u.ctx).Error(
+				"Failed to remove dump on server",
+				logging.StringAttr("file", file.Name),
+				logging.StringAttr("msg", msg),
+			)
+			return fmt.Errorf("failed to delete dump on server: %v", err)
+		}
+	}
+
+	logging.L(u.ctx).Info("The dump was successfully deleted on server")
+
+	return nil
+}
+
+// uploadEach lets every storage read the dump by itself, up to
+// MaxParallelDownload at a time.
+func (u *Upload) uploadEach() error {
 	var countStorage int8
 	var totalAll int64
 	var totalDone int64
@@ -48,7 +118,7 @@ func (u *Upload) Uploading() error {
 
 	globalProgress := progress.GlobalProgress(totalAll)
 
-	for _, storageItem := range u.config.Storages {
+	for storageName, storageItem := range u.config.Storages {
 		countStorage++
 		wg.Add(1)
 		go func() {
@@ -60,15 +130,32 @@ func (u *Upload) Uploading() error {
 			select {
 			case <-u.ctx.Done():
 				logging.L(u.ctx).Info("download cancelled by context")
-				errCh <- fmt.Errorf("download cancelled for storage %s", storageItem.Type)
+				err := fmt.Errorf("download cancelled for storage %s", storageItem.Type)
+				u.track(storageName, storageItem.Type, dumpDownloadTimeNow, "", err)
+				errCh <- err
 				return
 			default:
 				storageConfig := storageDomain.Config{
 					Type:     storageItem.Type,
-					DumpName: u.config.DumpName,
+					DumpName: u.config.StoredName(),
+					Source:   u.config.DumpName,
 					FileSize: totalSize,
+					Checksum: u.config.Checksum,
 					Conn:     u.conn,
 					Config:   storageItem,
+					Retries:  u.config.RetryConnect,
+				}
+
+				if u.config.DumpLocation == "local-direct" {
+					file, err := os.Open(u.config.DumpName)
+					if err != nil {
+						err = fmt.Errorf("failed to open dump %s: %w", u.config.DumpName, err)
+						u.track(storageName, storageItem.Type, dumpDownloadTimeNow, "", err)
+						errCh <- err
+						return
+					}
+					defer file.Close()
+					storageConfig.Reader = file
 				}
 
 				ctx := context.WithValue(u.ctx, "globalProgress", globalProgress)
@@ -79,10 +166,14 @@ func (u *Upload) Uploading() error {
 						"Failed to download dump",
 						logging.ErrAttr(err),
 					)
+					u.track(storageName, storageItem.Type, dumpDownloadTimeNow, "", err)
 					errCh <- fmt.Errorf("failed to download dump %s: %w", u.config.DumpName, err)
 					return
 				}
 
+				u.track(storageName, storageItem.Type, dumpDownloadTimeNow, storageConfig.StoredChecksum, nil)
+				metrics.ObserveUpload(u.config.Database.Key, storageItem.Type, time.Since(dumpDownloadTimeNow))
+
 				dumpDownloadTimeSec := fmt.Sprintf("%.2f sec", time.Since(dumpDownloadTimeNow).Seconds())
 
 				logging.L(u.ctx).Info(
@@ -110,27 +201,267 @@ func (u *Upload) Uploading() error {
 		}
 	}
 
-	for _, file := range u.config.FileRemoveList {
-		if !file.IsRemove {
+	return nil
+}
+
+// track records how the dump went to one storage, sum being the sha256 of
+// what the storage received.
+func (u *Upload) track(name, storageType string, startedAt time.Time, sum string, err error) {
+	upload := catalogDomain.Upload{
+		Storage:  name,
+		Type:     storageType,
+		Status:   catalog.StatusSuccess,
+		Duration: time.Since(startedAt).Seconds(),
+		Sha256:   sum,
+	}
+
+	if err != nil {
+		upload.Status = catalog.StatusFailed
+		upload.Error = err.Error()
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.config.Uploads = append(u.config.Uploads, upload)
+	if err == nil {
+		u.config.Uploaded = append(u.config.Uploaded, name)
+	}
+}
+
+// isStoredInPlace reports whether a local storage already points at the
+// dump file, in which case removing it would delete the backup itself.
+func (u *Upload) isStoredInPlace(name string) bool {
+	info, err := os.Stat(name)
+	if err != nil {
+		return false
+	}
+
+	for _, storageItem := range u.config.Storages {
+		if storageItem.Type != "local" {
 			continue
 		}
+		target, err := os.Stat(stream.TargetPath(storageItem.Dir, u.config.StoredName()))
+		if err == nil && os.SameFile(info, target) {
+			return true
+		}
+	}
 
-		logging.L(u.ctx).Info(
-			"Removing dump on server",
-			logging.StringAttr("file", file.Name),
-		)
-		fmt.Println("Removing dump from server:", file.Name)
-		if msg, err := u.conn.RunCommand(fmt.Sprintf("rm -f %s", file.Name)); err != nil {
-			logging.L(u.ctx).Error(
-				"Failed to remove dump on server",
-				logging.StringAttr("file", file.Name),
-				logging.StringAttr("msg", msg),
+	return false
+}
+
+// Streaming fans src out to every storage. wait reports the exit status of
+// the command producing src, it runs once src is drained and before the
+// storage writers are closed, so a failed dump aborts every upload instead of
+// being committed as a complete backup.
+func (u *Upload) Streaming(src io.Reader, wait func() error) error {
+	logging.L(u.ctx).Info("Streaming dump", logging.StringAttr("name", u.config.DumpName))
+
+	dumpStreamTimeNow := time.Now()
+
+	hash := sha256.New()
+	size := &counter{}
+	var dump io.Reader = io.TeeReader(src, io.MultiWriter(hash, size))
+
+	stopEncrypt := func() error { return nil }
+	if u.config.Encrypt.IsEnabled() {
+		encrypted, stop, err := stream.Encrypt(dump, u.config.Encrypt)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt dump: %w", err)
+		}
+		dump = encrypted
+		stopEncrypt = stop
+	}
+
+	err := u.fanOut(dump, wait, dumpStreamTimeNow)
+	if err != nil {
+		_ = stopEncrypt()
+	}
+
+	u.config.FileSize = size.n
+	u.config.Checksum = hex.EncodeToString(hash.Sum(nil))
+	metrics.ObserveDump(u.config.Database.Key, time.Since(dumpStreamTimeNow))
+
+	fmt.Printf("\rDump streamed in %.2f sec\n", time.Since(dumpStreamTimeNow).Seconds())
+	fmt.Printf("\rFile dump size: %s [%d bytes]\n", format.FormatBytes(size.n), size.n)
+
+	if err != nil {
+		return err
+	}
+
+	u.writeChecksums()
+
+	return nil
+}
+
+// uploadEncrypted reads the dump once, encrypts it once and fans the
+// ciphertext out, so every storage holds the same bytes under the same
+// checksum instead of running its own key derivation and cipher.
+func (u *Upload) uploadEncrypted() error {
+	startedAt := time.Now()
+
+	source := storageDomain.Config{
+		DumpName: u.config.StoredName(),
+		Source:   u.config.DumpName,
+		FileSize: u.config.FileSize,
+		Checksum: u.config.Checksum,
+		Encrypt:  u.config.Encrypt,
+		Conn:     u.conn,
+		Retries:  u.config.RetryConnect,
+	}
+
+	fail := func(err error) error {
+		for storageName, storageItem := range u.config.Storages {
+			u.track(storageName, storageItem.Type, startedAt, "", err)
+		}
+		return err
+	}
+
+	if u.config.DumpLocation == "local-direct" {
+		file, err := os.Open(u.config.DumpName)
+		if err != nil {
+			return fail(fmt.Errorf("failed to open dump %s: %w", u.config.DumpName, err))
+		}
+		defer file.Close()
+		source.Reader = file
+	}
+
+	src, closeSource, err := stream.Open(u.ctx, &source)
+	if err != nil {
+		return fail(fmt.Errorf("failed to open dump %s: %w", u.config.DumpName, err))
+	}
+
+	// fanOut only closes the source once it is drained, a failed upload
+	// closes it here so the encryption and the read stop as well
+	if err := u.fanOut(src, closeSource, startedAt); err != nil {
+		_ = closeSource()
+		return err
+	}
+
+	return nil
+}
+
+// fanOut copies src to every storage at once. wait, when set, runs after
+// src is drained, its error aborts the uploads like a failed read.
+func (u *Upload) fanOut(src io.Reader, wait func() error, startedAt time.Time) error {
+	countStorage := len(u.config.Storages)
+
+	wg := &sync.WaitGroup{}
+	errCh := make(chan error, countStorage)
+	writers := make([]*io.PipeWriter, 0, countStorage)
+
+	for storageName, storageItem := range u.config.Storages {
+		pr, pw := io.Pipe()
+		writers = append(writers, pw)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			storageConfig := storageDomain.Config{
+				Type:     storageItem.Type,
+				DumpName: u.config.StoredName(),
+				Conn:     u.conn,
+				Config:   storageItem,
+				Reader:   pr,
+			}
+
+			storageApp := storage.NewApp(u.ctx, &storageConfig)
+			if err := storageApp.Save(); err != nil {
+				_ = pr.CloseWithError(err)
+				logging.L(u.ctx).Error(
+					"Failed to stream dump",
+					logging.StringAttr("storage", storageItem.Type),
+					logging.ErrAttr(err),
+				)
+				u.track(storageName, storageItem.Type, startedAt, "", err)
+				errCh <- fmt.Errorf("failed to stream dump %s: %w", u.config.DumpName, err)
+				return
+			}
+
+			u.track(storageName, storageItem.Type, startedAt, storageConfig.StoredChecksum, nil)
+			metrics.ObserveUpload(u.config.Database.Key, storageItem.Type, time.Since(startedAt))
+
+			logging.L(u.ctx).Info(
+				"The dump was successfully streamed",
+				logging.StringAttr("time", fmt.Sprintf("%.2f sec", time.Since(startedAt).Seconds())),
+				logging.StringAttr("storage", storageItem.Type),
 			)
-			return fmt.Errorf("failed to delete dump on server: %v", err)
+		}()
+	}
+
+	_, err := stream.Tee(src, writers...)
+	if err == nil && wait != nil {
+		err = wait()
+	}
+
+	for _, w := range writers {
+		if err != nil {
+			_ = w.CloseWithError(err)
+			continue
 		}
+		_ = w.Close()
 	}
 
-	logging.L(u.ctx).Info("The dump was successfully deleted on server")
+	wg.Wait()
+	close(errCh)
+
+	for storageErr := range errCh {
+		countStorage--
+		fmt.Println(storageErr)
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to stream dump: %w", err)
+	}
+
+	if countStorage == 0 {
+		u.config.Phase = backupDomain.PhaseUpload
+		return fmt.Errorf("failed to stream dump")
+	}
 
 	return nil
 }
+
+// counter counts the bytes written to it.
+type counter struct {
+	n int64
+}
+
+func (c *counter) Write(p []byte) (int, error) {
+	c.n += int64(len(p))
+	return len(p), nil
+}
+
+// writeChecksums stores a sha256sum sidecar next to the dump on every storage
+// that received it. It only runs once the dump command is known to have
+// succeeded, a sidecar must never vouch for a truncated dump. A missing
+// sidecar does not fail the backup.
+func (u *Upload) writeChecksums() {
+	name := u.config.StoredName()
+
+	for _, upload := range u.config.Uploads {
+		if upload.Status != catalog.StatusSuccess || upload.Sha256 == "" {
+			continue
+		}
+
+		storageItem := u.config.Storages[upload.Storage]
+
+		storageConfig := storageDomain.Config{
+			Type:     storageItem.Type,
+			DumpName: checksum.SidecarName(name),
+			Conn:     u.conn,
+			Config:   storageItem,
+			Reader:   strings.NewReader(checksum.Sidecar(upload.Sha256, name)),
+		}
+
+		if err := storage.NewApp(u.ctx, &storageConfig).Save(); err != nil {
+			logging.L(u.ctx).Error(
+				"Failed to write checksum",
+				logging.StringAttr("storage", upload.Storage),
+				logging.ErrAttr(err),
+			)
+			fmt.Printf("failed to write checksum to %s: %v\n", upload.Storage, err)
+		}
+	}
+}
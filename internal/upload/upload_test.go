@@ -0,0 +1,92 @@
+package upload_test
+
+import (
+	commandConfig "dumper/internal/domain/command-config"
+	"dumper/internal/domain/config/encrypt"
+	"dumper/internal/domain/config/storage"
+	"dumper/internal/upload"
+	"dumper/pkg/utils/checksum"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func streamConfig(dir string) *commandConfig.Config {
+	return &commandConfig.Config{
+		Database: commandConfig.Database{Key: "app"},
+		DumpName: "app.sql",
+		Storages: map[string]storage.Storage{
+			"disk": {Type: "local", Dir: dir},
+		},
+	}
+}
+
+func TestStreaming_Stores(t *testing.T) {
+	dir := t.TempDir()
+	cfg := streamConfig(dir)
+
+	err := upload.New(t.Context(), nil, cfg).Streaming(strings.NewReader("dump"), func() error { return nil })
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(filepath.Join(dir, "app.sql"))
+	require.NoError(t, err)
+	assert.Equal(t, "dump", string(data))
+	assert.Equal(t, []string{"disk"}, cfg.Uploaded)
+
+	sidecar, err := os.ReadFile(filepath.Join(dir, "app.sql.sha256"))
+	require.NoError(t, err)
+	assert.Equal(t, checksum.Sidecar(cfg.Checksum, "app.sql"), string(sidecar))
+}
+
+func TestStreaming_FailedDumpIsNotStored(t *testing.T) {
+	dir := t.TempDir()
+	cfg := streamConfig(dir)
+
+	err := upload.New(t.Context(), nil, cfg).Streaming(
+		strings.NewReader("truncated"),
+		func() error { return errors.New("pg_dump exited with 1") },
+	)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "pg_dump exited with 1")
+
+	assert.NoFileExists(t, filepath.Join(dir, "app.sql"))
+	assert.NoFileExists(t, filepath.Join(dir, "app.sql.sha256"))
+	assert.Empty(t, cfg.Uploaded)
+}
+
+func TestUploading_EncryptsOnce(t *testing.T) {
+	dir := t.TempDir()
+	enabled := true
+
+	dump := filepath.Join(dir, "app.sql")
+	require.NoError(t, os.WriteFile(dump, []byte("dump"), 0600))
+
+	cfg := &commandConfig.Config{
+		Database:            commandConfig.Database{Key: "app"},
+		DumpName:            dump,
+		DumpLocation:        "local-direct",
+		MaxParallelDownload: 2,
+		Encrypt:             encrypt.Encrypt{Type: encrypt.TypeAES, Password: "secret", Enabled: &enabled},
+		Storages: map[string]storage.Storage{
+			"first":  {Type: "local", Dir: filepath.Join(dir, "first")},
+			"second": {Type: "local", Dir: filepath.Join(dir, "second")},
+		},
+	}
+
+	require.NoError(t, upload.New(t.Context(), nil, cfg).Uploading())
+
+	first, err := os.ReadFile(filepath.Join(dir, "first", "app.sql.enc"))
+	require.NoError(t, err)
+	second, err := os.ReadFile(filepath.Join(dir, "second", "app.sql.enc"))
+	require.NoError(t, err)
+	assert.Equal(t, first, second)
+
+	require.Len(t, cfg.Uploads, 2)
+	assert.NotEmpty(t, cfg.Uploads[0].Sha256)
+	assert.Equal(t, cfg.Uploads[0].Sha256, cfg.Uploads[1].Sha256)
+}
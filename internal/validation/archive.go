@@ -0,0 +1,24 @@
+package validation
+
+import (
+	"dumper/internal/domain/config/archive"
+	"fmt"
+)
+
+func validateArchive(a archive.Archive) error {
+	switch a.Algo {
+	case archive.AlgoGzip, archive.AlgoPigz, archive.AlgoZstd, archive.AlgoXz, archive.AlgoLz4, archive.AlgoNone:
+	default:
+		return fmt.Errorf("algo must be gzip, pigz, zstd, xz, lz4 or none, got '%s'", a.Algo)
+	}
+
+	if a.IsEnabled() && (a.Level < 0 || a.Level > a.MaxLevel()) {
+		return fmt.Errorf("level of %s must be between 1 and %d, got %d", a.Algo, a.MaxLevel(), a.Level)
+	}
+
+	if a.Threads < 0 {
+		return fmt.Errorf("threads must not be negative, got %d", a.Threads)
+	}
+
+	return nil
+}
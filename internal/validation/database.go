@@ -12,6 +12,7 @@ import (
 func validateDatabase(v *Validation, cfg *config.Config) error {
 	for name, db := range cfg.Databases {
 
+		db.Key = name
 		db.Name = db.GetName()
 		db.Port = db.GetPort(&cfg.Settings.DBPort)
 		db.Driver = db.GetDriver(&cfg.Settings.Driver)
@@ -22,8 +23,8 @@ func validateDatabase(v *Validation, cfg *config.Config) error {
 		db.Docker = &docker
 		removeDump := db.GetRemoveDump(cfg.Settings.RemoveDump)
 		db.RemoveDump = &removeDump
-		isArchive := db.IsArchive(*cfg.Settings.Archive)
-		db.Archive = &isArchive
+		dbArchive := db.GetArchive(cfg.Settings.Archive)
+		db.Archive = &dbArchive
 
 		if db.Port == "" {
 			db.Port = mapping.GetDefaultDBPort(db.Driver)
@@ -49,10 +50,42 @@ func validateDatabase(v *Validation, cfg *config.Config) error {
 			return fmt.Errorf("database '%s' invalid driver: '%s' or invalid format: '%s'", name, db.Driver, db.Format)
 		}
 
+		if cfg.Settings.DumpLocation == "local-ssh" && !mapping.IsStreamFormatDump(db.Driver, db.Format) {
+			return fmt.Errorf("database '%s' format '%s' of driver '%s' can't be streamed with location 'local-ssh'", name, db.Format, db.Driver)
+		}
+
 		if err := v.validator.Struct(db); err != nil {
 			return fmt.Errorf("database '%s' invalid: %w", name, HumanError(err))
 		}
 
+		if db.Driver == "psql" {
+			if err := validatePostgres(db); err != nil {
+				return fmt.Errorf("database '%s' invalid options: %w", name, err)
+			}
+		}
+
+		if db.Driver == "mysql" || db.Driver == "mariadb" {
+			if err := validateMySQL(db); err != nil {
+				return fmt.Errorf("database '%s' invalid options: %w", name, err)
+			}
+
+			if db.Options.Incremental {
+				if err := validateIncremental(cfg, db); err != nil {
+					return fmt.Errorf("database '%s' invalid options: %w", name, err)
+				}
+			}
+		}
+
+		if db.IsDiscover() {
+			if err := validateDiscover(db); err != nil {
+				return fmt.Errorf("database '%s' invalid discover: %w", name, err)
+			}
+		}
+
+		if err := validateArchive(*db.Archive); err != nil {
+			return fmt.Errorf("database '%s' invalid archive: %w", name, err)
+		}
+
 		if _, ok := cfg.Servers[db.Server]; !ok {
 			return fmt.Errorf("database '%s' has invalid server '%s'", name, db.Server)
 		}
@@ -0,0 +1,23 @@
+package validation
+
+import (
+	"dumper/internal/domain/config/database"
+	"dumper/pkg/utils/mapping"
+	"fmt"
+	"regexp"
+)
+
+func validateDiscover(db database.Database) error {
+	if !mapping.IsDiscoverable(db.Driver) {
+		return fmt.Errorf("driver '%s' can't list its databases, discover works with psql, mysql, mariadb and mongo", db.Driver)
+	}
+
+	patterns := append(append([]string(nil), db.Discover.Include...), db.Discover.Exclude...)
+	for _, pattern := range patterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("invalid pattern '%s': %v", pattern, err)
+		}
+	}
+
+	return nil
+}
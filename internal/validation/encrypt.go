@@ -0,0 +1,47 @@
+package validation
+
+import (
+	"dumper/internal/domain/config"
+	"dumper/internal/domain/config/encrypt"
+	"dumper/pkg/utils/aesstream"
+	"dumper/pkg/utils/age"
+	"fmt"
+)
+
+func validateEncrypt(cfg *config.Config) error {
+	check := func(scope string, e *encrypt.Encrypt) error {
+		if e == nil || e.Type == "" || (e.Enabled != nil && !*e.Enabled) {
+			return nil
+		}
+
+		switch e.Type {
+		case encrypt.TypeAES:
+			if !aesstream.IsKDF(e.KDF) {
+				return fmt.Errorf("%s encrypt invalid: unsupported kdf '%s'", scope, e.KDF)
+			}
+		case encrypt.TypeAge:
+			if e.Password != "" {
+				return fmt.Errorf("%s encrypt invalid: age uses recipients, not a password", scope)
+			}
+			if _, err := age.ParseRecipients(e.Recipients); err != nil {
+				return fmt.Errorf("%s encrypt invalid: %w", scope, err)
+			}
+		default:
+			return fmt.Errorf("%s encrypt invalid: unsupported type '%s'", scope, e.Type)
+		}
+
+		return nil
+	}
+
+	if err := check("settings", cfg.Settings.Encrypt); err != nil {
+		return err
+	}
+
+	for name, db := range cfg.Databases {
+		if err := check(fmt.Sprintf("database '%s'", name), db.Encrypt); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,37 @@
+package validation
+
+import (
+	"dumper/internal/connect"
+	"dumper/internal/domain/config/server"
+	connectDomain "dumper/internal/domain/connect"
+	"fmt"
+)
+
+func validateHostKey(checking string, fingerprints []string) error {
+	switch checking {
+	case "", connectDomain.HostKeyStrict, connectDomain.HostKeyAcceptNew, connectDomain.HostKeyOff:
+	default:
+		return fmt.Errorf("host_key_checking must be strict, accept-new or off, got '%s'", checking)
+	}
+
+	for _, fp := range fingerprints {
+		if !connect.ValidFingerprint(fp) {
+			return fmt.Errorf("host key fingerprint '%s' is invalid, expected SHA256:... as printed by ssh-keygen -l", fp)
+		}
+	}
+
+	return nil
+}
+
+func validateJumpHosts(v *Validation, hosts []server.JumpHost) error {
+	for i, host := range hosts {
+		if err := v.validator.Struct(host); err != nil {
+			return fmt.Errorf("jump host %d invalid: %w", i+1, HumanError(err))
+		}
+		if err := validateHostKey("", host.HostKeyFingerprints); err != nil {
+			return fmt.Errorf("jump host %s invalid: %w", host.Host, err)
+		}
+	}
+
+	return nil
+}
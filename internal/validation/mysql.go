@@ -0,0 +1,61 @@
+package validation
+
+import (
+	"dumper/internal/domain/config"
+	"dumper/internal/domain/config/database"
+	"fmt"
+	"strings"
+)
+
+func validateMySQL(db database.Database) error {
+	options := db.Options
+	physical := db.Format == "xtrabackup" || db.Format == "mariabackup"
+
+	if options.SetGtidPurged != "" {
+		switch strings.ToUpper(options.SetGtidPurged) {
+		case "OFF", "ON", "AUTO", "COMMENTED":
+		default:
+			return fmt.Errorf("set_gtid_purged must be OFF, ON, AUTO or COMMENTED, got '%s'", options.SetGtidPurged)
+		}
+
+		if db.Driver != "mysql" || db.Format != "sql" {
+			return fmt.Errorf("set_gtid_purged is an option of mysqldump, it needs driver 'mysql' with format 'sql'")
+		}
+	}
+
+	if options.Jobs < 0 {
+		return fmt.Errorf("jobs must not be negative, got %d", options.Jobs)
+	}
+
+	if options.Jobs > 0 && db.Format == "sql" {
+		return fmt.Errorf("jobs needs format 'mydumper', 'xtrabackup' or 'mariabackup', format 'sql' dumps with a single thread")
+	}
+
+	if options.Incremental && !physical {
+		return fmt.Errorf("incremental needs format 'xtrabackup' or 'mariabackup'")
+	}
+
+	if physical && len(options.IncTables)+len(options.ExcTables) > 0 {
+		return fmt.Errorf("format '%s' copies the whole server, table filters don't apply", db.Format)
+	}
+
+	return nil
+}
+
+// validateIncremental refuses retention for incremental copies. Each copy
+// only holds the changes since the one before it, and retention deletes
+// copies one by one, which would break the chain the later copies need.
+func validateIncremental(cfg *config.Config, db database.Database) error {
+	rule := db.GetRetention(cfg.Settings.Retention)
+	if rule.IsEnabled() {
+		return fmt.Errorf("incremental copies depend on the ones before them, retention would break the chain")
+	}
+
+	for _, name := range db.Storages {
+		if cfg.Storages[name].GetRetention(rule).IsEnabled() {
+			return fmt.Errorf("incremental copies depend on the ones before them, retention of storage '%s' would break the chain", name)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,45 @@
+package validation
+
+import (
+	"dumper/internal/domain/config"
+	"dumper/internal/domain/config/notification"
+	"errors"
+	"fmt"
+)
+
+func validateNotifications(cfg *config.Config) error {
+	for name, n := range cfg.Notifications {
+		if err := checkNotification(n); err != nil {
+			return fmt.Errorf("notification '%s' invalid: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+func checkNotification(n notification.Notification) error {
+	for _, on := range n.On {
+		if on != notification.OnSuccess && on != notification.OnFailure {
+			return fmt.Errorf("unknown 'on' value '%s', expected success | failure", on)
+		}
+	}
+
+	switch n.Type {
+	case "webhook", "slack", "mattermost":
+		if n.URL == "" {
+			return errors.New("url is required")
+		}
+	case "telegram":
+		if n.Token == "" || n.ChatID == "" {
+			return errors.New("token and chat_id are required")
+		}
+	case "email":
+		if n.Host == "" || n.From == "" || len(n.To) == 0 {
+			return errors.New("host, from and to are required")
+		}
+	default:
+		return fmt.Errorf("unsupported type '%s', expected webhook | slack | mattermost | telegram | email", n.Type)
+	}
+
+	return nil
+}
@@ -0,0 +1,47 @@
+package validation
+
+import (
+	"dumper/internal/domain/config/database"
+	"dumper/pkg/utils/cron"
+	"fmt"
+)
+
+func validatePostgres(db database.Database) error {
+	for _, section := range db.Options.Sections {
+		switch section {
+		case "pre-data", "data", "post-data":
+		default:
+			return fmt.Errorf("section must be pre-data, data or post-data, got '%s'", section)
+		}
+	}
+
+	if db.Options.Jobs < 0 {
+		return fmt.Errorf("jobs must not be negative, got %d", db.Options.Jobs)
+	}
+
+	if db.Options.Jobs > 0 && db.Format != "directory" {
+		return fmt.Errorf("jobs needs format 'directory', pg_dump runs in parallel only for it")
+	}
+
+	options := db.Options
+	if db.Format == "basebackup" &&
+		len(options.IncTables)+len(options.ExcTables)+len(options.IncSchemas)+len(options.ExcSchemas)+
+			len(options.ExcTableData)+len(options.Sections) > 0 {
+		return fmt.Errorf("format 'basebackup' copies the whole cluster, table, schema and section filters don't apply")
+	}
+
+	if options.WalSchedule != "" {
+		if options.WalDir == "" {
+			return fmt.Errorf("wal_schedule needs wal_dir, the directory archive_command copies the segments to")
+		}
+		if _, err := cron.Parse(options.WalSchedule); err != nil {
+			return fmt.Errorf("wal_schedule invalid: %w", err)
+		}
+	}
+
+	if size := options.WalSegSize; size != 0 && (size < 1 || size > 1024 || size&(size-1) != 0) {
+		return fmt.Errorf("wal_segment_size must be a power of 2 from 1 to 1024 MB, like initdb --wal-segsize")
+	}
+
+	return nil
+}
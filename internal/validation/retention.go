@@ -0,0 +1,43 @@
+package validation
+
+import (
+	"dumper/internal/domain/config"
+	"dumper/internal/domain/config/retention"
+	"fmt"
+)
+
+func validateRetention(cfg *config.Config) error {
+	check := func(scope string, rule *retention.Retention) error {
+		if rule == nil {
+			return nil
+		}
+
+		if rule.KeepLast < 0 || rule.KeepDaily < 0 || rule.KeepWeekly < 0 || rule.KeepMonthly < 0 {
+			return fmt.Errorf("%s retention invalid: keep values can't be negative", scope)
+		}
+
+		if _, err := rule.GetMaxAge(); err != nil {
+			return fmt.Errorf("%s retention invalid: %w", scope, err)
+		}
+
+		return nil
+	}
+
+	if err := check("settings", cfg.Settings.Retention); err != nil {
+		return err
+	}
+
+	for name, db := range cfg.Databases {
+		if err := check(fmt.Sprintf("database '%s'", name), db.Retention); err != nil {
+			return err
+		}
+	}
+
+	for name, s := range cfg.Storages {
+		if err := check(fmt.Sprintf("storage '%s'", name), s.Retention); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,26 @@
+package validation
+
+import (
+	"dumper/internal/domain/config"
+	"dumper/pkg/utils/cron"
+	"fmt"
+)
+
+func validateSchedule(cfg *config.Config) error {
+	if cfg.Settings.Schedule != "" {
+		if _, err := cron.Parse(cfg.Settings.Schedule); err != nil {
+			return fmt.Errorf("settings schedule invalid: %w", err)
+		}
+	}
+
+	for name, db := range cfg.Databases {
+		if db.Schedule == "" {
+			continue
+		}
+		if _, err := cron.Parse(db.Schedule); err != nil {
+			return fmt.Errorf("database '%s' schedule invalid: %w", name, err)
+		}
+	}
+
+	return nil
+}
@@ -6,6 +6,10 @@ import (
 )
 
 func validateServer(v *Validation, cfg *config.Config) error {
+	if err := validateHostKey(cfg.Settings.SSH.HostKeyChecking, nil); err != nil {
+		return fmt.Errorf("settings ssh invalid: %w", err)
+	}
+
 	for name, srv := range cfg.Servers {
 
 		srv.Name = srv.GetName()
@@ -13,8 +17,30 @@ func validateServer(v *Validation, cfg *config.Config) error {
 		srv.PrivateKey = srv.GetPrivateKey(&cfg.Settings.SSH.PrivateKey)
 		srv.Password = srv.GetPrivateKey(&cfg.Settings.SSH.Password)
 
+		if cfg.Settings.DumpLocation == "local-direct" {
+			if srv.Host == "" {
+				return fmt.Errorf("server '%s' invalid: host is required", name)
+			}
+			continue
+		}
+
+		if err := validateHostKey(srv.HostKeyChecking, srv.HostKeyFingerprints); err != nil {
+			return fmt.Errorf("server '%s' invalid: %w", name, err)
+		}
+
+		if err := validateJumpHosts(v, srv.JumpHosts); err != nil {
+			return fmt.Errorf("server '%s' invalid: %w", name, err)
+		}
+
 		if srv.PrivateKey == "" && srv.Password == "" {
-			return fmt.Errorf("server %s invalid: Private key or Password are required if not set in global", name)
+			if !srv.GetAgent(cfg.Settings.SSH.Agent) {
+				return fmt.Errorf("server %s invalid: Private key, Password or agent are required if not set in global", name)
+			}
+
+			if err := v.validator.StructExcept(srv, "PrivateKey", "Password"); err != nil {
+				return fmt.Errorf("server '%s' invalid: %w", name, HumanError(err))
+			}
+			continue
 		}
 
 		if err := v.validator.Struct(srv); err != nil {
@@ -40,10 +40,18 @@ func validateStorages(v *Validation, cfg *config.Config) error {
 				Username:   s.Username,
 				PrivateKey: s.PrivateKey,
 				Passphrase: s.Passphrase,
+				Password:   s.Password,
+				Agent:      s.IsAgent(),
 			}
 			if err := validate.Struct(sftp); err != nil {
 				return fmt.Errorf("storage '%s' (sftp) invalid: %w", name, HumanError(err))
 			}
+			if err := validateHostKey(s.HostKeyChecking, s.HostKeyFingerprints); err != nil {
+				return fmt.Errorf("storage '%s' (sftp) invalid: %w", name, err)
+			}
+			if err := validateJumpHosts(v, s.JumpHosts); err != nil {
+				return fmt.Errorf("storage '%s' (sftp) invalid: %w", name, err)
+			}
 
 		case "azure":
 			switch s.AuthType {
@@ -70,5 +70,21 @@ func (v *Validation) Handler(cfg *config.Config) error {
 		return err
 	}
 
+	if err := validateEncrypt(cfg); err != nil {
+		return err
+	}
+
+	if err := validateRetention(cfg); err != nil {
+		return err
+	}
+
+	if err := validateSchedule(cfg); err != nil {
+		return err
+	}
+
+	if err := validateNotifications(cfg); err != nil {
+		return err
+	}
+
 	return nil
 }
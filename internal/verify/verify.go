@@ -0,0 +1,114 @@
+package verify
+
+import (
+	"context"
+	"dumper/internal/domain/config"
+	configStorage "dumper/internal/domain/config/storage"
+	storageDomain "dumper/internal/domain/storage"
+	"dumper/internal/storage"
+	"dumper/pkg/logging"
+	"dumper/pkg/utils/checksum"
+	"dumper/pkg/utils/format"
+	"dumper/pkg/utils/stream"
+	"fmt"
+	"io"
+	"time"
+)
+
+type Verify struct {
+	ctx context.Context
+	cfg *config.Config
+}
+
+func NewApp(
+	ctx context.Context,
+	cfg *config.Config,
+) *Verify {
+	return &Verify{
+		ctx: ctx,
+		cfg: cfg,
+	}
+}
+
+// File re-hashes a stored backup and compares it with expected, or with the
+// sidecar stored next to it when expected is empty.
+func (v *Verify) File(storageName, fileName, expected string) error {
+	storageCfg, ok := v.cfg.Storages[storageName]
+	if !ok {
+		return fmt.Errorf("storage '%s' not found in configuration", storageName)
+	}
+	storageCfg.PrivateKey = storageCfg.GetPrivateKey(v.cfg.Settings.SSH.PrivateKey)
+
+	if expected == "" {
+		sum, err := v.sidecar(storageCfg, fileName)
+		if err != nil {
+			return err
+		}
+		expected = sum
+	}
+
+	logging.L(v.ctx).Info(
+		"Verifying backup",
+		logging.StringAttr("storage", storageName),
+		logging.StringAttr("file", fileName),
+	)
+	fmt.Printf("Verifying backup %s on %s\n", fileName, storageName)
+
+	storageConfig := storageDomain.Config{
+		Type:     storageCfg.Type,
+		DumpName: fileName,
+		Config:   storageCfg,
+	}
+
+	src, err := storage.NewApp(v.ctx, &storageConfig).Load()
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	verifyTimeNow := time.Now()
+	pr := stream.PipeReader(v.ctx, checksum.Verify(src, expected), storageConfig.FileSize)
+
+	size, err := io.Copy(io.Discard, pr)
+	if err != nil {
+		logging.L(v.ctx).Error(
+			"Backup verification failed",
+			logging.StringAttr("storage", storageName),
+			logging.StringAttr("file", fileName),
+			logging.ErrAttr(err),
+		)
+		return fmt.Errorf("[%s] %s: %w", storageName, fileName, err)
+	}
+
+	fmt.Printf("\r[%s] Checksum OK: %s [%s] in %.2f sec\n",
+		storageName, fileName, format.FormatBytes(size), time.Since(verifyTimeNow).Seconds())
+	logging.L(v.ctx).Info(
+		"Backup verified",
+		logging.StringAttr("storage", storageName),
+		logging.StringAttr("file", fileName),
+		logging.StringAttr("sha256", expected),
+	)
+
+	return nil
+}
+
+func (v *Verify) sidecar(storageCfg configStorage.Storage, fileName string) (string, error) {
+	sidecarName := checksum.SidecarName(fileName)
+
+	src, err := storage.NewApp(v.ctx, &storageDomain.Config{
+		Type:     storageCfg.Type,
+		DumpName: sidecarName,
+		Config:   storageCfg,
+	}).Load()
+	if err != nil {
+		return "", fmt.Errorf("failed to load checksum %s: %w", sidecarName, err)
+	}
+	defer src.Close()
+
+	data, err := io.ReadAll(io.LimitReader(src, 1024))
+	if err != nil {
+		return "", fmt.Errorf("failed to read checksum %s: %w", sidecarName, err)
+	}
+
+	return checksum.Parse(string(data))
+}
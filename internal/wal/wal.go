@@ -0,0 +1,311 @@
+package wal
+
+import (
+	"context"
+	"dumper/internal/connect"
+	connecterror "dumper/internal/connect/connect-error"
+	commandConfig "dumper/internal/domain/command-config"
+	"dumper/internal/domain/config"
+	dbConnect "dumper/internal/domain/config/db-connect"
+	"dumper/internal/domain/config/encrypt"
+	"dumper/internal/domain/config/storage"
+	storageDomain "dumper/internal/domain/storage"
+	storageApp "dumper/internal/storage"
+	"dumper/internal/upload"
+	"dumper/pkg/logging"
+	"dumper/pkg/utils/checksum"
+	"dumper/pkg/utils/runner"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// segmentPattern matches what archive_command leaves in the WAL archive:
+// segments, partial segments, backup labels and timeline histories, with
+// the extension of a compressing archive_command if there is one.
+var segmentPattern = regexp.MustCompile(
+	`^([0-9A-F]{24}(\.partial|\.[0-9A-F]{8}\.backup)?|[0-9A-F]{8}\.history)(\.[a-z0-9]+)?$`,
+)
+
+// fullSegment matches an uncompressed segment, the only kind of file whose
+// size is known in advance.
+var fullSegment = regexp.MustCompile(`^[0-9A-F]{24}$`)
+
+// settle is how long a file has to stay untouched before it is shipped, so
+// a segment archive_command is still copying is left for the next run.
+const settle = time.Minute
+
+const defaultSegSize = 16
+
+type segment struct {
+	name    string
+	size    int64
+	modTime time.Time
+}
+
+type WAL struct {
+	ctx       context.Context
+	cfg       *config.Config
+	dbConnect dbConnect.DBConnect
+	conn      *connect.Connect
+}
+
+func NewApp(
+	ctx context.Context,
+	cfg *config.Config,
+	dbConnect dbConnect.DBConnect,
+	conn *connect.Connect,
+) *WAL {
+	return &WAL{
+		ctx:       ctx,
+		cfg:       cfg,
+		dbConnect: dbConnect,
+		conn:      conn,
+	}
+}
+
+// Run ships the segments waiting in wal_dir to the storages. They keep
+// their own name, so restore_command can fetch them with %f. A segment a
+// storage already holds a complete copy of is not sent again, and once
+// every storage has it, it is removed from wal_dir unless wal_keep is set.
+// Files archive_command may still be writing are left for the next run.
+func (w *WAL) Run() error {
+	options := w.dbConnect.Database.GetOptions()
+	encryptCfg := w.dbConnect.Database.GetEncrypt(w.cfg.Settings.Encrypt)
+
+	if w.cfg.Settings.DumpLocation != "local-direct" {
+		if err := runner.RunWithCtx(w.ctx, w.conn.Connect); err != nil {
+			return &connecterror.ConnectError{Addr: w.dbConnect.Server.Host, Err: err}
+		}
+	}
+
+	segments, err := w.segments(options.WalDir)
+	if err != nil {
+		return fmt.Errorf("failed to list WAL archive %s: %w", options.WalDir, err)
+	}
+
+	if len(segments) == 0 {
+		logging.L(w.ctx).Info("No WAL segments to ship", logging.StringAttr("dir", options.WalDir))
+		return nil
+	}
+
+	stored, err := w.stored()
+	if err != nil {
+		return err
+	}
+
+	segSize := int64(options.WalSegSize)
+	if segSize <= 0 {
+		segSize = defaultSegSize
+	}
+	segSize <<= 20
+
+	now := time.Now()
+	shipped := 0
+	for _, seg := range segments {
+		if !ready(seg, segSize, now) {
+			logging.L(w.ctx).Info(
+				"WAL file not complete yet, left for the next run",
+				logging.StringAttr("file", seg.name),
+			)
+			continue
+		}
+
+		name := seg.name
+		if encryptCfg.IsEnabled() {
+			name += ".enc"
+		}
+
+		missing := make(map[string]storage.Storage)
+		for storageName, storageItem := range w.dbConnect.Storages {
+			if !holds(stored[storageName], name, seg.size, encryptCfg.IsEnabled()) {
+				missing[storageName] = storageItem
+			}
+		}
+
+		source := path.Join(options.WalDir, seg.name)
+
+		if len(missing) > 0 {
+			if err := w.upload(source, seg.size, missing, encryptCfg); err != nil {
+				return err
+			}
+			shipped++
+		}
+
+		if options.WalKeep {
+			continue
+		}
+
+		if err := w.remove(source); err != nil {
+			return fmt.Errorf("failed to remove shipped WAL segment %s: %w", source, err)
+		}
+	}
+
+	logging.L(w.ctx).Info(
+		"WAL segments shipped",
+		logging.StringAttr("db", w.dbConnect.Database.Key),
+		logging.IntAttr("count", shipped),
+	)
+	fmt.Printf("Shipped %d WAL segments of %s\n", shipped, w.dbConnect.Database.Key)
+
+	return nil
+}
+
+// upload sends one segment to the storages missing it, every one of them
+// has to take it before the segment may leave the server.
+func (w *WAL) upload(source string, size int64, storages map[string]storage.Storage, encryptCfg encrypt.Encrypt) error {
+	cmdConfig := &commandConfig.Config{
+		Database:            commandConfig.Database{Key: w.dbConnect.Database.Key},
+		Storages:            storages,
+		DumpLocation:        w.cfg.Settings.DumpLocation,
+		DumpName:            source,
+		FileSize:            size,
+		Encrypt:             encryptCfg,
+		MaxParallelDownload: w.cfg.Settings.MaxParallelDownload,
+		RetryConnect:        w.cfg.Settings.RetryConnect,
+	}
+
+	uploadApp := upload.New(w.ctx, w.conn, cmdConfig)
+	if err := runner.RunWithCtx(w.ctx, uploadApp.Uploading); err != nil {
+		return fmt.Errorf("failed to ship WAL segment %s: %w", source, err)
+	}
+
+	if len(cmdConfig.Uploaded) != len(storages) {
+		return fmt.Errorf("WAL segment %s reached %d of %d storages", source, len(cmdConfig.Uploaded), len(storages))
+	}
+
+	return nil
+}
+
+// ready reports whether seg is complete: it has not been touched for a
+// while and, when it is an uncompressed segment, it has the segment size.
+// Partial segments, backup labels, histories and compressed segments have
+// no fixed size.
+func ready(seg segment, segSize int64, now time.Time) bool {
+	if now.Sub(seg.modTime) < settle {
+		return false
+	}
+
+	return !fullSegment.MatchString(seg.name) || seg.size == segSize
+}
+
+// holds reports whether files, the listing of a storage, has a complete
+// copy of a segment of size bytes stored as name. A plain copy has the size
+// of the segment. An encrypted one is larger, so it counts once the
+// checksum sidecar written after a finished upload is there.
+func holds(files map[string]int64, name string, size int64, encrypted bool) bool {
+	stored, ok := files[name]
+	if !ok {
+		return false
+	}
+
+	if encrypted {
+		_, ok := files[checksum.SidecarName(name)]
+		return ok
+	}
+
+	return stored == size
+}
+
+// stored lists the files on every storage with their size, by storage name.
+func (w *WAL) stored() (map[string]map[string]int64, error) {
+	stored := make(map[string]map[string]int64, len(w.dbConnect.Storages))
+
+	for storageName, storageItem := range w.dbConnect.Storages {
+		files, err := storageApp.NewApp(w.ctx, &storageDomain.Config{
+			Type:   storageItem.Type,
+			Conn:   w.conn,
+			Config: storageItem,
+		}).List()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list storage %s: %w", storageName, err)
+		}
+
+		sizes := make(map[string]int64, len(files))
+		for _, file := range files {
+			sizes[file.Name] = file.Size
+		}
+		stored[storageName] = sizes
+	}
+
+	return stored, nil
+}
+
+func (w *WAL) segments(dir string) ([]segment, error) {
+	var out string
+
+	if w.cfg.Settings.DumpLocation == "local-direct" {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return nil, err
+		}
+
+		var lines []string
+		for _, entry := range entries {
+			info, err := entry.Info()
+			if err != nil || !info.Mode().IsRegular() {
+				continue
+			}
+			lines = append(lines, fmt.Sprintf("%s %d %d", entry.Name(), info.Size(), info.ModTime().Unix()))
+		}
+		out = strings.Join(lines, "\n")
+	} else {
+		var err error
+		out, err = w.conn.RunCommand(fmt.Sprintf("find %s -maxdepth 1 -type f -printf '%%f %%s %%T@\\n'", dir))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return parse(out), nil
+}
+
+func (w *WAL) remove(source string) error {
+	if w.cfg.Settings.DumpLocation == "local-direct" {
+		return os.Remove(filepath.FromSlash(source))
+	}
+
+	_, err := w.conn.RunCommand(fmt.Sprintf("rm -f %s", source))
+	return err
+}
+
+// parse reads "<name> <size> <mtime>" lines, the mtime in unix seconds,
+// and keeps the WAL files, oldest first.
+func parse(out string) []segment {
+	var segments []segment
+
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 3 || !segmentPattern.MatchString(fields[0]) {
+			continue
+		}
+
+		size, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+
+		mtime, err := strconv.ParseFloat(fields[2], 64)
+		if err != nil {
+			continue
+		}
+
+		segments = append(segments, segment{
+			name:    fields[0],
+			size:    size,
+			modTime: time.Unix(0, int64(mtime*float64(time.Second))),
+		})
+	}
+
+	sort.Slice(segments, func(i, j int) bool {
+		return segments[i].name < segments[j].name
+	})
+
+	return segments
+}
@@ -0,0 +1,83 @@
+package wal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	out := "000000010000000000000003 16777216 1760000000.5\n" +
+		"000000010000000000000001 16777216 1760000000\n" +
+		"000000010000000000000002.00000028.backup 340 1760000000\n" +
+		"00000002.history 42 1760000000\n" +
+		"000000010000000000000004.zst 1048576 1760000000\n" +
+		"000000010000000000000005.partial 16777216 1760000000\n" +
+		"archive_status 4096 1760000000\n" +
+		"000000010000000000000006.tmp-copy\n" +
+		"000000010000000000000007 16777216\n" +
+		"\n"
+
+	segments := parse(out)
+
+	var names []string
+	for _, seg := range segments {
+		names = append(names, seg.name)
+	}
+
+	assert.Equal(t, []string{
+		"000000010000000000000001",
+		"000000010000000000000002.00000028.backup",
+		"000000010000000000000003",
+		"000000010000000000000004.zst",
+		"000000010000000000000005.partial",
+		"00000002.history",
+	}, names)
+	assert.Equal(t, int64(16777216), segments[0].size)
+	assert.Equal(t, time.Unix(1760000000, 0), segments[0].modTime)
+	assert.Equal(t, time.Unix(1760000000, 5e8), segments[2].modTime)
+}
+
+func TestReady(t *testing.T) {
+	now := time.Unix(1760000000, 0)
+	old := now.Add(-time.Hour)
+	segSize := int64(16 << 20)
+
+	tests := []struct {
+		name string
+		seg  segment
+		want bool
+	}{
+		{name: "full segment", seg: segment{"000000010000000000000001", segSize, old}, want: true},
+		{name: "segment still copying", seg: segment{"000000010000000000000001", 4096, old}, want: false},
+		{name: "segment just written", seg: segment{"000000010000000000000001", segSize, now.Add(-time.Second)}, want: false},
+		{name: "partial", seg: segment{"000000010000000000000002.partial", 4096, old}, want: true},
+		{name: "backup label", seg: segment{"000000010000000000000002.00000028.backup", 340, old}, want: true},
+		{name: "history", seg: segment{"00000002.history", 42, old}, want: true},
+		{name: "compressed", seg: segment{"000000010000000000000003.zst", 1024, old}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ready(tt.seg, segSize, now))
+		})
+	}
+}
+
+func TestHolds(t *testing.T) {
+	files := map[string]int64{
+		"000000010000000000000001":            16 << 20,
+		"000000010000000000000002":            4096,
+		"000000010000000000000001.enc":        16<<20 + 512,
+		"000000010000000000000001.enc.sha256": 100,
+		"000000010000000000000002.enc":        4096,
+	}
+
+	assert.True(t, holds(files, "000000010000000000000001", 16<<20, false))
+	assert.False(t, holds(files, "000000010000000000000002", 16<<20, false), "partial upload")
+	assert.False(t, holds(files, "000000010000000000000003", 16<<20, false), "missing")
+
+	assert.True(t, holds(files, "000000010000000000000001.enc", 16<<20, true))
+	assert.False(t, holds(files, "000000010000000000000002.enc", 16<<20, true), "upload never finished")
+}
@@ -0,0 +1,292 @@
+package aesstream
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Stream layout, all integers big endian:
+//
+//	magic "DUMPERGCM" | version u8 | kdf u8 | kdf params 3*u32 | salt [16] |
+//	chunk size u32 | nonce prefix [7] | chunks...
+//
+// Every chunk is sealed with AES-256-GCM under the nonce
+// prefix || counter u32 || last u8, with the header as additional data. All
+// chunks but the last carry exactly chunk size bytes of plaintext, the last
+// one is shorter (possibly empty) and flagged, so truncation is detected.
+const (
+	Version = 1
+
+	DefaultChunkSize = 64 * 1024
+	maxChunkSize     = 16 * 1024 * 1024
+
+	saltSize        = 16
+	noncePrefixSize = 7
+)
+
+var magic = []byte("DUMPERGCM")
+
+const headerSize = 9 + 1 + 1 + 12 + saltSize + 4 + noncePrefixSize
+
+var (
+	ErrNotEncrypted = errors.New("not an encrypted dumper stream")
+	ErrTruncated    = errors.New("encrypted stream is truncated")
+	ErrAuth         = errors.New("wrong password or corrupted data")
+)
+
+// IsEncrypted reports whether data starts with the stream magic.
+func IsEncrypted(data []byte) bool {
+	return bytes.HasPrefix(data, magic)
+}
+
+type header struct {
+	kdf         kdfParams
+	salt        [saltSize]byte
+	chunkSize   uint32
+	noncePrefix [noncePrefixSize]byte
+}
+
+func (h *header) marshal() []byte {
+	buf := make([]byte, 0, headerSize)
+	buf = append(buf, magic...)
+	buf = append(buf, Version, h.kdf.id)
+	buf = binary.BigEndian.AppendUint32(buf, h.kdf.p1)
+	buf = binary.BigEndian.AppendUint32(buf, h.kdf.p2)
+	buf = binary.BigEndian.AppendUint32(buf, h.kdf.p3)
+	buf = append(buf, h.salt[:]...)
+	buf = binary.BigEndian.AppendUint32(buf, h.chunkSize)
+	buf = append(buf, h.noncePrefix[:]...)
+	return buf
+}
+
+func readHeader(src io.Reader) (*header, []byte, error) {
+	raw := make([]byte, headerSize)
+	if _, err := io.ReadFull(src, raw); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, nil, ErrNotEncrypted
+		}
+		return nil, nil, err
+	}
+
+	if !IsEncrypted(raw) {
+		return nil, nil, ErrNotEncrypted
+	}
+
+	off := len(magic)
+	if raw[off] != Version {
+		return nil, nil, fmt.Errorf("unsupported encryption version %d", raw[off])
+	}
+	off++
+
+	h := &header{}
+	h.kdf.id = raw[off]
+	off++
+	h.kdf.p1 = binary.BigEndian.Uint32(raw[off:])
+	h.kdf.p2 = binary.BigEndian.Uint32(raw[off+4:])
+	h.kdf.p3 = binary.BigEndian.Uint32(raw[off+8:])
+	off += 12
+	copy(h.salt[:], raw[off:])
+	off += saltSize
+	h.chunkSize = binary.BigEndian.Uint32(raw[off:])
+	off += 4
+	copy(h.noncePrefix[:], raw[off:])
+
+	if h.chunkSize == 0 || h.chunkSize > maxChunkSize {
+		return nil, nil, fmt.Errorf("invalid chunk size %d", h.chunkSize)
+	}
+
+	return h, raw, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+type nonce struct {
+	buf     [12]byte
+	counter uint32
+}
+
+func newNonce(prefix [noncePrefixSize]byte) *nonce {
+	n := &nonce{}
+	copy(n.buf[:], prefix[:])
+	return n
+}
+
+func (n *nonce) next(last bool) ([]byte, error) {
+	if n.counter == ^uint32(0) {
+		return nil, errors.New("encrypted stream too long")
+	}
+
+	binary.BigEndian.PutUint32(n.buf[noncePrefixSize:], n.counter)
+	n.buf[11] = 0
+	if last {
+		n.buf[11] = 1
+	}
+	n.counter++
+
+	return n.buf[:], nil
+}
+
+type encryptReader struct {
+	src     io.Reader
+	aead    cipher.AEAD
+	ad      []byte
+	nonce   *nonce
+	plain   []byte
+	pending []byte
+	done    bool
+}
+
+// Encrypt returns a reader producing the encrypted form of src. The key is
+// derived from password with the given KDF, scrypt when empty.
+func Encrypt(src io.Reader, password, kdf string) (io.Reader, error) {
+	return encrypt(src, password, kdf, DefaultChunkSize)
+}
+
+func encrypt(src io.Reader, password, kdf string, chunkSize int) (io.Reader, error) {
+	if password == "" {
+		return nil, errors.New("encryption password is empty")
+	}
+
+	params, err := defaultKDF(kdf)
+	if err != nil {
+		return nil, err
+	}
+
+	h := &header{kdf: params, chunkSize: uint32(chunkSize)}
+	if _, err := rand.Read(h.salt[:]); err != nil {
+		return nil, err
+	}
+	if _, err := rand.Read(h.noncePrefix[:]); err != nil {
+		return nil, err
+	}
+
+	key, err := params.derive(password, h.salt[:])
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	ad := h.marshal()
+
+	return &encryptReader{
+		src:     src,
+		aead:    aead,
+		ad:      ad,
+		nonce:   newNonce(h.noncePrefix),
+		plain:   make([]byte, chunkSize),
+		pending: append([]byte(nil), ad...),
+	}, nil
+}
+
+func (r *encryptReader) Read(p []byte) (int, error) {
+	for len(r.pending) == 0 {
+		if r.done {
+			return 0, io.EOF
+		}
+		if err := r.seal(); err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+	return n, nil
+}
+
+func (r *encryptReader) seal() error {
+	n, err := io.ReadFull(r.src, r.plain)
+
+	last := false
+	switch {
+	case err == nil:
+	case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		last = true
+	default:
+		return err
+	}
+
+	nonce, err := r.nonce.next(last)
+	if err != nil {
+		return err
+	}
+
+	r.pending = r.aead.Seal(r.pending[:0], nonce, r.plain[:n], r.ad)
+	r.done = last
+
+	return nil
+}
+
+type decryptReader struct {
+	src     io.Reader
+	aead    cipher.AEAD
+	ad      []byte
+	nonce   *nonce
+	sealed  []byte
+	pending []byte
+	done    bool
+}
+
+func (r *decryptReader) Read(p []byte) (int, error) {
+	for len(r.pending) == 0 {
+		if r.done {
+			return 0, io.EOF
+		}
+		if err := r.open(); err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+	return n, nil
+}
+
+func (r *decryptReader) open() error {
+	n, err := io.ReadFull(r.src, r.sealed)
+
+	last := false
+	switch {
+	case err == nil:
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		last = true
+	case errors.Is(err, io.EOF):
+		return ErrTruncated
+	default:
+		return err
+	}
+
+	if n < r.aead.Overhead() {
+		return ErrTruncated
+	}
+
+	nonce, err := r.nonce.next(last)
+	if err != nil {
+		return err
+	}
+
+	plain, err := r.aead.Open(r.sealed[:0], nonce, r.sealed[:n], r.ad)
+	if err != nil {
+		return ErrAuth
+	}
+
+	r.pending = plain
+	r.done = last
+
+	return nil
+}
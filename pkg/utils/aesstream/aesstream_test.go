@@ -0,0 +1,113 @@
+package aesstream_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"dumper/pkg/utils/aesstream"
+	"encoding/base64"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func encrypt(t *testing.T, plain []byte, password, kdf string) []byte {
+	t.Helper()
+
+	r, err := aesstream.Encrypt(bytes.NewReader(plain), password, kdf)
+	require.NoError(t, err)
+
+	data, err := io.ReadAll(r)
+	require.NoError(t, err)
+
+	return data
+}
+
+func decrypt(password string, data []byte) ([]byte, error) {
+	r, err := aesstream.Decrypt(bytes.NewReader(data), password)
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(r)
+}
+
+func TestRoundTrip(t *testing.T) {
+	sizes := []int{0, 1, aesstream.DefaultChunkSize, 2*aesstream.DefaultChunkSize + 17}
+
+	for _, size := range sizes {
+		plain := make([]byte, size)
+		_, _ = rand.Read(plain)
+
+		data := encrypt(t, plain, "secret", "")
+		assert.True(t, aesstream.IsEncrypted(data))
+
+		got, err := decrypt("secret", data)
+		require.NoError(t, err, "size %d", size)
+		assert.Equal(t, plain, got, "size %d", size)
+	}
+}
+
+func TestRoundTrip_Argon2(t *testing.T) {
+	data := encrypt(t, []byte("dump-data"), "secret", aesstream.KDFArgon2)
+
+	got, err := decrypt("secret", data)
+
+	require.NoError(t, err)
+	assert.Equal(t, "dump-data", string(got))
+}
+
+func TestEncrypt_UnknownKDF(t *testing.T) {
+	_, err := aesstream.Encrypt(strings.NewReader(""), "secret", "md5")
+
+	assert.Error(t, err)
+	assert.False(t, aesstream.IsKDF("md5"))
+	assert.True(t, aesstream.IsKDF(aesstream.KDFScrypt))
+}
+
+func TestDecrypt_WrongPassword(t *testing.T) {
+	data := encrypt(t, []byte("dump-data"), "secret", "")
+
+	_, err := decrypt("other", data)
+
+	assert.ErrorIs(t, err, aesstream.ErrAuth)
+}
+
+func TestDecrypt_Tampered(t *testing.T) {
+	data := encrypt(t, []byte("dump-data"), "secret", "")
+	data[len(data)-20] ^= 1
+
+	_, err := decrypt("secret", data)
+
+	assert.ErrorIs(t, err, aesstream.ErrAuth)
+}
+
+func TestDecrypt_Truncated(t *testing.T) {
+	plain := make([]byte, 2*aesstream.DefaultChunkSize+17)
+	data := encrypt(t, plain, "secret", "")
+
+	// Drop the final chunk: what remains is a valid prefix of full chunks.
+	cut := len(data) - (17 + 16)
+
+	_, err := decrypt("secret", data[:cut])
+
+	assert.ErrorIs(t, err, aesstream.ErrTruncated)
+}
+
+func TestDecrypt_NotEncrypted(t *testing.T) {
+	_, err := decrypt("secret", []byte("plain dump"))
+
+	assert.ErrorIs(t, err, aesstream.ErrNotEncrypted)
+}
+
+func TestDecrypt_OpenSSL(t *testing.T) {
+	// openssl enc -aes-256-cbc -salt -pbkdf2 -iter 100000 -k secret
+	data, err := base64.StdEncoding.DecodeString("U2FsdGVkX18qnfpCGoH3SjbS4ylphgd5i/1v6/Fr0cg+VE5Rzv4xk7F1ayzcCSC9")
+	require.NoError(t, err)
+
+	got, err := decrypt("secret", data)
+
+	require.NoError(t, err)
+	assert.Equal(t, "legacy dump contents\n", string(got))
+}
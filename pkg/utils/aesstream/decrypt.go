@@ -0,0 +1,150 @@
+package aesstream
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/sha256"
+	"errors"
+	"io"
+)
+
+// opensslMagic prefixes files written by
+// `openssl enc -aes-256-cbc -salt -pbkdf2 -iter 100000`, the format dumper
+// produced before the native stream.
+var opensslMagic = []byte("Salted__")
+
+const opensslIterations = 100000
+
+// Decrypt returns a reader producing the plaintext of src. Both the native
+// stream and legacy openssl encrypted dumps are accepted.
+func Decrypt(src io.Reader, password string) (io.Reader, error) {
+	br := bufio.NewReader(src)
+
+	prefix, err := br.Peek(len(magic))
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	if bytes.HasPrefix(prefix, opensslMagic) {
+		return decryptOpenSSL(br, password)
+	}
+
+	h, ad, err := readHeader(br)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := h.kdf.derive(password, h.salt[:])
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &decryptReader{
+		src:    br,
+		aead:   aead,
+		ad:     ad,
+		nonce:  newNonce(h.noncePrefix),
+		sealed: make([]byte, int(h.chunkSize)+aead.Overhead()),
+	}, nil
+}
+
+func decryptOpenSSL(src io.Reader, password string) (io.Reader, error) {
+	head := make([]byte, len(opensslMagic)+8)
+	if _, err := io.ReadFull(src, head); err != nil {
+		return nil, ErrTruncated
+	}
+
+	derived, err := pbkdf2.Key(sha256.New, password, head[len(opensslMagic):], opensslIterations, 32+aes.BlockSize)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(derived[:32])
+	if err != nil {
+		return nil, err
+	}
+
+	return &cbcReader{
+		src:  src,
+		mode: cipher.NewCBCDecrypter(block, derived[32:]),
+		buf:  make([]byte, 256*aes.BlockSize),
+	}, nil
+}
+
+// cbcReader decrypts AES-CBC holding back the last block until EOF so the
+// PKCS#7 padding can be stripped.
+type cbcReader struct {
+	src     io.Reader
+	mode    cipher.BlockMode
+	buf     []byte
+	held    []byte
+	pending []byte
+	done    bool
+}
+
+func (r *cbcReader) Read(p []byte) (int, error) {
+	for len(r.pending) == 0 {
+		if r.done {
+			return 0, io.EOF
+		}
+		if err := r.fill(); err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+	return n, nil
+}
+
+func (r *cbcReader) fill() error {
+	n, err := io.ReadFull(r.src, r.buf)
+
+	last := false
+	switch {
+	case err == nil:
+	case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		last = true
+	default:
+		return err
+	}
+
+	if n%aes.BlockSize != 0 {
+		return ErrTruncated
+	}
+
+	r.mode.CryptBlocks(r.buf[:n], r.buf[:n])
+	data := append(r.held, r.buf[:n]...)
+
+	if !last {
+		r.pending = data[:len(data)-aes.BlockSize]
+		r.held = append([]byte(nil), data[len(data)-aes.BlockSize:]...)
+		return nil
+	}
+
+	r.done = true
+	if len(data) == 0 {
+		return ErrTruncated
+	}
+
+	pad := int(data[len(data)-1])
+	if pad == 0 || pad > aes.BlockSize {
+		return ErrAuth
+	}
+	for _, b := range data[len(data)-pad:] {
+		if int(b) != pad {
+			return ErrAuth
+		}
+	}
+
+	r.pending = data[:len(data)-pad]
+	return nil
+}
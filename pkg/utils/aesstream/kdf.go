@@ -0,0 +1,59 @@
+package aesstream
+
+import (
+	"fmt"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	KDFScrypt = "scrypt"
+	KDFArgon2 = "argon2"
+
+	kdfScrypt uint8 = 1
+	kdfArgon2 uint8 = 2
+
+	keySize = 32
+)
+
+// kdfParams holds the KDF id and its three cost parameters: N, r and p for
+// scrypt; time, memory in KiB and threads for argon2id.
+type kdfParams struct {
+	id         uint8
+	p1, p2, p3 uint32
+}
+
+// IsKDF reports whether name is a supported key derivation function.
+func IsKDF(name string) bool {
+	_, err := defaultKDF(name)
+	return err == nil
+}
+
+func defaultKDF(name string) (kdfParams, error) {
+	switch name {
+	case "", KDFScrypt:
+		return kdfParams{id: kdfScrypt, p1: 1 << 15, p2: 8, p3: 1}, nil
+	case KDFArgon2:
+		return kdfParams{id: kdfArgon2, p1: 3, p2: 64 * 1024, p3: 4}, nil
+	default:
+		return kdfParams{}, fmt.Errorf("unsupported kdf %q", name)
+	}
+}
+
+func (k kdfParams) derive(password string, salt []byte) ([]byte, error) {
+	switch k.id {
+	case kdfScrypt:
+		if k.p1 > 1<<20 || k.p2 > 32 || k.p3 > 16 {
+			return nil, fmt.Errorf("scrypt parameters out of range")
+		}
+		return scrypt.Key([]byte(password), salt, int(k.p1), int(k.p2), int(k.p3), keySize)
+	case kdfArgon2:
+		if k.p1 == 0 || k.p1 > 64 || k.p2 > 4*1024*1024 || k.p3 == 0 || k.p3 > 255 {
+			return nil, fmt.Errorf("argon2 parameters out of range")
+		}
+		return argon2.IDKey([]byte(password), salt, k.p1, k.p2, uint8(k.p3), keySize), nil
+	default:
+		return nil, fmt.Errorf("unsupported kdf id %d", k.id)
+	}
+}
@@ -0,0 +1,107 @@
+// Package age encrypts dumps in the age v1 format (age-encryption.org/v1)
+// for X25519 recipients through filippo.io/age, so dumps encrypted here can
+// be read with the age tool and the other way around.
+package age
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"filippo.io/age"
+)
+
+const intro = "age-encryption.org/v1\n"
+
+var (
+	ErrNotEncrypted = errors.New("not an age encrypted stream")
+	ErrNoIdentity   = errors.New("no identity matched any of the recipients")
+	ErrAuth         = errors.New("age stream is corrupted or truncated")
+
+	errStopped = errors.New("age encryption stopped")
+)
+
+// IsEncrypted reports whether data starts with the age header.
+func IsEncrypted(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(intro))
+}
+
+// Encrypt returns a reader producing src encrypted to every recipient. The
+// encryption runs in a goroutine until src is drained, stop ends it early
+// when the reader is abandoned.
+func Encrypt(src io.Reader, recipients ...*Recipient) (reader io.Reader, stop func() error, err error) {
+	if len(recipients) == 0 {
+		return nil, nil, errors.New("no recipients")
+	}
+
+	list := make([]age.Recipient, 0, len(recipients))
+	for _, r := range recipients {
+		list = append(list, r.key)
+	}
+
+	pr, pw := io.Pipe()
+
+	// age writes the header as soon as it starts, so it has to run on the
+	// writing side of the pipe
+	go func() {
+		w, err := age.Encrypt(pw, list...)
+		if err != nil {
+			_ = pw.CloseWithError(fmt.Errorf("failed to start age encryption: %w", err))
+			return
+		}
+
+		if _, err := io.Copy(w, src); err != nil {
+			_ = pw.CloseWithError(err)
+			return
+		}
+		_ = pw.CloseWithError(w.Close())
+	}()
+
+	stop = func() error {
+		return pr.CloseWithError(errStopped)
+	}
+
+	return pr, stop, nil
+}
+
+// Decrypt returns a reader producing the plaintext of src, using whichever
+// identity matches one of its recipients.
+func Decrypt(src io.Reader, identities ...*Identity) (io.Reader, error) {
+	br := bufio.NewReader(src)
+
+	head, err := br.Peek(len(intro))
+	if err != nil || !IsEncrypted(head) {
+		return nil, ErrNotEncrypted
+	}
+
+	list := make([]age.Identity, 0, len(identities))
+	for _, i := range identities {
+		list = append(list, i.key)
+	}
+
+	r, err := age.Decrypt(br, list...)
+	if err != nil {
+		var noMatch *age.NoIdentityMatchError
+		if errors.As(err, &noMatch) {
+			return nil, ErrNoIdentity
+		}
+		return nil, fmt.Errorf("failed to read age header: %w", err)
+	}
+
+	return &decryptReader{src: r}, nil
+}
+
+// decryptReader reports a payload that fails authentication as ErrAuth.
+type decryptReader struct {
+	src io.Reader
+}
+
+func (r *decryptReader) Read(p []byte) (int, error) {
+	n, err := r.src.Read(p)
+	if err != nil && err != io.EOF {
+		err = fmt.Errorf("%w: %v", ErrAuth, err)
+	}
+	return n, err
+}
@@ -0,0 +1,226 @@
+package age_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"dumper/pkg/utils/age"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func encrypt(t *testing.T, plain []byte, recipients ...*age.Recipient) []byte {
+	t.Helper()
+
+	r, _, err := age.Encrypt(bytes.NewReader(plain), recipients...)
+	require.NoError(t, err)
+
+	data, err := io.ReadAll(r)
+	require.NoError(t, err)
+
+	return data
+}
+
+func decrypt(data []byte, identities ...*age.Identity) ([]byte, error) {
+	r, err := age.Decrypt(bytes.NewReader(data), identities...)
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(r)
+}
+
+func newIdentity(t *testing.T) *age.Identity {
+	t.Helper()
+
+	identity, err := age.GenerateIdentity()
+	require.NoError(t, err)
+
+	return identity
+}
+
+func TestKeys_RoundTrip(t *testing.T) {
+	identity := newIdentity(t)
+
+	assert.True(t, strings.HasPrefix(identity.String(), "AGE-SECRET-KEY-1"))
+	assert.True(t, strings.HasPrefix(identity.Recipient().String(), "age1"))
+
+	parsed, err := age.ParseIdentity(identity.String())
+	require.NoError(t, err)
+	assert.Equal(t, identity.Recipient().String(), parsed.Recipient().String())
+
+	recipient, err := age.ParseRecipient(identity.Recipient().String())
+	require.NoError(t, err)
+	assert.Equal(t, identity.Recipient().String(), recipient.String())
+}
+
+func TestParseRecipient_Invalid(t *testing.T) {
+	identity := newIdentity(t)
+
+	_, err := age.ParseRecipient(identity.String())
+	assert.Error(t, err)
+
+	_, err = age.ParseRecipient("age1invalid")
+	assert.Error(t, err)
+}
+
+func TestParseIdentities(t *testing.T) {
+	identity := newIdentity(t)
+	file := "# created: today\n# public key: " + identity.Recipient().String() + "\n\n" + identity.String() + "\n"
+
+	identities, err := age.ParseIdentities(strings.NewReader(file))
+
+	require.NoError(t, err)
+	require.Len(t, identities, 1)
+	assert.Equal(t, identity.String(), identities[0].String())
+
+	_, err = age.ParseIdentities(strings.NewReader("# empty\n"))
+	assert.Error(t, err)
+}
+
+func TestRoundTrip(t *testing.T) {
+	identity := newIdentity(t)
+	sizes := []int{0, 1, 64 * 1024, 2*64*1024 + 17}
+
+	for _, size := range sizes {
+		plain := make([]byte, size)
+		_, _ = rand.Read(plain)
+
+		data := encrypt(t, plain, identity.Recipient())
+		assert.True(t, age.IsEncrypted(data))
+
+		got, err := decrypt(data, identity)
+		require.NoError(t, err, "size %d", size)
+		assert.Equal(t, plain, got, "size %d", size)
+	}
+}
+
+// endless never runs out and counts the reads.
+type endless struct {
+	reads atomic.Int64
+}
+
+func (e *endless) Read(p []byte) (int, error) {
+	e.reads.Add(1)
+	return len(p), nil
+}
+
+func TestEncrypt_Stop(t *testing.T) {
+	src := &endless{}
+
+	r, stop, err := age.Encrypt(src, newIdentity(t).Recipient())
+	require.NoError(t, err)
+
+	_, err = io.ReadFull(r, make([]byte, 1024))
+	require.NoError(t, err)
+
+	require.NoError(t, stop())
+
+	// the goroutine may finish the read it is in, then leaves src alone
+	var last int64
+	require.Eventually(t, func() bool {
+		reads := src.reads.Load()
+		settled := reads == last
+		last = reads
+		return settled
+	}, time.Second, 50*time.Millisecond)
+
+	_, err = r.Read(make([]byte, 1))
+	assert.Error(t, err)
+}
+
+func TestRoundTrip_MultipleRecipients(t *testing.T) {
+	first, second := newIdentity(t), newIdentity(t)
+	data := encrypt(t, []byte("dump-data"), first.Recipient(), second.Recipient())
+
+	for _, identity := range []*age.Identity{first, second} {
+		got, err := decrypt(data, identity)
+		require.NoError(t, err)
+		assert.Equal(t, "dump-data", string(got))
+	}
+}
+
+func TestDecrypt_WrongIdentity(t *testing.T) {
+	data := encrypt(t, []byte("dump-data"), newIdentity(t).Recipient())
+
+	_, err := decrypt(data, newIdentity(t))
+
+	assert.ErrorIs(t, err, age.ErrNoIdentity)
+}
+
+func TestDecrypt_Tampered(t *testing.T) {
+	identity := newIdentity(t)
+	data := encrypt(t, []byte("dump-data"), identity.Recipient())
+	data[len(data)-1] ^= 1
+
+	_, err := decrypt(data, identity)
+
+	assert.ErrorIs(t, err, age.ErrAuth)
+}
+
+func TestDecrypt_Truncated(t *testing.T) {
+	identity := newIdentity(t)
+	data := encrypt(t, make([]byte, 2*64*1024+17), identity.Recipient())
+
+	_, err := decrypt(data[:len(data)-(17+16)], identity)
+
+	assert.Error(t, err)
+}
+
+func TestDecrypt_NotEncrypted(t *testing.T) {
+	_, err := decrypt([]byte("plain dump\n"), newIdentity(t))
+
+	assert.ErrorIs(t, err, age.ErrNotEncrypted)
+}
+
+// testdata was produced by the age and age-keygen tools:
+//
+//	age-keygen -o key.txt
+//	age -r age1dz4qv96v2q025awavtmaf5cy4ssk4aa6qgqmgga2y3l0qc2d33qslg2gcx -o plain.sql.age plain.sql
+const testdataRecipient = "age1dz4qv96v2q025awavtmaf5cy4ssk4aa6qgqmgga2y3l0qc2d33qslg2gcx"
+
+func TestReadIdentityFile_AgeKeygen(t *testing.T) {
+	identities, err := age.ReadIdentityFile(filepath.Join("testdata", "key.txt"))
+
+	require.NoError(t, err)
+	require.Len(t, identities, 1)
+	assert.Equal(t, testdataRecipient, identities[0].Recipient().String())
+}
+
+func TestDecrypt_AgeCLI(t *testing.T) {
+	identities, err := age.ReadIdentityFile(filepath.Join("testdata", "key.txt"))
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(filepath.Join("testdata", "plain.sql.age"))
+	require.NoError(t, err)
+	plain, err := os.ReadFile(filepath.Join("testdata", "plain.sql"))
+	require.NoError(t, err)
+
+	got, err := decrypt(data, identities...)
+	require.NoError(t, err)
+	assert.Equal(t, plain, got)
+}
+
+func TestEncrypt_AgeCLI(t *testing.T) {
+	bin, err := exec.LookPath("age")
+	if err != nil {
+		t.Skip("age is not installed")
+	}
+
+	recipient, err := age.ParseRecipient(testdataRecipient)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "dump.sql.age")
+	require.NoError(t, os.WriteFile(path, encrypt(t, []byte("dump-data"), recipient), 0600))
+
+	out, err := exec.Command(bin, "-d", "-i", filepath.Join("testdata", "key.txt"), path).Output()
+	require.NoError(t, err)
+	assert.Equal(t, "dump-data", string(out))
+}
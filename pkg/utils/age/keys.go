@@ -0,0 +1,124 @@
+package age
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"filippo.io/age"
+)
+
+// Recipient is an X25519 public key in the age1... form.
+type Recipient struct {
+	key *age.X25519Recipient
+}
+
+// Identity is an X25519 private key in the AGE-SECRET-KEY-1... form.
+type Identity struct {
+	key *age.X25519Identity
+}
+
+func ParseRecipient(s string) (*Recipient, error) {
+	key, err := age.ParseX25519Recipient(strings.TrimSpace(s))
+	if err != nil {
+		return nil, fmt.Errorf("malformed recipient %q: %v", s, err)
+	}
+
+	return &Recipient{key: key}, nil
+}
+
+// ParseRecipients parses every recipient in list.
+func ParseRecipients(list []string) ([]*Recipient, error) {
+	recipients := make([]*Recipient, 0, len(list))
+	for _, s := range list {
+		r, err := ParseRecipient(s)
+		if err != nil {
+			return nil, err
+		}
+		recipients = append(recipients, r)
+	}
+
+	if len(recipients) == 0 {
+		return nil, errors.New("no recipients")
+	}
+
+	return recipients, nil
+}
+
+func (r *Recipient) String() string {
+	return r.key.String()
+}
+
+func GenerateIdentity() (*Identity, error) {
+	key, err := age.GenerateX25519Identity()
+	if err != nil {
+		return nil, err
+	}
+	return &Identity{key: key}, nil
+}
+
+func ParseIdentity(s string) (*Identity, error) {
+	key, err := age.ParseX25519Identity(strings.TrimSpace(s))
+	if err != nil {
+		return nil, fmt.Errorf("malformed secret key: %v", err)
+	}
+
+	return &Identity{key: key}, nil
+}
+
+// ParseIdentities reads an age identity file: one key per line, blank lines
+// and # comments are skipped.
+func ParseIdentities(r io.Reader) ([]*Identity, error) {
+	var identities []*Identity
+
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		identity, err := ParseIdentity(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", n, err)
+		}
+		identities = append(identities, identity)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(identities) == 0 {
+		return nil, errors.New("no identities found")
+	}
+
+	return identities, nil
+}
+
+// ReadIdentityFile parses the age identity file at path.
+func ReadIdentityFile(path string) ([]*Identity, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	identities, err := ParseIdentities(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read identity file %s: %w", path, err)
+	}
+
+	return identities, nil
+}
+
+func (i *Identity) Recipient() *Recipient {
+	return &Recipient{key: i.key.Recipient()}
+}
+
+func (i *Identity) String() string {
+	return i.key.String()
+}
@@ -0,0 +1 @@
+CREATE TABLE app (id int);
@@ -0,0 +1,125 @@
+package checksum
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const Suffix = ".sha256"
+
+// SidecarName is the name of the file holding the checksum of dumpName.
+func SidecarName(dumpName string) string {
+	return dumpName + Suffix
+}
+
+// Sidecar renders a checksum in the sha256sum format so stored copies can
+// also be checked with `sha256sum -c`.
+func Sidecar(sum, dumpName string) string {
+	return fmt.Sprintf("%s  %s\n", sum, filepath.Base(dumpName))
+}
+
+// Parse extracts the digest from sha256sum output or a sidecar file.
+func Parse(output string) (string, error) {
+	fields := strings.Fields(output)
+	if len(fields) == 0 {
+		return "", fmt.Errorf("empty checksum output")
+	}
+
+	sum := strings.ToLower(fields[0])
+	if _, err := hex.DecodeString(sum); err != nil || len(sum) != sha256.Size*2 {
+		return "", fmt.Errorf("invalid sha256 checksum: %q", fields[0])
+	}
+
+	return sum, nil
+}
+
+func Sum(r io.Reader) (string, error) {
+	h := sha256.New()
+	if _, err := io.Copy(h, r); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func File(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	return Sum(file)
+}
+
+type verifier struct {
+	src      io.Reader
+	hash     hash.Hash
+	expected string
+}
+
+func (v *verifier) Read(p []byte) (int, error) {
+	n, err := v.src.Read(p)
+	if n > 0 {
+		v.hash.Write(p[:n])
+	}
+
+	if err == io.EOF {
+		if sum := hex.EncodeToString(v.hash.Sum(nil)); sum != v.expected {
+			return n, fmt.Errorf("checksum mismatch: expected %s, got %s", v.expected, sum)
+		}
+	}
+
+	return n, err
+}
+
+// Verify hashes everything read through it and turns the final EOF into an
+// error when the digest differs from expected. An empty expected sum leaves
+// the reader untouched.
+func Verify(src io.Reader, expected string) io.Reader {
+	if expected == "" {
+		return src
+	}
+
+	return &verifier{
+		src:      src,
+		hash:     sha256.New(),
+		expected: strings.ToLower(expected),
+	}
+}
+
+type tracker struct {
+	src  io.Reader
+	hash hash.Hash
+	done func(sum string)
+}
+
+func (t *tracker) Read(p []byte) (int, error) {
+	n, err := t.src.Read(p)
+	if n > 0 {
+		t.hash.Write(p[:n])
+	}
+
+	if err == io.EOF && t.done != nil {
+		t.done(hex.EncodeToString(t.hash.Sum(nil)))
+		t.done = nil
+	}
+
+	return n, err
+}
+
+// Track hashes everything read through it and hands the digest to done once
+// src reaches EOF.
+func Track(src io.Reader, done func(sum string)) io.Reader {
+	return &tracker{
+		src:  src,
+		hash: sha256.New(),
+		done: done,
+	}
+}
@@ -0,0 +1,100 @@
+package checksum_test
+
+import (
+	"dumper/pkg/utils/checksum"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSum(t *testing.T) {
+	sum, err := checksum.Sum(strings.NewReader(""))
+
+	require.NoError(t, err)
+	assert.Equal(t, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", sum)
+}
+
+func TestFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dump.sql")
+	require.NoError(t, os.WriteFile(path, []byte("dump-data"), 0600))
+
+	fileSum, err := checksum.File(path)
+	require.NoError(t, err)
+
+	readerSum, err := checksum.Sum(strings.NewReader("dump-data"))
+	require.NoError(t, err)
+
+	assert.Equal(t, readerSum, fileSum)
+}
+
+func TestParse(t *testing.T) {
+	sum := strings.Repeat("ab", 32)
+
+	tests := []struct {
+		name    string
+		output  string
+		want    string
+		wantErr bool
+	}{
+		{name: "sha256sum output", output: sum + "  /root/dump/db.sql\n", want: sum},
+		{name: "upper case", output: strings.ToUpper(sum), want: sum},
+		{name: "empty", output: "  \n", wantErr: true},
+		{name: "not hex", output: strings.Repeat("zz", 32), wantErr: true},
+		{name: "wrong length", output: "abcd  db.sql", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := checksum.Parse(tt.output)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSidecar(t *testing.T) {
+	sum := strings.Repeat("ab", 32)
+
+	assert.Equal(t, "/dumps/db.sql.sha256", checksum.SidecarName("/dumps/db.sql"))
+	assert.Equal(t, sum+"  db.sql\n", checksum.Sidecar(sum, "/dumps/db.sql"))
+
+	parsed, err := checksum.Parse(checksum.Sidecar(sum, "/dumps/db.sql"))
+	require.NoError(t, err)
+	assert.Equal(t, sum, parsed)
+}
+
+func TestVerify(t *testing.T) {
+	sum, err := checksum.Sum(strings.NewReader("dump-data"))
+	require.NoError(t, err)
+
+	data, err := io.ReadAll(checksum.Verify(strings.NewReader("dump-data"), sum))
+	require.NoError(t, err)
+	assert.Equal(t, "dump-data", string(data))
+
+	_, err = io.ReadAll(checksum.Verify(strings.NewReader("dump-dat4"), sum))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "checksum mismatch")
+
+	data, err = io.ReadAll(checksum.Verify(strings.NewReader("anything"), ""))
+	require.NoError(t, err)
+	assert.Equal(t, "anything", string(data))
+}
+
+func TestTrack(t *testing.T) {
+	var sum string
+	r := checksum.Track(strings.NewReader(""), func(s string) { sum = s })
+
+	_, err := io.ReadAll(r)
+
+	require.NoError(t, err)
+	assert.Equal(t, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", sum)
+}
@@ -0,0 +1,143 @@
+package compress
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+)
+
+type format struct {
+	algo  string
+	magic []byte
+	exts  map[string]string
+}
+
+// formats lists what restore understands, the extensions map to the name
+// the dump gets once decompressed.
+var formats = []format{
+	{"gzip", []byte{0x1f, 0x8b}, map[string]string{".gz": "", ".tgz": ".tar"}},
+	{"zstd", []byte{0x28, 0xb5, 0x2f, 0xfd}, map[string]string{".zst": "", ".tzst": ".tar"}},
+	{"xz", []byte{0xfd, '7', 'z', 'X', 'Z', 0x00}, map[string]string{".xz": "", ".txz": ".tar"}},
+	{"lz4", []byte{0x04, 0x22, 0x4d, 0x18}, map[string]string{".lz4": ""}},
+}
+
+// Detect peeks at the head of src and returns the algorithm that produced
+// it, or "" for anything not compressed.
+func Detect(src *bufio.Reader) string {
+	for _, f := range formats {
+		magic, err := src.Peek(len(f.magic))
+		if err == nil && bytes.Equal(magic, f.magic) {
+			return f.algo
+		}
+	}
+	return ""
+}
+
+// TrimExt drops the extension algo adds to name.
+func TrimExt(name, algo string) string {
+	for _, f := range formats {
+		if f.algo != algo {
+			continue
+		}
+		for ext, replace := range f.exts {
+			if base, ok := strings.CutSuffix(name, ext); ok {
+				return base + replace
+			}
+		}
+	}
+	return name
+}
+
+// NewReader decompresses src. gzip is handled in Go, the other algorithms
+// need their tool installed locally.
+func NewReader(src io.Reader, algo string) (io.ReadCloser, error) {
+	if algo == "gzip" {
+		return gzip.NewReader(src)
+	}
+
+	path, err := exec.LookPath(algo)
+	if err != nil {
+		return nil, fmt.Errorf("%s is required to decompress the dump: %v", algo, err)
+	}
+
+	cmd := exec.Command(path, "-d", "-c")
+	cmd.Stdin = src
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	return &cmdReader{ReadCloser: stdout, cmd: cmd, stderr: &stderr}, nil
+}
+
+// NewGzip compresses src on the fly, level 0 picks the default.
+func NewGzip(src io.Reader, level int) (io.ReadCloser, error) {
+	if level == 0 {
+		level = gzip.DefaultCompression
+	}
+
+	pr, pw := io.Pipe()
+	gz, err := gzip.NewWriterLevel(pw, level)
+	if err != nil {
+		return nil, err
+	}
+
+	go func() {
+		_, err := io.Copy(gz, src)
+		if closeErr := gz.Close(); err == nil {
+			err = closeErr
+		}
+		_ = pw.CloseWithError(err)
+	}()
+
+	return pr, nil
+}
+
+// cmdReader reports a failing decompressor at the end of the stream rather
+// than passing a truncated dump on as complete.
+type cmdReader struct {
+	io.ReadCloser
+	cmd    *exec.Cmd
+	stderr *bytes.Buffer
+	done   bool
+	err    error
+}
+
+func (r *cmdReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	if err == io.EOF {
+		if waitErr := r.wait(); waitErr != nil {
+			return n, waitErr
+		}
+	}
+	return n, err
+}
+
+func (r *cmdReader) Close() error {
+	_ = r.ReadCloser.Close()
+	return r.wait()
+}
+
+func (r *cmdReader) wait() error {
+	if r.done {
+		return r.err
+	}
+	r.done = true
+
+	if err := r.cmd.Wait(); err != nil {
+		r.err = fmt.Errorf("%s failed: %v: %s", r.cmd.Path, err, strings.TrimSpace(r.stderr.String()))
+	}
+	return r.err
+}
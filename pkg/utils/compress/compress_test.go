@@ -0,0 +1,99 @@
+package compress_test
+
+import (
+	"bufio"
+	"bytes"
+	"dumper/pkg/utils/compress"
+	"io"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []byte
+		want  string
+	}{
+		{"gzip", []byte{0x1f, 0x8b, 0x08, 0x00}, "gzip"},
+		{"zstd", []byte{0x28, 0xb5, 0x2f, 0xfd, 0x00}, "zstd"},
+		{"xz", []byte{0xfd, '7', 'z', 'X', 'Z', 0x00, 0x00}, "xz"},
+		{"lz4", []byte{0x04, 0x22, 0x4d, 0x18, 0x00}, "lz4"},
+		{"plain", []byte("SELECT 1;"), ""},
+		{"short", []byte{0x28}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, compress.Detect(bufio.NewReader(bytes.NewReader(tt.input))))
+		})
+	}
+}
+
+func TestTrimExt(t *testing.T) {
+	assert.Equal(t, "db.sql", compress.TrimExt("db.sql.gz", "gzip"))
+	assert.Equal(t, "db.tar", compress.TrimExt("db.tgz", "gzip"))
+	assert.Equal(t, "db.tar", compress.TrimExt("db.tar.zst", "zstd"))
+	assert.Equal(t, "db.archive", compress.TrimExt("db.archive.xz", "xz"))
+	assert.Equal(t, "db.rdb", compress.TrimExt("db.rdb.lz4", "lz4"))
+	assert.Equal(t, "db.sql.zst", compress.TrimExt("db.sql.zst", "gzip"))
+}
+
+func TestNewGzip_RoundTrip(t *testing.T) {
+	data := strings.Repeat("INSERT INTO t VALUES (1);\n", 1000)
+
+	gz, err := compress.NewGzip(strings.NewReader(data), 9)
+	require.NoError(t, err)
+	packed, err := io.ReadAll(gz)
+	require.NoError(t, err)
+	assert.Less(t, len(packed), len(data))
+
+	src := bufio.NewReader(bytes.NewReader(packed))
+	require.Equal(t, "gzip", compress.Detect(src))
+
+	reader, err := compress.NewReader(src, "gzip")
+	require.NoError(t, err)
+	out, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, data, string(out))
+}
+
+func TestNewReader_Tool(t *testing.T) {
+	for _, algo := range []string{"zstd", "xz", "lz4"} {
+		t.Run(algo, func(t *testing.T) {
+			if _, err := exec.LookPath(algo); err != nil {
+				t.Skipf("%s is not installed", algo)
+			}
+
+			packed, err := exec.Command("sh", "-c", "printf 'SELECT 1;' | "+algo+" -c").Output()
+			require.NoError(t, err)
+
+			src := bufio.NewReader(bytes.NewReader(packed))
+			require.Equal(t, algo, compress.Detect(src))
+
+			reader, err := compress.NewReader(src, algo)
+			require.NoError(t, err)
+			out, err := io.ReadAll(reader)
+			require.NoError(t, err)
+			require.NoError(t, reader.Close())
+			assert.Equal(t, "SELECT 1;", string(out))
+		})
+	}
+}
+
+func TestNewReader_Corrupt(t *testing.T) {
+	if _, err := exec.LookPath("zstd"); err != nil {
+		t.Skip("zstd is not installed")
+	}
+
+	src := bufio.NewReader(bytes.NewReader([]byte{0x28, 0xb5, 0x2f, 0xfd, 0x01, 0x02, 0x03}))
+	reader, err := compress.NewReader(src, "zstd")
+	require.NoError(t, err)
+
+	_, err = io.ReadAll(reader)
+	assert.ErrorContains(t, err, "zstd failed")
+}
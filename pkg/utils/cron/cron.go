@@ -0,0 +1,219 @@
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule is a parsed standard 5-field cron expression:
+// minute hour day-of-month month day-of-week.
+type Schedule struct {
+	minute  uint64
+	hour    uint64
+	dom     uint64
+	month   uint64
+	dow     uint64
+	anyDom  bool
+	anyDow  bool
+	literal string
+}
+
+type bounds struct {
+	min, max int
+	names    map[string]int
+}
+
+var (
+	minuteBounds = bounds{min: 0, max: 59}
+	hourBounds   = bounds{min: 0, max: 23}
+	domBounds    = bounds{min: 1, max: 31}
+	monthBounds  = bounds{min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	dowBounds = bounds{min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+var descriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+func Parse(expr string) (*Schedule, error) {
+	literal := strings.TrimSpace(expr)
+
+	spec := literal
+	if strings.HasPrefix(spec, "@") {
+		d, ok := descriptors[strings.ToLower(spec)]
+		if !ok {
+			return nil, fmt.Errorf("unknown cron descriptor %q", spec)
+		}
+		spec = d
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron expression %q must have 5 fields, got %d", expr, len(fields))
+	}
+
+	s := &Schedule{
+		anyDom:  fields[2] == "*" || fields[2] == "?",
+		anyDow:  fields[4] == "*" || fields[4] == "?",
+		literal: literal,
+	}
+
+	var err error
+	if s.minute, err = parseField(fields[0], minuteBounds); err != nil {
+		return nil, fmt.Errorf("cron minute: %w", err)
+	}
+	if s.hour, err = parseField(fields[1], hourBounds); err != nil {
+		return nil, fmt.Errorf("cron hour: %w", err)
+	}
+	if s.dom, err = parseField(fields[2], domBounds); err != nil {
+		return nil, fmt.Errorf("cron day of month: %w", err)
+	}
+	if s.month, err = parseField(fields[3], monthBounds); err != nil {
+		return nil, fmt.Errorf("cron month: %w", err)
+	}
+	if s.dow, err = parseField(fields[4], dowBounds); err != nil {
+		return nil, fmt.Errorf("cron day of week: %w", err)
+	}
+
+	// 7 is an alias for Sunday
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+
+	return s, nil
+}
+
+func (s *Schedule) String() string {
+	return s.literal
+}
+
+// Next returns the first time strictly after t that matches the schedule,
+// or the zero time if nothing matches within five years.
+func (s *Schedule) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			continue
+		}
+
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+
+		return t
+	}
+
+	return time.Time{}
+}
+
+// dayMatches follows the classic cron rule: when both day fields are
+// restricted, a day matching either of them fires.
+func (s *Schedule) dayMatches(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+
+	switch {
+	case s.anyDom && s.anyDow:
+		return true
+	case s.anyDom:
+		return dowMatch
+	case s.anyDow:
+		return domMatch
+	default:
+		return domMatch || dowMatch
+	}
+}
+
+func parseField(field string, b bounds) (uint64, error) {
+	var bits uint64
+
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepPart)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step %q", stepPart)
+			}
+			step = n
+		}
+
+		var lo, hi int
+		switch {
+		case rangePart == "*" || rangePart == "?":
+			lo, hi = b.min, b.max
+		case strings.Contains(rangePart, "-"):
+			from, to, _ := strings.Cut(rangePart, "-")
+			var err error
+			if lo, err = parseValue(from, b); err != nil {
+				return 0, err
+			}
+			if hi, err = parseValue(to, b); err != nil {
+				return 0, err
+			}
+			if lo > hi {
+				return 0, fmt.Errorf("invalid range %q", rangePart)
+			}
+		default:
+			var err error
+			if lo, err = parseValue(rangePart, b); err != nil {
+				return 0, err
+			}
+			hi = lo
+			if hasStep {
+				hi = b.max
+			}
+		}
+
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+
+	return bits, nil
+}
+
+func parseValue(value string, b bounds) (int, error) {
+	if n, ok := b.names[strings.ToLower(value)]; ok {
+		return n, nil
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", value)
+	}
+	if n < b.min || n > b.max {
+		return 0, fmt.Errorf("value %d out of range [%d-%d]", n, b.min, b.max)
+	}
+
+	return n, nil
+}
@@ -0,0 +1,71 @@
+package cron_test
+
+import (
+	"dumper/pkg/utils/cron"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 2025-06-30 is a Monday
+var from = time.Date(2025, 6, 30, 12, 30, 45, 0, time.UTC)
+
+func TestSchedule_Next(t *testing.T) {
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{expr: "* * * * *", want: time.Date(2025, 6, 30, 12, 31, 0, 0, time.UTC)},
+		{expr: "*/15 * * * *", want: time.Date(2025, 6, 30, 12, 45, 0, 0, time.UTC)},
+		{expr: "0 3 * * *", want: time.Date(2025, 7, 1, 3, 0, 0, 0, time.UTC)},
+		{expr: "30 12 * * *", want: time.Date(2025, 7, 1, 12, 30, 0, 0, time.UTC)},
+		{expr: "0 9-17/4 * * *", want: time.Date(2025, 6, 30, 13, 0, 0, 0, time.UTC)},
+		{expr: "0 0 * * sat,sun", want: time.Date(2025, 7, 5, 0, 0, 0, 0, time.UTC)},
+		{expr: "0 0 * * 7", want: time.Date(2025, 7, 6, 0, 0, 0, 0, time.UTC)},
+		{expr: "0 0 1 * *", want: time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)},
+		{expr: "0 0 31 * *", want: time.Date(2025, 7, 31, 0, 0, 0, 0, time.UTC)},
+		{expr: "0 0 29 feb *", want: time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{expr: "0 0 15 * fri", want: time.Date(2025, 7, 4, 0, 0, 0, 0, time.UTC)},
+		{expr: "@hourly", want: time.Date(2025, 6, 30, 13, 0, 0, 0, time.UTC)},
+		{expr: "@weekly", want: time.Date(2025, 7, 6, 0, 0, 0, 0, time.UTC)},
+		{expr: "@yearly", want: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			schedule, err := cron.Parse(tt.expr)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, schedule.Next(from))
+		})
+	}
+}
+
+func TestSchedule_NextNeverMatches(t *testing.T) {
+	schedule, err := cron.Parse("0 0 31 feb *")
+	require.NoError(t, err)
+
+	assert.True(t, schedule.Next(from).IsZero())
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+		"@often",
+	} {
+		t.Run(expr, func(t *testing.T) {
+			_, err := cron.Parse(expr)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -1,58 +1,88 @@
 package mapping
 
 type DriverInfo struct {
-	DefaultCommand string
-	DefaultPort    string
-	Formats        map[string]struct{}
-	Overrides      map[string]string
+	DefaultCommand   string
+	DefaultPort      string
+	Formats          map[string]struct{}
+	StreamFormats    map[string]struct{}
+	Overrides        map[string]string
+	RestoreCommand   string
+	RestoreOverrides map[string]string
+	Discover         bool
 }
 
 var dbDrivers = map[string]DriverInfo{
 	"mariadb": {
-		DefaultCommand: "mariadb-dump",
-		DefaultPort:    "3306",
-		Formats:        map[string]struct{}{"sql": {}},
+		DefaultCommand:   "mariadb-dump",
+		DefaultPort:      "3306",
+		Formats:          map[string]struct{}{"sql": {}, "mydumper": {}, "mariabackup": {}},
+		StreamFormats:    map[string]struct{}{"sql": {}, "mariabackup": {}},
+		Overrides:        map[string]string{"mydumper": "mydumper", "mariabackup": "mariabackup"},
+		RestoreCommand:   "mariadb",
+		RestoreOverrides: map[string]string{"mydumper": "myloader"},
+		Discover:         true,
 	},
 	"mongo": {
 		DefaultCommand: "mongodump",
 		DefaultPort:    "27015",
 		Formats:        map[string]struct{}{"bson": {}, "archive": {}},
+		StreamFormats:  map[string]struct{}{"archive": {}},
+		RestoreCommand: "mongorestore",
+		Discover:       true,
 	},
 	"mssql": {
-		DefaultCommand: "sqlcmd",
-		DefaultPort:    "27015",
-		Formats:        map[string]struct{}{"bac": {}, "bacpac": {}},
-		Overrides:      map[string]string{"bacpac": "sqlpackage"},
+		DefaultCommand:   "sqlcmd",
+		DefaultPort:      "27015",
+		Formats:          map[string]struct{}{"bac": {}, "bacpac": {}},
+		Overrides:        map[string]string{"bacpac": "sqlpackage"},
+		RestoreCommand:   "sqlcmd",
+		RestoreOverrides: map[string]string{"bacpac": "sqlpackage"},
 	},
 	"mysql": {
-		DefaultCommand: "mysqldump",
-		DefaultPort:    "3306",
-		Formats:        map[string]struct{}{"sql": {}},
+		DefaultCommand:   "mysqldump",
+		DefaultPort:      "3306",
+		Formats:          map[string]struct{}{"sql": {}, "mydumper": {}, "xtrabackup": {}},
+		StreamFormats:    map[string]struct{}{"sql": {}, "xtrabackup": {}},
+		Overrides:        map[string]string{"mydumper": "mydumper", "xtrabackup": "xtrabackup"},
+		RestoreCommand:   "mysql",
+		RestoreOverrides: map[string]string{"mydumper": "myloader"},
+		Discover:         true,
 	},
 	"psql": {
-		DefaultCommand: "pg_dump",
-		DefaultPort:    "5432",
-		Formats:        map[string]struct{}{"plain": {}, "dump": {}, "tar": {}},
+		DefaultCommand:   "pg_dump",
+		DefaultPort:      "5432",
+		Formats:          map[string]struct{}{"plain": {}, "dump": {}, "tar": {}, "directory": {}, "basebackup": {}},
+		StreamFormats:    map[string]struct{}{"plain": {}, "dump": {}, "tar": {}},
+		Overrides:        map[string]string{"basebackup": "pg_basebackup"},
+		RestoreCommand:   "psql",
+		RestoreOverrides: map[string]string{"dump": "pg_restore", "tar": "pg_restore", "directory": "pg_restore"},
+		Discover:         true,
 	},
 	"redis": {
 		DefaultCommand: "redis-cli",
 		DefaultPort:    "6379",
 		Formats:        map[string]struct{}{"rdb": {}},
+		StreamFormats:  map[string]struct{}{"rdb": {}},
+		RestoreCommand: "redis-cli",
 	},
 	"sqlite": {
 		DefaultCommand: "sqlite3",
 		DefaultPort:    "",
 		Formats:        map[string]struct{}{"sql": {}},
+		StreamFormats:  map[string]struct{}{"sql": {}},
+		RestoreCommand: "sqlite3",
 	},
 	"neo4j": {
 		DefaultCommand: "neo4j-admin",
 		DefaultPort:    "",
 		Formats:        map[string]struct{}{"dump": {}},
+		RestoreCommand: "neo4j-admin",
 	},
 	"dynamodb": {
 		DefaultCommand: "aws",
 		DefaultPort:    "",
 		Formats:        map[string]struct{}{"json": {}},
+		StreamFormats:  map[string]struct{}{"json": {}},
 	},
 	"influxdb": {
 		DefaultCommand: "influx",
@@ -68,6 +98,7 @@ var dbDrivers = map[string]DriverInfo{
 		DefaultCommand: "gbak",
 		DefaultPort:    "3050",
 		Formats:        map[string]struct{}{"fbk": {}},
+		RestoreCommand: "gbak",
 	},
 	"cassandra": {
 		DefaultCommand: "nodetool",
@@ -94,6 +125,21 @@ func IsValidFormatDump(driverName, format string) bool {
 	return false
 }
 
+func IsStreamFormatDump(driverName, format string) bool {
+	if driver, ok := dbDrivers[driverName]; ok {
+		_, exists := driver.StreamFormats[format]
+		return exists
+	}
+	return false
+}
+
+func IsDiscoverable(driverName string) bool {
+	if driver, ok := dbDrivers[driverName]; ok {
+		return driver.Discover
+	}
+	return false
+}
+
 func GetDBSource(driverName, format string) string {
 	driver, ok := dbDrivers[driverName]
 	if !ok {
@@ -109,6 +155,21 @@ func GetDBSource(driverName, format string) string {
 	return driver.DefaultCommand
 }
 
+func GetRestoreSource(driverName, format string) string {
+	driver, ok := dbDrivers[driverName]
+	if !ok {
+		return ""
+	}
+
+	if driver.RestoreOverrides != nil {
+		if cmd, exists := driver.RestoreOverrides[format]; exists {
+			return cmd
+		}
+	}
+
+	return driver.RestoreCommand
+}
+
 func GetDefaultDBPort(driverName string) string {
 	driver, ok := dbDrivers[driverName]
 	if !ok {
@@ -0,0 +1,319 @@
+package metrics
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	kindGauge   = "gauge"
+	kindCounter = "counter"
+)
+
+// Registry keeps metric families in registration order and renders them in
+// the Prometheus text exposition format.
+type Registry struct {
+	mu       sync.Mutex
+	families []*Vec
+}
+
+type Vec struct {
+	mu     sync.Mutex
+	name   string
+	help   string
+	kind   string
+	labels []string
+	values map[string]*sample
+}
+
+type sample struct {
+	labels []string
+	value  float64
+}
+
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+func (r *Registry) Gauge(name, help string, labels ...string) *Vec {
+	return r.register(name, help, kindGauge, labels)
+}
+
+func (r *Registry) Counter(name, help string, labels ...string) *Vec {
+	return r.register(name, help, kindCounter, labels)
+}
+
+func (r *Registry) register(name, help, kind string, labels []string) *Vec {
+	v := &Vec{
+		name:   name,
+		help:   help,
+		kind:   kind,
+		labels: labels,
+		values: make(map[string]*sample),
+	}
+
+	r.mu.Lock()
+	r.families = append(r.families, v)
+	r.mu.Unlock()
+
+	return v
+}
+
+func (v *Vec) Set(value float64, labelValues ...string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.sample(labelValues).value = value
+}
+
+func (v *Vec) Add(value float64, labelValues ...string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.sample(labelValues).value += value
+}
+
+func (v *Vec) Inc(labelValues ...string) {
+	v.Add(1, labelValues...)
+}
+
+// seed adds a counter value from an earlier run. A gauge is only taken when
+// this run has not set it yet, its own observation is newer.
+func (v *Vec) seed(value float64, labelValues []string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if _, ok := v.values[strings.Join(labelValues, "\xff")]; ok && v.kind == kindGauge {
+		return
+	}
+	v.sample(labelValues).value += value
+}
+
+func (v *Vec) sample(labelValues []string) *sample {
+	if len(labelValues) != len(v.labels) {
+		panic(fmt.Sprintf("metric %s expects %d labels, got %d", v.name, len(v.labels), len(labelValues)))
+	}
+
+	key := strings.Join(labelValues, "\xff")
+	s, ok := v.values[key]
+	if !ok {
+		s = &sample{labels: append([]string(nil), labelValues...)}
+		v.values[key] = s
+	}
+
+	return s
+}
+
+func (v *Vec) write(w *bytes.Buffer) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if len(v.values) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "# HELP %s %s\n", v.name, v.help)
+	fmt.Fprintf(w, "# TYPE %s %s\n", v.name, v.kind)
+
+	keys := make([]string, 0, len(v.values))
+	for key := range v.values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		s := v.values[key]
+		w.WriteString(v.name)
+
+		if len(v.labels) > 0 {
+			w.WriteByte('{')
+			for i, label := range v.labels {
+				if i > 0 {
+					w.WriteByte(',')
+				}
+				fmt.Fprintf(w, "%s=\"%s\"", label, escape(s.labels[i]))
+			}
+			w.WriteByte('}')
+		}
+
+		fmt.Fprintf(w, " %s\n", strconv.FormatFloat(s.value, 'f', -1, 64))
+	}
+}
+
+func (r *Registry) WriteTo(w io.Writer) (int64, error) {
+	r.mu.Lock()
+	families := append([]*Vec(nil), r.families...)
+	r.mu.Unlock()
+
+	var buf bytes.Buffer
+	for _, v := range families {
+		v.write(&buf)
+	}
+
+	return buf.WriteTo(w)
+}
+
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		_, _ = r.WriteTo(w)
+	})
+}
+
+// WriteTextfile writes the metrics for the node_exporter textfile collector.
+// The file is replaced atomically so the collector never reads it half written.
+func (r *Registry) WriteTextfile(path string) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create metrics directory: %v", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create metrics file: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := r.WriteTo(tmp); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write metrics file: %v", err)
+	}
+
+	if err := tmp.Chmod(0644); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write metrics file: %v", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write metrics file: %v", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace metrics file: %v", err)
+	}
+
+	return nil
+}
+
+// ReadTextfile seeds the registry with the samples a previous run wrote to
+// path. One-shot runs only observe the databases they back up, seeding keeps
+// the samples of every other database and carries counters forward instead
+// of overwriting them. A missing file is not an error, lines of metrics the
+// registry does not know are skipped.
+func (r *Registry) ReadTextfile(path string) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read metrics file: %v", err)
+	}
+
+	r.mu.Lock()
+	families := make(map[string]*Vec, len(r.families))
+	for _, v := range r.families {
+		families[v.name] = v
+	}
+	r.mu.Unlock()
+
+	for _, line := range strings.Split(string(data), "\n") {
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		name, labels, value, ok := parseLine(line)
+		if !ok {
+			continue
+		}
+
+		v, ok := families[name]
+		if !ok || len(labels) != len(v.labels) {
+			continue
+		}
+
+		labelValues := make([]string, len(v.labels))
+		for i, label := range v.labels {
+			if labelValues[i], ok = labels[label]; !ok {
+				break
+			}
+		}
+		if ok {
+			v.seed(value, labelValues)
+		}
+	}
+
+	return nil
+}
+
+// parseLine splits one sample line of the text format as written by WriteTo.
+func parseLine(line string) (string, map[string]string, float64, bool) {
+	labels := make(map[string]string)
+
+	end := strings.IndexAny(line, "{ ")
+	if end <= 0 {
+		return "", nil, 0, false
+	}
+	name, rest := line[:end], line[end:]
+
+	if strings.HasPrefix(rest, "{") {
+		rest = rest[1:]
+		for !strings.HasPrefix(rest, "}") {
+			rest = strings.TrimPrefix(rest, ",")
+
+			eq := strings.Index(rest, "=\"")
+			if eq <= 0 {
+				return "", nil, 0, false
+			}
+			label := rest[:eq]
+			rest = rest[eq+2:]
+
+			var value strings.Builder
+			closed := false
+			for i := 0; i < len(rest); i++ {
+				c := rest[i]
+				if c == '\\' && i+1 < len(rest) {
+					i++
+					switch rest[i] {
+					case 'n':
+						value.WriteByte('\n')
+					default:
+						value.WriteByte(rest[i])
+					}
+					continue
+				}
+				if c == '"' {
+					rest = rest[i+1:]
+					closed = true
+					break
+				}
+				value.WriteByte(c)
+			}
+			if !closed {
+				return "", nil, 0, false
+			}
+
+			labels[label] = value.String()
+		}
+		rest = rest[1:]
+	}
+
+	value, err := strconv.ParseFloat(strings.TrimSpace(rest), 64)
+	if err != nil {
+		return "", nil, 0, false
+	}
+
+	return name, labels, value, true
+}
+
+func escape(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, "\n", `\n`)
+	return strings.ReplaceAll(value, `"`, `\"`)
+}
@@ -0,0 +1,112 @@
+package metrics_test
+
+import (
+	"bytes"
+	"dumper/pkg/utils/metrics"
+	"io"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_WriteTo(t *testing.T) {
+	r := metrics.NewRegistry()
+	size := r.Gauge("dumper_dump_size_bytes", "Size of the last dump.", "db")
+	failures := r.Counter("dumper_backup_failures_total", "Failed backups by phase.", "db", "phase")
+	r.Gauge("dumper_unused", "Never set.")
+
+	size.Set(2048, "app")
+	size.Set(1.5e9, "b\"i\\g\n")
+	failures.Inc("app", "dump")
+	failures.Inc("app", "dump")
+	failures.Add(1, "app", "upload")
+
+	var buf bytes.Buffer
+	_, err := r.WriteTo(&buf)
+	require.NoError(t, err)
+
+	assert.Equal(t, `# HELP dumper_dump_size_bytes Size of the last dump.
+# TYPE dumper_dump_size_bytes gauge
+dumper_dump_size_bytes{db="app"} 2048
+dumper_dump_size_bytes{db="b\"i\\g\n"} 1500000000
+# HELP dumper_backup_failures_total Failed backups by phase.
+# TYPE dumper_backup_failures_total counter
+dumper_backup_failures_total{db="app",phase="dump"} 2
+dumper_backup_failures_total{db="app",phase="upload"} 1
+`, buf.String())
+}
+
+func TestRegistry_Handler(t *testing.T) {
+	r := metrics.NewRegistry()
+	r.Gauge("dumper_up", "Always one.").Set(1)
+
+	rec := httptest.NewRecorder()
+	r.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	body, _ := io.ReadAll(rec.Body)
+	assert.Contains(t, rec.Header().Get("Content-Type"), "text/plain")
+	assert.Contains(t, string(body), "dumper_up 1\n")
+}
+
+func TestRegistry_WriteTextfile(t *testing.T) {
+	r := metrics.NewRegistry()
+	r.Gauge("dumper_up", "Always one.").Set(1)
+
+	path := filepath.Join(t.TempDir(), "textfile", "dumper.prom")
+	require.NoError(t, r.WriteTextfile(path))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "dumper_up 1\n")
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestVec_WrongLabelCount(t *testing.T) {
+	r := metrics.NewRegistry()
+	g := r.Gauge("dumper_size", "Size.", "db")
+
+	assert.Panics(t, func() { g.Set(1) })
+}
+
+func TestRegistry_ReadTextfile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dumper.prom")
+
+	previous := metrics.NewRegistry()
+	previousSuccess := previous.Gauge("dumper_last_success", "Last success.", "db")
+	previousSuccess.Set(1750000000, "other")
+	previousSuccess.Set(1750000000, "app")
+	previousSuccess.Set(1, "b\"i\\g\n")
+	previous.Counter("dumper_runs_total", "Runs.", "db", "status").Inc("app", "success")
+	previous.Gauge("dumper_gone", "No longer registered.").Set(1)
+	require.NoError(t, previous.WriteTextfile(path))
+
+	r := metrics.NewRegistry()
+	lastSuccess := r.Gauge("dumper_last_success", "Last success.", "db")
+	runs := r.Counter("dumper_runs_total", "Runs.", "db", "status")
+
+	lastSuccess.Set(1760000000, "app")
+	require.NoError(t, r.ReadTextfile(path))
+	runs.Inc("app", "success")
+
+	var buf bytes.Buffer
+	_, err := r.WriteTo(&buf)
+	require.NoError(t, err)
+
+	assert.Contains(t, buf.String(), `dumper_last_success{db="other"} 1750000000`)
+	assert.Contains(t, buf.String(), `dumper_last_success{db="b\"i\\g\n"} 1`)
+	assert.Contains(t, buf.String(), `dumper_last_success{db="app"} 1760000000`)
+	assert.Contains(t, buf.String(), `dumper_runs_total{db="app",status="success"} 2`)
+	assert.NotContains(t, buf.String(), "dumper_gone")
+}
+
+func TestRegistry_ReadTextfileMissing(t *testing.T) {
+	r := metrics.NewRegistry()
+	assert.NoError(t, r.ReadTextfile(filepath.Join(t.TempDir(), "missing.prom")))
+}
@@ -37,7 +37,7 @@ func WithRetry(
 				return fmt.Errorf("failed after %d attempts: %w", maxRetries, err)
 			}
 
-			delay := attempt.ExponentialBackoff(attempts)
+			delay := BackoffFunc(attempts)
 
 			logging.L(ctx).Error("Connection error, retrying after",
 				logging.StringAttr("time", delay.String()),
@@ -0,0 +1,60 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"dumper/pkg/utils/mask"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+)
+
+func RunLocalCommand(ctx context.Context, dir, cmd string) (string, error) {
+	return RunLocalCommandWithInput(ctx, dir, cmd, nil, nil)
+}
+
+// RunLocalCommandWithInput runs cmd with env added to the current
+// environment, feeding it stdin when set.
+func RunLocalCommandWithInput(ctx context.Context, dir, cmd string, env map[string]string, stdin io.Reader) (string, error) {
+	var command *exec.Cmd
+
+	if runtime.GOOS == "windows" {
+		command = exec.CommandContext(ctx, "cmd", "/C", cmd)
+	} else if bash, err := exec.LookPath("bash"); err == nil {
+		command = exec.CommandContext(ctx, bash, "-c", fmt.Sprintf("set -o pipefail; %s", cmd))
+	} else {
+		command = exec.CommandContext(ctx, "sh", "-c", cmd)
+	}
+
+	var stdout, stderr bytes.Buffer
+	command.Dir = dir
+
+	if len(env) > 0 {
+		command.Env = os.Environ()
+		for name, value := range env {
+			command.Env = append(command.Env, name+"="+value)
+		}
+	}
+
+	command.Stdout = &stdout
+	command.Stderr = &stderr
+
+	if stdin != nil {
+		command.Stdin = stdin
+	}
+
+	err := command.Run()
+	output := stdout.String()
+	errorOutput := stderr.String()
+
+	if err != nil {
+		return output + errorOutput, fmt.Errorf(
+			"command failed: %v\nstderr: %s",
+			err, mask.Mask(errorOutput),
+		)
+	}
+
+	return output, nil
+}
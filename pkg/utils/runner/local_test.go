@@ -0,0 +1,61 @@
+package runner
+
+import (
+	"context"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestRunLocalCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("posix shell required")
+	}
+
+	t.Run("Command output is returned", func(t *testing.T) {
+		out, err := RunLocalCommand(context.Background(), t.TempDir(), "echo hello")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if strings.TrimSpace(out) != "hello" {
+			t.Fatalf("expected 'hello', got %q", out)
+		}
+	})
+
+	t.Run("Input is passed to stdin", func(t *testing.T) {
+		out, err := RunLocalCommandWithInput(context.Background(), t.TempDir(), "cat", nil, strings.NewReader("restore"))
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if out != "restore" {
+			t.Fatalf("expected 'restore', got %q", out)
+		}
+	})
+
+	t.Run("Env is passed to the command", func(t *testing.T) {
+		out, err := RunLocalCommandWithInput(context.Background(), t.TempDir(), "echo $DUMPER_TEST_SECRET", map[string]string{"DUMPER_TEST_SECRET": "secret"}, nil)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if strings.TrimSpace(out) != "secret" {
+			t.Fatalf("expected 'secret', got %q", out)
+		}
+	})
+
+	t.Run("Failed pipeline returns error", func(t *testing.T) {
+		_, err := RunLocalCommand(context.Background(), t.TempDir(), "false | cat")
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+	})
+
+	t.Run("Stderr is masked", func(t *testing.T) {
+		_, err := RunLocalCommand(context.Background(), t.TempDir(), "echo 'mysqldump -psecret' >&2; exit 1")
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+		if strings.Contains(err.Error(), "secret") {
+			t.Fatalf("expected password to be masked, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,123 @@
+package secret
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	awsConfig "github.com/aws/aws-sdk-go-v2/config"
+)
+
+// AWSSecretsManager reads a secret from AWS Secrets Manager, ref being
+// "secret-id" or "secret-id#key" for a key of a JSON secret. Credentials and
+// region come from the default AWS chain, AWS_ENDPOINT_URL_SECRETS_MANAGER or
+// AWS_ENDPOINT_URL override the endpoint.
+func AWSSecretsManager(ctx context.Context, ref string) (string, error) {
+	id, key := splitField(ref)
+	if id == "" {
+		return "", fmt.Errorf("aws-sm reference %q has no secret id", ref)
+	}
+
+	cfg, err := awsConfig.LoadDefaultConfig(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to load aws config: %w", err)
+	}
+	if cfg.Region == "" {
+		return "", fmt.Errorf("aws region is not set")
+	}
+
+	creds, err := cfg.Credentials.Retrieve(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to load aws credentials: %w", err)
+	}
+
+	payload, err := json.Marshal(map[string]string{"SecretId": id})
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, awsEndpoint(cfg.Region), bytes.NewReader(payload))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "secretsmanager.GetSecretValue")
+
+	hash := sha256.Sum256(payload)
+	if err := v4.NewSigner().SignHTTP(ctx, creds, req, hex.EncodeToString(hash[:]), "secretsmanager", cfg.Region, time.Now()); err != nil {
+		return "", fmt.Errorf("failed to sign aws request: %w", err)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("aws-sm request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("aws-sm returned %s for %s: %s", resp.Status, id, awsError(body))
+	}
+
+	var out struct {
+		SecretString *string
+		SecretBinary []byte
+	}
+	if err := json.Unmarshal(body, &out); err != nil {
+		return "", fmt.Errorf("invalid aws-sm response: %w", err)
+	}
+
+	value := string(out.SecretBinary)
+	if out.SecretString != nil {
+		value = *out.SecretString
+	}
+
+	if key == "" {
+		return value, nil
+	}
+
+	var data map[string]any
+	if err := json.Unmarshal([]byte(value), &data); err != nil {
+		return "", fmt.Errorf("secret %s is not a JSON object", id)
+	}
+
+	return pickField(data, key, id)
+}
+
+func awsEndpoint(region string) string {
+	for _, name := range []string{"AWS_ENDPOINT_URL_SECRETS_MANAGER", "AWS_ENDPOINT_URL"} {
+		if endpoint := os.Getenv(name); endpoint != "" {
+			return strings.TrimRight(endpoint, "/") + "/"
+		}
+	}
+	return fmt.Sprintf("https://secretsmanager.%s.amazonaws.com/", region)
+}
+
+// awsError keeps only the error type and message, never the response body
+// as a whole.
+func awsError(body []byte) string {
+	var out struct {
+		Type    string `json:"__type"`
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(body, &out); err != nil || out.Type == "" {
+		return "unknown error"
+	}
+	if idx := strings.LastIndex(out.Type, "#"); idx >= 0 {
+		out.Type = out.Type[idx+1:]
+	}
+	return strings.TrimSpace(out.Type + " " + out.Message)
+}
@@ -0,0 +1,75 @@
+package secret
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const cmdTimeout = 30 * time.Second
+
+// Env reads the environment variable ref.
+func Env(_ context.Context, ref string) (string, error) {
+	value, ok := os.LookupEnv(ref)
+	if !ok {
+		return "", fmt.Errorf("environment variable %s is not set", ref)
+	}
+	return value, nil
+}
+
+// File reads the file at ref without its trailing newline.
+func File(_ context.Context, ref string) (string, error) {
+	data, err := os.ReadFile(ref)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// Cmd runs ref with sh and returns its output without the trailing newline.
+func Cmd(ctx context.Context, ref string) (string, error) {
+	return run(ctx, "sh", "-c", ref)
+}
+
+// Pass reads the first line of a pass(1) entry.
+func Pass(ctx context.Context, ref string) (string, error) {
+	out, err := run(ctx, "pass", "show", ref)
+	if err != nil {
+		return "", err
+	}
+	line, _, _ := strings.Cut(out, "\n")
+	return line, nil
+}
+
+// OnePassword reads a field with the 1Password CLI, ref being
+// vault/item/field with or without the op:// prefix.
+func OnePassword(ctx context.Context, ref string) (string, error) {
+	if !strings.HasPrefix(ref, "op://") {
+		ref = "op://" + ref
+	}
+	return run(ctx, "op", "read", "--no-newline", ref)
+}
+
+func run(ctx context.Context, name string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, cmdTimeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	command := exec.CommandContext(ctx, name, args...)
+	command.Stdout = &stdout
+	command.Stderr = &stderr
+
+	if err := command.Run(); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return "", fmt.Errorf("%s timed out", name)
+		}
+		return "", fmt.Errorf("%s failed: %v: %s", name, err, strings.TrimSpace(stderr.String()))
+	}
+
+	return strings.TrimRight(stdout.String(), "\r\n"), nil
+}
@@ -0,0 +1,201 @@
+// Package secret resolves ${provider:reference} placeholders in config
+// values, so credentials can live outside the config file.
+package secret
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Provider returns the secret a reference points to.
+type Provider func(ctx context.Context, ref string) (string, error)
+
+type Resolver struct {
+	providers map[string]Provider
+	mu        sync.Mutex
+	cache     map[string]string
+}
+
+// New returns a resolver with every built-in provider: env, file, cmd,
+// vault, aws-sm, pass and op.
+func New() *Resolver {
+	r := &Resolver{
+		providers: make(map[string]Provider),
+		cache:     make(map[string]string),
+	}
+
+	r.Register("env", Env)
+	r.Register("file", File)
+	r.Register("cmd", Cmd)
+	r.Register("vault", Vault)
+	r.Register("aws-sm", AWSSecretsManager)
+	r.Register("pass", Pass)
+	r.Register("op", OnePassword)
+
+	return r
+}
+
+// Register adds or replaces the provider for name.
+func (r *Resolver) Register(name string, provider Provider) {
+	r.providers[name] = provider
+}
+
+// Providers lists the registered provider names.
+func (r *Resolver) Providers() []string {
+	names := make([]string, 0, len(r.providers))
+	for name := range r.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Resolve replaces every ${provider:reference} in s. Placeholders naming an
+// unknown provider, like shell ${VAR:-default}, are kept as they are and
+// $${ is written as a literal ${.
+func (r *Resolver) Resolve(ctx context.Context, s string) (string, error) {
+	if !strings.Contains(s, "${") {
+		return s, nil
+	}
+
+	var out strings.Builder
+	for i := 0; i < len(s); {
+		if strings.HasPrefix(s[i:], "$${") {
+			out.WriteString("${")
+			i += 3
+			continue
+		}
+
+		if !strings.HasPrefix(s[i:], "${") {
+			out.WriteByte(s[i])
+			i++
+			continue
+		}
+
+		name, ref, end, ok := r.parse(s, i+2)
+		if !ok {
+			out.WriteString("${")
+			i += 2
+			continue
+		}
+
+		value, err := r.lookup(ctx, name, ref)
+		if err != nil {
+			return "", err
+		}
+
+		out.WriteString(value)
+		i = end
+	}
+
+	return out.String(), nil
+}
+
+// parse reads provider:reference} starting at pos. Braces inside the
+// reference are balanced, so commands like awk '{print $1}' work.
+func (r *Resolver) parse(s string, pos int) (string, string, int, bool) {
+	colon := strings.IndexByte(s[pos:], ':')
+	if colon <= 0 {
+		return "", "", 0, false
+	}
+
+	name := s[pos : pos+colon]
+	if _, ok := r.providers[name]; !ok {
+		return "", "", 0, false
+	}
+
+	depth := 0
+	for i := pos + colon + 1; i < len(s); i++ {
+		switch s[i] {
+		case '{':
+			depth++
+		case '}':
+			if depth == 0 {
+				return name, s[pos+colon+1 : i], i + 1, true
+			}
+			depth--
+		}
+	}
+
+	return "", "", 0, false
+}
+
+func (r *Resolver) lookup(ctx context.Context, name, ref string) (string, error) {
+	key := name + ":" + ref
+
+	r.mu.Lock()
+	value, ok := r.cache[key]
+	r.mu.Unlock()
+	if ok {
+		return value, nil
+	}
+
+	value, err := r.providers[name](ctx, strings.TrimSpace(ref))
+	if err != nil {
+		return "", fmt.Errorf("secret %s: %w", name, err)
+	}
+
+	r.mu.Lock()
+	r.cache[key] = value
+	r.mu.Unlock()
+
+	return value, nil
+}
+
+// ResolveNode resolves every scalar value of a parsed YAML document in
+// place, before it is decoded into the config structs.
+func (r *Resolver) ResolveNode(ctx context.Context, node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		value, err := r.Resolve(ctx, node.Value)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", node.Line, err)
+		}
+		if value != node.Value {
+			node.Value = value
+			if node.Style == 0 {
+				// let an unquoted ${env:PORT} still decode into an int, but
+				// a secret that reads as null must stay a string
+				node.Tag = ""
+				if isNull(value) {
+					node.Tag = "!!str"
+				}
+			}
+		}
+		return nil
+	case yaml.MappingNode:
+		for i := 1; i < len(node.Content); i += 2 {
+			if err := r.ResolveNode(ctx, node.Content[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		for _, child := range node.Content {
+			if err := r.ResolveNode(ctx, child); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// isNull reports whether YAML resolves the plain scalar value to null.
+func isNull(value string) bool {
+	switch value {
+	case "", "~", "null", "Null", "NULL":
+		return true
+	}
+	return false
+}
+
+// splitField cuts a "path#field" reference.
+func splitField(ref string) (string, string) {
+	path, field, _ := strings.Cut(ref, "#")
+	return path, field
+}
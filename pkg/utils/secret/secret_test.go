@@ -0,0 +1,240 @@
+package secret_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"dumper/pkg/utils/secret"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestResolve(t *testing.T) {
+	t.Setenv("DUMPER_TEST_SECRET", "s3cr3t")
+
+	file := filepath.Join(t.TempDir(), "password")
+	require.NoError(t, os.WriteFile(file, []byte("from-file\n"), 0600))
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "plain", input: "password", want: "password"},
+		{name: "env", input: "${env:DUMPER_TEST_SECRET}", want: "s3cr3t"},
+		{name: "embedded", input: "user:${env:DUMPER_TEST_SECRET}@host", want: "user:s3cr3t@host"},
+		{name: "file", input: "${file:" + file + "}", want: "from-file"},
+		{name: "cmd", input: "${cmd:echo hello | awk '{print $1}'}", want: "hello"},
+		{name: "escaped", input: "$${env:DUMPER_TEST_SECRET}", want: "${env:DUMPER_TEST_SECRET}"},
+		{name: "unknown provider", input: "${HOME:-/root}", want: "${HOME:-/root}"},
+		{name: "unterminated", input: "${env:DUMPER_TEST_SECRET", want: "${env:DUMPER_TEST_SECRET"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := secret.New().Resolve(context.Background(), tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestResolve_Errors(t *testing.T) {
+	_, err := secret.New().Resolve(context.Background(), "${env:DUMPER_TEST_MISSING}")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "DUMPER_TEST_MISSING")
+
+	_, err = secret.New().Resolve(context.Background(), "${cmd:echo leaked; exit 3}")
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "leaked")
+}
+
+func TestResolve_Cached(t *testing.T) {
+	calls := 0
+	r := secret.New()
+	r.Register("count", func(_ context.Context, ref string) (string, error) {
+		calls++
+		return ref, nil
+	})
+
+	got, err := r.Resolve(context.Background(), "${count:a}-${count:a}-${count:b}")
+
+	require.NoError(t, err)
+	assert.Equal(t, "a-a-b", got)
+	assert.Equal(t, 2, calls)
+}
+
+func TestResolveNode(t *testing.T) {
+	t.Setenv("DUMPER_TEST_SECRET", "s3cr3t")
+	t.Setenv("DUMPER_TEST_PORT", "5432")
+
+	var root yaml.Node
+	require.NoError(t, yaml.Unmarshal([]byte(`
+servers:
+  main:
+    password: ${env:DUMPER_TEST_SECRET}
+    hosts: [a, "${env:DUMPER_TEST_SECRET}"]
+    port: ${env:DUMPER_TEST_PORT}
+    ${env:DUMPER_TEST_SECRET}: key
+`), &root))
+
+	require.NoError(t, secret.New().ResolveNode(context.Background(), &root))
+
+	var cfg map[string]map[string]map[string]any
+	require.NoError(t, root.Decode(&cfg))
+
+	main := cfg["servers"]["main"]
+	assert.Equal(t, "s3cr3t", main["password"])
+	assert.Equal(t, []any{"a", "s3cr3t"}, main["hosts"])
+	assert.Equal(t, 5432, main["port"])
+	assert.Equal(t, "key", main["${env:DUMPER_TEST_SECRET}"])
+}
+
+func TestResolveNode_NullLikeSecrets(t *testing.T) {
+	for _, value := range []string{"", "~", "null", "NULL"} {
+		t.Run(value, func(t *testing.T) {
+			t.Setenv("DUMPER_TEST_SECRET", value)
+
+			var root yaml.Node
+			require.NoError(t, yaml.Unmarshal([]byte("password: ${env:DUMPER_TEST_SECRET}\n"), &root))
+			require.NoError(t, secret.New().ResolveNode(context.Background(), &root))
+
+			var cfg struct {
+				Password *string `yaml:"password"`
+			}
+			require.NoError(t, root.Decode(&cfg))
+
+			require.NotNil(t, cfg.Password)
+			assert.Equal(t, value, *cfg.Password)
+		})
+	}
+}
+
+func TestResolveNode_ReportsLine(t *testing.T) {
+	var root yaml.Node
+	require.NoError(t, yaml.Unmarshal([]byte("a: 1\nb: ${env:DUMPER_TEST_MISSING}\n"), &root))
+
+	err := secret.New().ResolveNode(context.Background(), &root)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "line 2")
+}
+
+func TestVault(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "root-token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/v1/secret/data/db":
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"data": map[string]any{
+					"data":     map[string]any{"password": "kv2-pass", "user": "app"},
+					"metadata": map[string]any{"version": 3},
+				},
+			})
+		case "/v1/kv/db":
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"data": map[string]any{"password": "kv1-pass"},
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	t.Setenv("VAULT_ADDR", server.URL)
+	t.Setenv("VAULT_TOKEN", "root-token")
+
+	got, err := secret.Vault(context.Background(), "secret/data/db#password")
+	require.NoError(t, err)
+	assert.Equal(t, "kv2-pass", got)
+
+	got, err = secret.Vault(context.Background(), "kv/db")
+	require.NoError(t, err)
+	assert.Equal(t, "kv1-pass", got)
+
+	_, err = secret.Vault(context.Background(), "secret/data/db")
+	assert.ErrorContains(t, err, "select one with #field")
+
+	_, err = secret.Vault(context.Background(), "secret/data/db#missing")
+	assert.ErrorContains(t, err, "no field missing")
+
+	_, err = secret.Vault(context.Background(), "secret/data/other#password")
+	assert.ErrorContains(t, err, "404")
+
+	t.Setenv("VAULT_TOKEN", "wrong")
+	_, err = secret.Vault(context.Background(), "kv/db")
+	assert.ErrorContains(t, err, "403")
+}
+
+func TestAWSSecretsManager(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Amz-Target") != "secretsmanager.GetSecretValue" ||
+			!strings.Contains(r.Header.Get("Authorization"), "Credential=AKIDTEST/") {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		var in struct{ SecretId string }
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		switch in.SecretId {
+		case "prod/db":
+			_ = json.NewEncoder(w).Encode(map[string]any{"SecretString": `{"password":"aws-pass"}`})
+		case "prod/token":
+			_ = json.NewEncoder(w).Encode(map[string]any{"SecretString": "plain-token"})
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"__type":  "com.amazonaws.secretsmanager#ResourceNotFoundException",
+				"message": "Secrets Manager can't find the specified secret.",
+			})
+		}
+	}))
+	defer server.Close()
+
+	t.Setenv("AWS_ENDPOINT_URL_SECRETS_MANAGER", server.URL)
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDTEST")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	t.Setenv("AWS_REGION", "eu-west-1")
+	t.Setenv("AWS_CONFIG_FILE", filepath.Join(t.TempDir(), "config"))
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(t.TempDir(), "credentials"))
+
+	got, err := secret.AWSSecretsManager(context.Background(), "prod/db#password")
+	require.NoError(t, err)
+	assert.Equal(t, "aws-pass", got)
+
+	got, err = secret.AWSSecretsManager(context.Background(), "prod/token")
+	require.NoError(t, err)
+	assert.Equal(t, "plain-token", got)
+
+	_, err = secret.AWSSecretsManager(context.Background(), "prod/missing")
+	assert.ErrorContains(t, err, "ResourceNotFoundException")
+}
+
+func TestRegister(t *testing.T) {
+	r := secret.New()
+	r.Register("fail", func(context.Context, string) (string, error) {
+		return "", errors.New("boom")
+	})
+
+	assert.Contains(t, r.Providers(), "fail")
+
+	_, err := r.Resolve(context.Background(), "${fail:x}")
+	assert.ErrorContains(t, err, "secret fail: boom")
+}
@@ -0,0 +1,118 @@
+package secret
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const defaultVaultAddr = "http://127.0.0.1:8200"
+
+var httpClient = &http.Client{Timeout: 30 * time.Second}
+
+// Vault reads a field of a HashiCorp Vault KV secret, ref being
+// "mount/path#field" (KV v2 paths include data/, e.g. secret/data/db#password).
+// The server and token come from VAULT_ADDR, VAULT_TOKEN or ~/.vault-token and
+// VAULT_NAMESPACE, like the vault CLI.
+func Vault(ctx context.Context, ref string) (string, error) {
+	path, field := splitField(ref)
+	if path == "" {
+		return "", fmt.Errorf("vault reference %q has no path", ref)
+	}
+
+	token, err := vaultToken()
+	if err != nil {
+		return "", err
+	}
+
+	addr := os.Getenv("VAULT_ADDR")
+	if addr == "" {
+		addr = defaultVaultAddr
+	}
+
+	url := strings.TrimRight(addr, "/") + "/v1/" + strings.TrimLeft(path, "/")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+
+	req.Header.Set("X-Vault-Token", token)
+	if namespace := os.Getenv("VAULT_NAMESPACE"); namespace != "" {
+		req.Header.Set("X-Vault-Namespace", namespace)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("vault request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("vault returned %s for %s", resp.Status, path)
+	}
+
+	var body struct {
+		Data map[string]any `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("invalid vault response: %w", err)
+	}
+
+	data := body.Data
+	if nested, ok := data["data"].(map[string]any); ok {
+		if _, hasMeta := data["metadata"]; hasMeta {
+			data = nested
+		}
+	}
+
+	return pickField(data, field, path)
+}
+
+func vaultToken() (string, error) {
+	if token := os.Getenv("VAULT_TOKEN"); token != "" {
+		return token, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err == nil {
+		if data, err := os.ReadFile(filepath.Join(home, ".vault-token")); err == nil {
+			return strings.TrimSpace(string(data)), nil
+		}
+	}
+
+	return "", fmt.Errorf("vault token is not set, use VAULT_TOKEN or ~/.vault-token")
+}
+
+// pickField returns field from a secret's key/value data. Without a field
+// the secret must hold exactly one value.
+func pickField(data map[string]any, field, name string) (string, error) {
+	if field == "" {
+		if len(data) != 1 {
+			return "", fmt.Errorf("secret %s has %d fields, select one with #field", name, len(data))
+		}
+		for key := range data {
+			field = key
+		}
+	}
+
+	value, ok := data[field]
+	if !ok {
+		return "", fmt.Errorf("secret %s has no field %s", name, field)
+	}
+
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	default:
+		out, err := json.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+		return string(out), nil
+	}
+}
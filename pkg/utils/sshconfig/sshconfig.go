@@ -0,0 +1,333 @@
+// Package sshconfig reads the parts of OpenSSH client config files that
+// dumper uses to reach a host.
+package sshconfig
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Host is what the config files say about one alias.
+type Host struct {
+	HostName            string
+	User                string
+	Port                string
+	IdentityFiles       []string
+	ProxyJump           string
+	ServerAliveInterval time.Duration
+}
+
+type Config struct {
+	blocks []block
+}
+
+type block struct {
+	patterns []string
+	all      bool
+	options  []option
+}
+
+type option struct {
+	key   string
+	value string
+}
+
+// DefaultFiles are the user and system config, read in that order.
+func DefaultFiles() []string {
+	files := []string{"/etc/ssh/ssh_config"}
+	if home, err := os.UserHomeDir(); err == nil {
+		files = append([]string{filepath.Join(home, ".ssh", "config")}, files...)
+	}
+	return files
+}
+
+// Load parses the files in order, missing files are skipped. Like ssh,
+// the first value found for an option wins.
+func Load(paths ...string) (*Config, error) {
+	cfg := &Config{}
+
+	for _, path := range paths {
+		if err := cfg.load(path, nil, 0); err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+
+	return cfg, nil
+}
+
+// Parse reads a single config, Include lines are ignored.
+func Parse(r io.Reader) (*Config, error) {
+	cfg := &Config{}
+	if err := cfg.parse(r, "", nil, 0); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func (c *Config) load(path string, current *block, depth int) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := c.parse(f, filepath.Dir(path), current, depth); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+func (c *Config) parse(r io.Reader, dir string, current *block, depth int) error {
+	if current == nil {
+		current = &block{all: true}
+		c.blocks = append(c.blocks, *current)
+	}
+	idx := len(c.blocks) - 1
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		key, value := splitLine(scanner.Text())
+		if key == "" {
+			continue
+		}
+
+		switch key {
+		case "host":
+			c.blocks = append(c.blocks, block{patterns: strings.Fields(value)})
+			idx = len(c.blocks) - 1
+		case "match":
+			c.blocks = append(c.blocks, matchBlock(value))
+			idx = len(c.blocks) - 1
+		case "include":
+			if dir == "" || depth >= 16 {
+				continue
+			}
+			for _, pattern := range strings.Fields(value) {
+				pattern = expandHome(pattern)
+				if !filepath.IsAbs(pattern) {
+					pattern = filepath.Join(dir, pattern)
+				}
+				matches, err := filepath.Glob(pattern)
+				if err != nil {
+					return fmt.Errorf("line %d: %w", line, err)
+				}
+				for _, m := range matches {
+					scope := c.blocks[idx]
+					scope.options = nil
+					c.blocks = append(c.blocks, scope)
+					if err := c.load(m, &scope, depth+1); err != nil {
+						return err
+					}
+				}
+			}
+			// options after the Include still belong to the enclosing block
+			scope := c.blocks[idx]
+			scope.options = nil
+			c.blocks = append(c.blocks, scope)
+			idx = len(c.blocks) - 1
+		default:
+			c.blocks[idx].options = append(c.blocks[idx].options, option{key: key, value: value})
+		}
+	}
+
+	return scanner.Err()
+}
+
+// matchBlock supports "Match all" and "Match host ...", other criteria
+// never match.
+func matchBlock(value string) block {
+	fields := strings.Fields(value)
+	switch {
+	case len(fields) == 1 && strings.EqualFold(fields[0], "all"):
+		return block{all: true}
+	case len(fields) == 2 && strings.EqualFold(fields[0], "host"):
+		return block{patterns: strings.Split(fields[1], ",")}
+	default:
+		return block{}
+	}
+}
+
+func splitLine(line string) (string, string) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return "", ""
+	}
+
+	end := strings.IndexAny(line, " \t=")
+	if end < 0 {
+		return strings.ToLower(line), ""
+	}
+
+	key := strings.ToLower(line[:end])
+	value := strings.TrimSpace(line[end:])
+	value = strings.TrimSpace(strings.TrimPrefix(value, "="))
+
+	return key, unquote(value)
+}
+
+func unquote(value string) string {
+	if len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"' {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
+
+func (b block) matches(alias string) bool {
+	if b.all {
+		return true
+	}
+
+	matched := false
+	for _, pattern := range b.patterns {
+		if negated := strings.HasPrefix(pattern, "!"); negated {
+			if wildcard(pattern[1:], alias) {
+				return false
+			}
+			continue
+		}
+		if wildcard(pattern, alias) {
+			matched = true
+		}
+	}
+
+	return matched
+}
+
+// wildcard matches ssh host patterns, where * and ? are the only specials.
+func wildcard(pattern, s string) bool {
+	for len(pattern) > 0 {
+		switch pattern[0] {
+		case '*':
+			for i := len(s); i >= 0; i-- {
+				if wildcard(pattern[1:], s[i:]) {
+					return true
+				}
+			}
+			return false
+		case '?':
+			if s == "" {
+				return false
+			}
+		default:
+			if s == "" || !strings.EqualFold(pattern[:1], s[:1]) {
+				return false
+			}
+		}
+		pattern, s = pattern[1:], s[1:]
+	}
+	return s == ""
+}
+
+// Get returns the first value of key for alias.
+func (c *Config) Get(alias, key string) string {
+	values := c.values(alias, strings.ToLower(key), true)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// GetAll returns every value of a repeatable key, like IdentityFile.
+func (c *Config) GetAll(alias, key string) []string {
+	return c.values(alias, strings.ToLower(key), false)
+}
+
+func (c *Config) values(alias, key string, first bool) []string {
+	var values []string
+	for _, b := range c.blocks {
+		if !b.matches(alias) {
+			continue
+		}
+		for _, o := range b.options {
+			if o.key != key {
+				continue
+			}
+			values = append(values, o.value)
+			if first {
+				return values
+			}
+		}
+	}
+	return values
+}
+
+// Lookup resolves the settings of alias, expanding ~ and the %h, %r, %u,
+// %d and %% tokens of IdentityFile.
+func (c *Config) Lookup(alias string) Host {
+	h := Host{
+		HostName:  c.Get(alias, "HostName"),
+		User:      c.Get(alias, "User"),
+		Port:      c.Get(alias, "Port"),
+		ProxyJump: c.Get(alias, "ProxyJump"),
+	}
+
+	if h.HostName != "" {
+		h.HostName = strings.ReplaceAll(h.HostName, "%h", alias)
+	}
+
+	if strings.EqualFold(h.ProxyJump, "none") {
+		h.ProxyJump = ""
+	}
+
+	if interval := c.Get(alias, "ServerAliveInterval"); interval != "" {
+		if seconds, err := strconv.Atoi(interval); err == nil && seconds > 0 {
+			h.ServerAliveInterval = time.Duration(seconds) * time.Second
+		}
+	}
+
+	hostName := h.HostName
+	if hostName == "" {
+		hostName = alias
+	}
+
+	for _, file := range c.GetAll(alias, "IdentityFile") {
+		if strings.EqualFold(file, "none") {
+			continue
+		}
+		h.IdentityFiles = append(h.IdentityFiles, expandTokens(file, hostName, h.User))
+	}
+
+	return h
+}
+
+func expandTokens(s, host, remoteUser string) string {
+	s = expandHome(s)
+	if !strings.Contains(s, "%") {
+		return s
+	}
+
+	local := ""
+	if u, err := user.Current(); err == nil {
+		local = u.Username
+	}
+	if remoteUser == "" {
+		remoteUser = local
+	}
+	home, _ := os.UserHomeDir()
+
+	return strings.NewReplacer(
+		"%%", "%",
+		"%d", home,
+		"%h", host,
+		"%r", remoteUser,
+		"%u", local,
+	).Replace(s)
+}
+
+func expandHome(s string) string {
+	if s != "~" && !strings.HasPrefix(s, "~/") {
+		return s
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return s
+	}
+	return filepath.Join(home, s[1:])
+}
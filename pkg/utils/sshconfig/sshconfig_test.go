@@ -0,0 +1,82 @@
+package sshconfig_test
+
+import (
+	"dumper/pkg/utils/sshconfig"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testConfig = `
+# global defaults come last in the file but first match wins
+Host db-prod
+    HostName 10.0.0.12
+    User postgres
+    Port=2222
+    IdentityFile ~/.ssh/id_prod
+    ProxyJump ops@bastion:22
+    ServerAliveInterval 30
+
+Host db-* !db-legacy
+    User backup
+    IdentityFile "/keys/%h_%r"
+
+Host bastion
+    HostName bastion.example.com
+    ProxyJump none
+
+Match all
+    Port 22
+    IdentityFile ~/.ssh/id_ed25519
+`
+
+func TestLookup(t *testing.T) {
+	cfg, err := sshconfig.Parse(strings.NewReader(testConfig))
+	require.NoError(t, err)
+
+	home, err := os.UserHomeDir()
+	require.NoError(t, err)
+
+	h := cfg.Lookup("db-prod")
+	assert.Equal(t, "10.0.0.12", h.HostName)
+	assert.Equal(t, "postgres", h.User)
+	assert.Equal(t, "2222", h.Port)
+	assert.Equal(t, "ops@bastion:22", h.ProxyJump)
+	assert.Equal(t, 30*time.Second, h.ServerAliveInterval)
+	assert.Equal(t, []string{
+		filepath.Join(home, ".ssh/id_prod"),
+		"/keys/10.0.0.12_postgres",
+		filepath.Join(home, ".ssh/id_ed25519"),
+	}, h.IdentityFiles)
+
+	h = cfg.Lookup("db-staging")
+	assert.Equal(t, "", h.HostName)
+	assert.Equal(t, "backup", h.User)
+	assert.Equal(t, "22", h.Port)
+
+	h = cfg.Lookup("db-legacy")
+	assert.Equal(t, "", h.User)
+
+	h = cfg.Lookup("bastion")
+	assert.Equal(t, "bastion.example.com", h.HostName)
+	assert.Equal(t, "", h.ProxyJump)
+}
+
+func TestLoad_Include(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "config.d"), 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.d", "db"), []byte("Host db\n  HostName db.internal\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config"), []byte("Include config.d/*\n\nHost *\n  User admin\n"), 0600))
+
+	cfg, err := sshconfig.Load(filepath.Join(dir, "config"), filepath.Join(dir, "missing"))
+	require.NoError(t, err)
+
+	h := cfg.Lookup("db")
+	assert.Equal(t, "db.internal", h.HostName)
+	assert.Equal(t, "admin", h.User)
+}
@@ -0,0 +1,163 @@
+package stream
+
+import (
+	"context"
+	"dumper/pkg/logging"
+	"dumper/pkg/utils/retry"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Opener starts reading a file at offset.
+type Opener func(offset int64) (io.Reader, func() error, error)
+
+// resumeReader hands out a remote file and, when the transfer breaks before
+// size bytes arrived, opens it again at the last byte handed out. Consumers
+// like the checksum, the cipher and the storage writers never see the drop,
+// so uploads carry on instead of starting over.
+type resumeReader struct {
+	ctx     context.Context
+	open    Opener
+	name    string
+	size    int64
+	retries int
+
+	offset    int64
+	cur       io.Reader
+	closeCur  func() error
+	stalled   int
+	lastStart int64
+}
+
+// Resumable reads name through open, resuming up to retries times in a row
+// without progress. size is the expected length, when it is unknown only a
+// failed read or a failed remote command triggers a resume.
+func Resumable(ctx context.Context, open Opener, name string, size int64, retries int) (io.Reader, func() error, error) {
+	r := &resumeReader{
+		ctx:       ctx,
+		open:      open,
+		name:      name,
+		size:      size,
+		retries:   retries,
+		lastStart: -1,
+	}
+
+	if err := r.start(); err != nil {
+		return nil, nil, err
+	}
+
+	return r, r.close, nil
+}
+
+func (r *resumeReader) Read(p []byte) (int, error) {
+	for {
+		if r.cur == nil {
+			if err := r.resume(); err != nil {
+				return 0, err
+			}
+		}
+
+		n, err := r.cur.Read(p)
+		r.offset += int64(n)
+
+		if err == nil {
+			return n, nil
+		}
+
+		closeErr := r.close()
+		if errors.Is(err, io.EOF) && closeErr == nil && (r.size <= 0 || r.offset >= r.size) {
+			return n, io.EOF
+		}
+
+		if errors.Is(err, io.EOF) {
+			err = closeErr
+			if err == nil {
+				err = fmt.Errorf("transfer ended at byte %d of %d", r.offset, r.size)
+			}
+		}
+
+		logging.L(r.ctx).Warn(
+			"Transfer interrupted",
+			logging.StringAttr("name", r.name),
+			logging.Int64Attr("offset", r.offset),
+			logging.ErrAttr(err),
+		)
+
+		if n > 0 {
+			return n, nil
+		}
+	}
+}
+
+// resume opens the file again at the current offset. Drops and failed
+// opens that bring no new bytes share one budget of retries attempts, each
+// waiting out the retry backoff.
+func (r *resumeReader) resume() error {
+	for {
+		if r.offset == r.lastStart {
+			r.stalled++
+		} else {
+			r.stalled = 0
+		}
+
+		if r.stalled >= r.retries {
+			return fmt.Errorf("transfer of %s failed at byte %d after %d attempts", r.name, r.offset, r.retries)
+		}
+
+		if r.stalled > 0 {
+			select {
+			case <-r.ctx.Done():
+				return fmt.Errorf("transfer cancelled: %w", r.ctx.Err())
+			case <-time.After(retry.BackoffFunc(r.stalled)):
+			}
+		}
+
+		logging.L(r.ctx).Info(
+			"Resuming transfer",
+			logging.StringAttr("name", r.name),
+			logging.Int64Attr("offset", r.offset),
+		)
+
+		err := r.start()
+		if err == nil {
+			return nil
+		}
+
+		logging.L(r.ctx).Warn(
+			"Failed to resume transfer",
+			logging.StringAttr("name", r.name),
+			logging.Int64Attr("offset", r.offset),
+			logging.ErrAttr(err),
+		)
+
+		// a failed open made no progress either
+		r.lastStart = r.offset
+	}
+}
+
+func (r *resumeReader) start() error {
+	reader, closeFunc, err := r.open(r.offset)
+	if err != nil {
+		return err
+	}
+
+	r.cur = reader
+	r.closeCur = closeFunc
+	r.lastStart = r.offset
+
+	return nil
+}
+
+func (r *resumeReader) close() error {
+	if r.cur == nil {
+		return nil
+	}
+
+	err := r.closeCur()
+	r.cur = nil
+	r.closeCur = nil
+
+	return err
+}
@@ -0,0 +1,120 @@
+package stream_test
+
+import (
+	"context"
+	"dumper/pkg/utils/retry"
+	"dumper/pkg/utils/stream"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flakyFile serves data but cuts every transfer after cut bytes, failing
+// the remote command or ending the stream early.
+type flakyFile struct {
+	data    string
+	cut     int
+	drops   int
+	offsets []int64
+	silent  bool
+}
+
+func (f *flakyFile) open(offset int64) (io.Reader, func() error, error) {
+	f.offsets = append(f.offsets, offset)
+	rest := f.data[offset:]
+
+	if f.drops == 0 || len(rest) <= f.cut {
+		return strings.NewReader(rest), func() error { return nil }, nil
+	}
+
+	f.drops--
+	if f.silent {
+		return strings.NewReader(rest[:f.cut]), func() error { return nil }, nil
+	}
+	return strings.NewReader(rest[:f.cut]), func() error { return errors.New("connection lost") }, nil
+}
+
+func fastBackoff(t *testing.T) {
+	orig := retry.BackoffFunc
+	retry.BackoffFunc = func(int) time.Duration { return time.Millisecond }
+	t.Cleanup(func() { retry.BackoffFunc = orig })
+}
+
+func TestResumable_ResumesAtOffset(t *testing.T) {
+	fastBackoff(t)
+	file := &flakyFile{data: strings.Repeat("0123456789", 10), cut: 30, drops: 2}
+
+	r, closeFunc, err := stream.Resumable(context.Background(), file.open, "dump.sql", 100, 3)
+	require.NoError(t, err)
+
+	data, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.NoError(t, closeFunc())
+
+	assert.Equal(t, file.data, string(data))
+	assert.Equal(t, []int64{0, 30, 60}, file.offsets)
+}
+
+func TestResumable_ShortStreamWithoutError(t *testing.T) {
+	fastBackoff(t)
+	file := &flakyFile{data: strings.Repeat("x", 100), cut: 40, drops: 1, silent: true}
+
+	r, _, err := stream.Resumable(context.Background(), file.open, "dump.sql", 100, 3)
+	require.NoError(t, err)
+
+	data, err := io.ReadAll(r)
+	require.NoError(t, err)
+
+	assert.Equal(t, file.data, string(data))
+	assert.Equal(t, []int64{0, 40}, file.offsets)
+}
+
+func TestResumable_GivesUpWithoutProgress(t *testing.T) {
+	fastBackoff(t)
+	file := &flakyFile{data: strings.Repeat("x", 100), cut: 0, drops: 100}
+
+	r, _, err := stream.Resumable(context.Background(), file.open, "dump.sql", 100, 3)
+	require.NoError(t, err)
+
+	_, err = io.ReadAll(r)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed at byte 0")
+	assert.Len(t, file.offsets, 3)
+}
+
+func TestResumable_ResumeOpenFails(t *testing.T) {
+	fastBackoff(t)
+	file := &flakyFile{data: strings.Repeat("x", 100), cut: 40, drops: 1}
+
+	opens := 0
+	open := func(offset int64) (io.Reader, func() error, error) {
+		opens++
+		if opens > 1 {
+			return nil, nil, errors.New("no session")
+		}
+		return file.open(offset)
+	}
+
+	r, _, err := stream.Resumable(context.Background(), open, "dump.sql", 100, 3)
+	require.NoError(t, err)
+
+	_, err = io.ReadAll(r)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed at byte 40 after 3 attempts")
+	// the first open, then one budget of 3 attempts at byte 40
+	assert.Equal(t, 4, opens)
+}
+
+func TestResumable_OpenFails(t *testing.T) {
+	open := func(int64) (io.Reader, func() error, error) {
+		return nil, nil, errors.New("no session")
+	}
+
+	_, _, err := stream.Resumable(context.Background(), open, "dump.sql", 10, 3)
+	assert.Error(t, err)
+}
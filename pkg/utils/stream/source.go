@@ -0,0 +1,159 @@
+package stream
+
+import (
+	"context"
+	"dumper/internal/connect"
+	"dumper/pkg/logging"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pkg/sftp"
+)
+
+// Source is the dump file on the server, readable from any offset so an
+// interrupted transfer can resume.
+type Source interface {
+	Size() (int64, error)
+	Open(offset int64) (io.Reader, func() error, error)
+}
+
+// NewSource reads path over the SFTP subsystem, or with cat over an exec
+// session when the server does not offer SFTP.
+func NewSource(ctx context.Context, conn *connect.Connect, path string) Source {
+	client, err := conn.NewSFTP()
+	if err != nil {
+		logging.L(ctx).Warn(
+			"SFTP subsystem is not available, reading the dump with cat",
+			logging.StringAttr("name", path),
+			logging.ErrAttr(err),
+		)
+		return NewCatSource(conn, path)
+	}
+
+	src := NewSFTPSource(func() (*sftp.Client, error) { return conn.NewSFTP() }, path)
+	src.client = client
+
+	return src
+}
+
+// SFTPSource reads with File.WriteTo, which keeps many read requests in
+// flight and so fills high-latency links that a single stream can not.
+type SFTPSource struct {
+	newClient func() (*sftp.Client, error)
+	path      string
+
+	mu     sync.Mutex
+	client *sftp.Client
+}
+
+func NewSFTPSource(newClient func() (*sftp.Client, error), path string) *SFTPSource {
+	return &SFTPSource{newClient: newClient, path: path}
+}
+
+// connect hands out the probe client of NewSource once, then new ones.
+func (s *SFTPSource) connect() (*sftp.Client, error) {
+	s.mu.Lock()
+	client := s.client
+	s.client = nil
+	s.mu.Unlock()
+
+	if client != nil {
+		return client, nil
+	}
+
+	return s.newClient()
+}
+
+func (s *SFTPSource) Size() (int64, error) {
+	client, err := s.connect()
+	if err != nil {
+		return 0, err
+	}
+	defer client.Close()
+
+	info, err := client.Stat(s.path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to stat %s: %w", s.path, err)
+	}
+
+	return info.Size(), nil
+}
+
+func (s *SFTPSource) Open(offset int64) (io.Reader, func() error, error) {
+	client, err := s.connect()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to start SFTP session: %w", err)
+	}
+
+	file, err := client.Open(s.path)
+	if err != nil {
+		_ = client.Close()
+		return nil, nil, fmt.Errorf("failed to open %s: %w", s.path, err)
+	}
+
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		_ = file.Close()
+		_ = client.Close()
+		return nil, nil, fmt.Errorf("failed to seek %s: %w", s.path, err)
+	}
+
+	pr, pw := io.Pipe()
+	done := make(chan error, 1)
+
+	go func() {
+		_, err := file.WriteTo(pw)
+		_ = pw.CloseWithError(err)
+		done <- err
+	}()
+
+	closeFunc := func() error {
+		_ = pr.Close()
+		err := <-done
+		_ = file.Close()
+		_ = client.Close()
+
+		if errors.Is(err, io.ErrClosedPipe) {
+			return nil
+		}
+		return err
+	}
+
+	return pr, closeFunc, nil
+}
+
+// CatSource reads with cat, and tail -c to resume, over exec sessions.
+type CatSource struct {
+	conn *connect.Connect
+	path string
+}
+
+func NewCatSource(conn *connect.Connect, path string) *CatSource {
+	return &CatSource{conn: conn, path: path}
+}
+
+func (s *CatSource) Size() (int64, error) {
+	out, err := s.conn.RunCommand(fmt.Sprintf("wc -c < %s", s.path))
+	if err != nil {
+		return 0, err
+	}
+
+	size, err := strconv.ParseInt(strings.TrimSpace(out), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read size of %s: %w", s.path, err)
+	}
+
+	return size, nil
+}
+
+func (s *CatSource) Open(offset int64) (io.Reader, func() error, error) {
+	cmd := fmt.Sprintf("cat %s", s.path)
+	if offset > 0 {
+		cmd = fmt.Sprintf("tail -c +%d %s", offset+1, s.path)
+	}
+
+	return sshCommand(s.conn, cmd)
+}
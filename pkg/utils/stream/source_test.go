@@ -0,0 +1,83 @@
+package stream_test
+
+import (
+	"dumper/pkg/utils/stream"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pkg/sftp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pipeConn joins the two halves of an in-process SFTP connection.
+type pipeConn struct {
+	io.Reader
+	io.WriteCloser
+}
+
+func newSFTPClient() func() (*sftp.Client, error) {
+	return func() (*sftp.Client, error) {
+		serverRead, clientWrite := io.Pipe()
+		clientRead, serverWrite := io.Pipe()
+
+		server, err := sftp.NewServer(pipeConn{serverRead, serverWrite})
+		if err != nil {
+			return nil, err
+		}
+		go func() {
+			_ = server.Serve()
+			_ = server.Close()
+		}()
+
+		return sftp.NewClientPipe(clientRead, clientWrite)
+	}
+}
+
+func TestSFTPSource(t *testing.T) {
+	data := strings.Repeat("0123456789abcdef", 64*1024)
+	path := filepath.Join(t.TempDir(), "dump.sql")
+	require.NoError(t, os.WriteFile(path, []byte(data), 0600))
+
+	src := stream.NewSFTPSource(newSFTPClient(), path)
+
+	size, err := src.Size()
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(data)), size)
+
+	r, closeFunc, err := src.Open(0)
+	require.NoError(t, err)
+	got, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.NoError(t, closeFunc())
+	assert.Equal(t, data, string(got))
+
+	r, closeFunc, err = src.Open(1000)
+	require.NoError(t, err)
+	got, err = io.ReadAll(r)
+	require.NoError(t, err)
+	require.NoError(t, closeFunc())
+	assert.Equal(t, data[1000:], string(got))
+}
+
+func TestSFTPSource_CloseEarly(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dump.sql")
+	require.NoError(t, os.WriteFile(path, []byte(strings.Repeat("x", 1<<20)), 0600))
+
+	r, closeFunc, err := stream.NewSFTPSource(newSFTPClient(), path).Open(0)
+	require.NoError(t, err)
+
+	_, err = r.Read(make([]byte, 10))
+	require.NoError(t, err)
+	assert.NoError(t, closeFunc())
+}
+
+func TestSFTPSource_Missing(t *testing.T) {
+	src := stream.NewSFTPSource(newSFTPClient(), filepath.Join(t.TempDir(), "missing"))
+
+	_, _, err := src.Open(0)
+	assert.Error(t, err)
+}
@@ -3,10 +3,17 @@ package stream
 import (
 	"context"
 	"dumper/internal/connect"
+	"dumper/internal/domain/config/encrypt"
+	storageDomain "dumper/internal/domain/storage"
+	"dumper/pkg/utils/aesstream"
+	"dumper/pkg/utils/age"
+	"dumper/pkg/utils/checksum"
 	"dumper/pkg/utils/progress"
+	"errors"
 	"fmt"
 	"io"
 	"path/filepath"
+	"strings"
 )
 
 func PipeReader(
@@ -34,7 +41,7 @@ func PipeReader(
 
 				if gp, ok := ctx.Value("globalProgress").(*progress.GlobProgress); ok {
 					gp.Add(int64(n))
-				} else {
+				} else if fileSize > 0 {
 					progress.Progress(uploaded, fileSize)
 				}
 
@@ -57,12 +64,7 @@ func PipeReader(
 	return pr
 }
 
-func SSHStreamer(
-	ctx context.Context,
-	conn *connect.Connect,
-	dumpName string,
-	fileSize int64,
-) (*io.PipeReader, func() error, error) {
+func sshCommand(conn *connect.Connect, cmd string) (io.Reader, func() error, error) {
 	session, err := conn.NewSession()
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to create SSH session: %w", err)
@@ -74,21 +76,167 @@ func SSHStreamer(
 		return nil, nil, fmt.Errorf("failed to get stdout pipe: %w", err)
 	}
 
-	if err := session.Start(fmt.Sprintf("cat %s", dumpName)); err != nil {
+	if err := session.Start(cmd); err != nil {
 		_ = session.Close()
 		return nil, nil, fmt.Errorf("failed to start remote command: %w", err)
 	}
 
-	pr := PipeReader(ctx, stdout, fileSize)
-
 	closeFunc := func() error {
-		_ = session.Wait()
-		return session.Close()
+		waitErr := session.Wait()
+		closeErr := session.Close()
+		if waitErr != nil {
+			return fmt.Errorf("remote command failed: %w", waitErr)
+		}
+		if errors.Is(closeErr, io.EOF) {
+			return nil
+		}
+		return closeErr
+	}
+
+	return stdout, closeFunc, nil
+}
+
+// Open returns the dump bytes for a storage writer. When the config carries a
+// checksum the stream fails at EOF if the received bytes do not match it.
+// With encryption enabled the dump is encrypted on the way, so only
+// ciphertext reaches the storage, and StoredChecksum is set to its digest.
+func Open(
+	ctx context.Context,
+	config *storageDomain.Config,
+) (*io.PipeReader, func() error, error) {
+	src := config.Reader
+	closeFunc := func() error { return nil }
+
+	if src == nil {
+		source := NewSource(ctx, config.Conn, config.GetSource())
+
+		size := config.FileSize
+		if size <= 0 {
+			size, _ = source.Size()
+		}
+
+		stdout, closeSSH, err := Resumable(ctx, source.Open, config.GetSource(), size, config.GetRetries())
+		if err != nil {
+			return nil, nil, err
+		}
+		src = stdout
+		closeFunc = closeSSH
+	}
+
+	src = checksum.Verify(src, config.Checksum)
+
+	if config.Encrypt.IsEnabled() {
+		encrypted, stop, err := Encrypt(src, config.Encrypt)
+		if err != nil {
+			_ = closeFunc()
+			return nil, nil, err
+		}
+		src = encrypted
+
+		// a storage failing mid-stream leaves the ciphertext unread, so the
+		// encryption is stopped along with the source
+		closeSource := closeFunc
+		closeFunc = func() error {
+			_ = stop()
+			return closeSource()
+		}
+	}
+
+	src = checksum.Track(src, func(sum string) { config.StoredChecksum = sum })
+
+	return PipeReader(ctx, src, config.FileSize), closeFunc, nil
+}
+
+// Encrypt wraps src with the cipher selected by the encrypt config. stop
+// releases the cipher when the reader is abandoned before EOF.
+func Encrypt(src io.Reader, cfg encrypt.Encrypt) (reader io.Reader, stop func() error, err error) {
+	switch cfg.Type {
+	case encrypt.TypeAES:
+		// aesstream encrypts as it is read, there is nothing to stop
+		reader, err = aesstream.Encrypt(src, cfg.Password, cfg.KDF)
+		return reader, func() error { return nil }, err
+	case encrypt.TypeAge:
+		recipients, err := age.ParseRecipients(cfg.Recipients)
+		if err != nil {
+			return nil, nil, err
+		}
+		return age.Encrypt(src, recipients...)
+	default:
+		return nil, nil, fmt.Errorf("unsupported encryption type: %s", cfg.Type)
+	}
+}
+
+func Tee(src io.Reader, writers ...*io.PipeWriter) (int64, error) {
+	alive := make([]bool, len(writers))
+	for i := range alive {
+		alive[i] = true
 	}
 
-	return pr, closeFunc, nil
+	buf := make([]byte, 32*1024)
+	var total int64
+
+	for {
+		n, err := src.Read(buf)
+		if n > 0 {
+			total += int64(n)
+			left := 0
+
+			for i, w := range writers {
+				if !alive[i] {
+					continue
+				}
+				if _, writeErr := w.Write(buf[:n]); writeErr != nil {
+					alive[i] = false
+					continue
+				}
+				left++
+			}
+
+			if left == 0 {
+				return total, fmt.Errorf("no writers left to stream into")
+			}
+		}
+
+		if err == io.EOF {
+			return total, nil
+		}
+		if err != nil {
+			return total, err
+		}
+	}
+}
+
+type readCloser struct {
+	io.Reader
+	closers []func() error
+}
+
+func (r *readCloser) Close() error {
+	var firstErr error
+	for _, closeFunc := range r.closers {
+		if err := closeFunc(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// ReadCloser binds a reader to the cleanup of everything it depends on,
+// closers run in the given order.
+func ReadCloser(r io.Reader, closers ...func() error) io.ReadCloser {
+	return &readCloser{Reader: r, closers: closers}
 }
 
 func TargetPath(dir, dumpName string) string {
 	return filepath.Join(dir, filepath.Base(dumpName))
 }
+
+// ListPrefix is the object key prefix matching files stored by TargetPath
+// in dir, so object storages can list them.
+func ListPrefix(dir string) string {
+	prefix := filepath.ToSlash(filepath.Clean(dir))
+	if prefix == "." {
+		return ""
+	}
+	return strings.TrimSuffix(prefix, "/") + "/"
+}
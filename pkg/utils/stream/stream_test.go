@@ -0,0 +1,145 @@
+package stream_test
+
+import (
+	"bytes"
+	"context"
+	"dumper/internal/domain/config/encrypt"
+	storageDomain "dumper/internal/domain/storage"
+	"dumper/pkg/utils/aesstream"
+	"dumper/pkg/utils/checksum"
+	"dumper/pkg/utils/stream"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTee_CopiesToAllWriters(t *testing.T) {
+	payload := strings.Repeat("dump-data", 10000)
+
+	pr1, pw1 := io.Pipe()
+	pr2, pw2 := io.Pipe()
+
+	var out1, out2 bytes.Buffer
+	wg := &sync.WaitGroup{}
+	wg.Add(2)
+	go func() { defer wg.Done(); _, _ = io.Copy(&out1, pr1) }()
+	go func() { defer wg.Done(); _, _ = io.Copy(&out2, pr2) }()
+
+	total, err := stream.Tee(strings.NewReader(payload), pw1, pw2)
+	_ = pw1.Close()
+	_ = pw2.Close()
+	wg.Wait()
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(payload)), total)
+	assert.Equal(t, payload, out1.String())
+	assert.Equal(t, payload, out2.String())
+}
+
+func TestTee_DropsFailedWriter(t *testing.T) {
+	payload := strings.Repeat("x", 100000)
+
+	pr1, pw1 := io.Pipe()
+	pr2, pw2 := io.Pipe()
+	_ = pr1.CloseWithError(errors.New("storage failed"))
+
+	var out bytes.Buffer
+	done := make(chan struct{})
+	go func() { defer close(done); _, _ = io.Copy(&out, pr2) }()
+
+	total, err := stream.Tee(strings.NewReader(payload), pw1, pw2)
+	_ = pw2.Close()
+	<-done
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(payload)), total)
+	assert.Equal(t, payload, out.String())
+}
+
+func TestTee_NoWritersLeft(t *testing.T) {
+	pr, pw := io.Pipe()
+	_ = pr.CloseWithError(errors.New("storage failed"))
+
+	_, err := stream.Tee(strings.NewReader("data"), pw)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no writers left")
+}
+
+func TestListPrefix(t *testing.T) {
+	tests := map[string]string{
+		"":           "",
+		".":          "",
+		"./":         "",
+		"backups":    "backups/",
+		"backups/":   "backups/",
+		"/backups":   "/backups/",
+		"a/b/../c//": "a/c/",
+	}
+
+	for dir, want := range tests {
+		assert.Equal(t, want, stream.ListPrefix(dir), dir)
+	}
+}
+
+func TestOpen_VerifiesChecksum(t *testing.T) {
+	sum, err := checksum.Sum(strings.NewReader("dump-data"))
+	require.NoError(t, err)
+
+	pr, closeFunc, err := stream.Open(context.Background(), &storageDomain.Config{
+		Reader:   strings.NewReader("dump-data"),
+		Checksum: sum,
+	})
+	require.NoError(t, err)
+	defer closeFunc()
+
+	data, err := io.ReadAll(pr)
+	require.NoError(t, err)
+	assert.Equal(t, "dump-data", string(data))
+
+	pr, closeFunc, err = stream.Open(context.Background(), &storageDomain.Config{
+		Reader:   strings.NewReader("corrupted"),
+		Checksum: sum,
+	})
+	require.NoError(t, err)
+	defer closeFunc()
+
+	_, err = io.ReadAll(pr)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "checksum mismatch")
+}
+
+func TestOpen_Encrypts(t *testing.T) {
+	enabled := true
+	config := &storageDomain.Config{
+		Reader: strings.NewReader("dump-data"),
+		Encrypt: encrypt.Encrypt{
+			Type:     encrypt.TypeAES,
+			Password: "secret",
+			Enabled:  &enabled,
+		},
+	}
+
+	pr, closeFunc, err := stream.Open(context.Background(), config)
+	require.NoError(t, err)
+	defer closeFunc()
+
+	data, err := io.ReadAll(pr)
+	require.NoError(t, err)
+	assert.True(t, aesstream.IsEncrypted(data))
+
+	sum, err := checksum.Sum(bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Equal(t, sum, config.StoredChecksum)
+
+	plain, err := aesstream.Decrypt(bytes.NewReader(data), "secret")
+	require.NoError(t, err)
+	got, err := io.ReadAll(plain)
+	require.NoError(t, err)
+	assert.Equal(t, "dump-data", string(got))
+}
@@ -3,6 +3,7 @@ package template
 import (
 	"fmt"
 	"path/filepath"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -43,6 +44,30 @@ func GetTemplateFileName(data TemplateData) string {
 	return strings.ReplaceAll(result, " ", "_")
 }
 
+// GetTemplatePattern matches every file name the template can produce for
+// the server and database, with any dump extension appended.
+func GetTemplatePattern(data TemplateData) *regexp.Regexp {
+	if data.Template == "" {
+		data.Template = "{%srv%}_{%db%}_{%date%}"
+	}
+
+	replacements := map[string]string{
+		regexp.QuoteMeta("{%srv%}"):      regexp.QuoteMeta(data.Server),
+		regexp.QuoteMeta("{%db%}"):       regexp.QuoteMeta(data.Database),
+		regexp.QuoteMeta("{%date%}"):     `\d{4}\.\d{2}\.\d{2}`,
+		regexp.QuoteMeta("{%time%}"):     `\d{2}-\d{2}-\d{2}`,
+		regexp.QuoteMeta("{%datetime%}"): `\d{4}\.\d{2}\.\d{2}_\d{2}-\d{2}-\d{2}`,
+		regexp.QuoteMeta("{%ts%}"):       `\d+`,
+	}
+
+	pattern := regexp.QuoteMeta(strings.ReplaceAll(data.Template, " ", "_"))
+	for placeholder, value := range replacements {
+		pattern = strings.ReplaceAll(pattern, placeholder, value)
+	}
+
+	return regexp.MustCompile(`^` + pattern + `(\..+)?$`)
+}
+
 func GetFullPath(parts ...string) string {
 	return filepath.Clean(filepath.Join(parts...))
 }
@@ -59,3 +59,46 @@ func TestGetTemplateFileName(t *testing.T) {
 		})
 	}
 }
+
+func TestGetTemplatePattern(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    TemplateData
+		match   []string
+		noMatch []string
+	}{
+		{
+			name:    "default template",
+			data:    TemplateData{Server: "web", Database: "site"},
+			match:   []string{"web_site_2025.10.31.sql", "web_site_2025.10.31.sql.gz.enc"},
+			noMatch: []string{"web_site_2_2025.10.31.sql", "web_site_latest.sql", "other_site_2025.10.31.sql"},
+		},
+		{
+			name:    "time and timestamp placeholders",
+			data:    TemplateData{Server: "web", Database: "site.db", Template: "{%db%}-{%time%}-{%ts%}"},
+			match:   []string{"site.db-12-34-05-1761914045.dump"},
+			noMatch: []string{"siteXdb-12-34-05-1761914045.dump", "site.db-12-34-05-.dump"},
+		},
+		{
+			name:  "spaces are replaced like in file names",
+			data:  TemplateData{Server: "web", Database: "site", Template: "backup {%srv%} {%datetime%}"},
+			match: []string{"backup_web_2025.10.31_12-34-05.tar"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pattern := GetTemplatePattern(tt.data)
+			for _, name := range tt.match {
+				if !pattern.MatchString(name) {
+					t.Errorf("expected %q to match %s", name, pattern)
+				}
+			}
+			for _, name := range tt.noMatch {
+				if pattern.MatchString(name) {
+					t.Errorf("expected %q not to match %s", name, pattern)
+				}
+			}
+		})
+	}
+}